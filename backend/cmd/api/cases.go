@@ -0,0 +1,207 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mike5tew/humanos/internal/safeguarding"
+)
+
+// CaseActionRequest is the body for assign, resolve and reopen
+type CaseActionRequest struct {
+	StaffID  string `json:"staff_id"`           // Reviewer performing the action
+	Assignee string `json:"assignee,omitempty"` // assign: defaults to staff_id
+	Outcome  string `json:"outcome,omitempty"`  // resolve: outcome code
+	Notes    string `json:"notes,omitempty"`    // resolve: reviewer notes; reopen: reason
+}
+
+// CaseDetailResponse is everything a reviewer needs to assess a case
+type CaseDetailResponse struct {
+	Case       *safeguarding.Case          `json:"case"`
+	AuditTrail []safeguarding.AuditEntry   `json:"audit_trail"`
+	Deliveries []*safeguarding.OutboxEntry `json:"deliveries"`
+}
+
+// requireStaff guards case routes with SAFEGUARDING_STAFF_TOKEN
+// Without a token configured the routes stay closed
+func requireStaff(next http.Handler) http.Handler {
+	token := os.Getenv("SAFEGUARDING_STAFF_TOKEN")
+	if token == "" {
+		log.Printf("SAFEGUARDING_STAFF_TOKEN not set - case management API disabled")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			http.Error(w, "Case management not configured", http.StatusServiceUnavailable)
+			return
+		}
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleListCases lists cases; ?status=open|assigned|resolved|active|all (default active)
+func (s *Server) handleListCases(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "active"
+	case "all":
+		status = ""
+	}
+
+	cases, err := s.cases.List(status)
+	if err != nil {
+		log.Printf("Error listing cases: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cases)
+}
+
+func (s *Server) handleGetCase(w http.ResponseWriter, r *http.Request) {
+	c, err := s.cases.Get(chi.URLParam(r, "caseId"))
+	if errors.Is(err, safeguarding.ErrCaseNotFound) {
+		http.Error(w, "Case not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading case: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	trail, err := s.cases.Trail(c.ID)
+	if err != nil {
+		log.Printf("Error reading audit trail for case %s: %v", c.ID, err)
+	}
+	deliveries, err := s.outbox.Deliveries(c.ID)
+	if err != nil {
+		log.Printf("Error reading deliveries for case %s: %v", c.ID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(CaseDetailResponse{
+		Case:       c,
+		AuditTrail: trail,
+		Deliveries: deliveries,
+	})
+}
+
+func (s *Server) handleAssignCase(w http.ResponseWriter, r *http.Request) {
+	s.caseAction(w, r, func(id string, req CaseActionRequest) (*safeguarding.Case, error) {
+		assignee := req.Assignee
+		if assignee == "" {
+			assignee = req.StaffID
+		}
+		return s.cases.Assign(id, assignee, req.StaffID)
+	})
+}
+
+func (s *Server) handleResolveCase(w http.ResponseWriter, r *http.Request) {
+	s.caseAction(w, r, func(id string, req CaseActionRequest) (*safeguarding.Case, error) {
+		return s.cases.Resolve(id, req.Outcome, req.Notes, req.StaffID)
+	})
+}
+
+func (s *Server) handleReopenCase(w http.ResponseWriter, r *http.Request) {
+	s.caseAction(w, r, func(id string, req CaseActionRequest) (*safeguarding.Case, error) {
+		return s.cases.Reopen(id, req.Notes, req.StaffID)
+	})
+}
+
+// handleCaseTuning reports how reviewer outcomes are adjusting detection
+func (s *Server) handleCaseTuning(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.cases.Stats())
+}
+
+// handleStudentSignals shows the longitudinal history behind persistent-pattern cases
+func (s *Server) handleStudentSignals(w http.ResponseWriter, r *http.Request) {
+	history, err := s.signals.History(chi.URLParam(r, "studentId"))
+	if err != nil {
+		log.Printf("Error loading signal history: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// caseAction decodes a reviewer action and maps case errors to status codes
+func (s *Server) caseAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	apply func(id string, req CaseActionRequest) (*safeguarding.Case, error),
+) {
+	var req CaseActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.StaffID == "" {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	c, err := apply(chi.URLParam(r, "caseId"), req)
+	switch {
+	case errors.Is(err, safeguarding.ErrCaseNotFound):
+		http.Error(w, "Case not found", http.StatusNotFound)
+		return
+	case errors.Is(err, safeguarding.ErrInvalidOutcome):
+		http.Error(w, "Unknown outcome code", http.StatusBadRequest)
+		return
+	case errors.Is(err, safeguarding.ErrCaseResolved):
+		http.Error(w, "Case is already resolved", http.StatusConflict)
+		return
+	case errors.Is(err, safeguarding.ErrCaseNotResolved):
+		http.Error(w, "Case is not resolved", http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("Error updating case: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// openCaseManager keeps cases alongside the outbox and shares its audit trail
+func openCaseManager(outbox *safeguarding.Outbox) (*safeguarding.CaseManager, safeguarding.CaseStore, error) {
+	var caseStore safeguarding.CaseStore
+	if os.Getenv("PROFILE_STORE") == "memory" {
+		caseStore = safeguarding.NewMemoryCaseStore()
+	} else {
+		boltStore, err := safeguarding.NewBoltCaseStore(getEnvOrDefault("SAFEGUARDING_CASES_DB_PATH", "data/safeguarding_cases.db"))
+		if err != nil {
+			return nil, nil, err
+		}
+		caseStore = boltStore
+	}
+
+	cases, err := safeguarding.NewCaseManager(caseStore, outbox.Audit())
+	if err != nil {
+		caseStore.Close()
+		return nil, nil, err
+	}
+	return cases, caseStore, nil
+}
+
+// openSignalStore keeps signal histories alongside cases
+func openSignalStore() (safeguarding.SignalStore, error) {
+	if os.Getenv("PROFILE_STORE") == "memory" {
+		return safeguarding.NewMemorySignalStore(), nil
+	}
+	return safeguarding.NewBoltSignalStore(getEnvOrDefault("SAFEGUARDING_SIGNALS_DB_PATH", "data/safeguarding_signals.db"))
+}
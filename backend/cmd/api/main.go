@@ -1,32 +1,44 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
 	"github.com/mike5tew/humanos/internal/coach"
+	"github.com/mike5tew/humanos/internal/curriculum"
 	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/generation"
+	"github.com/mike5tew/humanos/internal/integration"
+	"github.com/mike5tew/humanos/internal/mastery"
+	"github.com/mike5tew/humanos/internal/nlp"
+	"github.com/mike5tew/humanos/internal/playbreak"
+	"github.com/mike5tew/humanos/internal/realtime"
+	"github.com/mike5tew/humanos/internal/rewards"
+	"github.com/mike5tew/humanos/internal/safeguarding"
+	"github.com/mike5tew/humanos/internal/store"
 )
 
 type Server struct {
 	orchestrator *coach.Orchestrator
-	profiles     map[string]*StudentProfile // In-memory profile storage (for MVP)
-}
-
-// StudentProfile represents a student's current state
-type StudentProfile struct {
-	StudentID       string         `json:"student_id"`
-	Age             int            `json:"age"`
-	BrainState      etp.BrainState `json:"brain_state"`
-	ActiveBarriers  []string       `json:"active_barriers"`
-	RewardsEarned   int            `json:"rewards_earned"`
-	PlayBreakStage  string         `json:"play_break_stage"`
-	LastInteraction string         `json:"last_interaction"`
+	profiles     store.StudentProfileRepository
+	hub          *realtime.Hub
+	playBreaks   *playbreak.Engine
+	rewards      *rewards.Ledger
+	outbox       *safeguarding.Outbox
+	cases        *safeguarding.CaseManager
+	signals      *safeguarding.Tracker
+	chisg        *integration.CHISGClient
+	mastery      *mastery.Estimator
+	embeddings   string // Classifier in use, or "off"
 }
 
 func main() {
@@ -37,6 +49,7 @@ func main() {
 	barriersPath := getEnvOrDefault("BARRIERS_PATH", "../../shared/schemas/barriers.json")
 	traumaPath := getEnvOrDefault("TRAUMA_PATH", "../../shared/schemas/trauma_detection.json")
 	agePath := getEnvOrDefault("AGE_PATH", "../../shared/schemas/age_appropriateness.json")
+	curriculumPath := getEnvOrDefault("CURRICULUM_PATH", "../../shared/schemas/curriculum_taxonomy.json")
 
 	// Initialize orchestrator
 	orchestrator, err := coach.NewOrchestrator(barriersPath, traumaPath, agePath)
@@ -44,11 +57,118 @@ func main() {
 		log.Fatalf("Failed to initialize orchestrator: %v", err)
 	}
 
+	// Curriculum taxonomy: which learning topics a message is about
+	taxonomy, err := curriculum.Load(curriculumPath)
+	if err != nil {
+		log.Fatalf("Failed to load curriculum taxonomy: %v", err)
+	}
+	orchestrator.UseCurriculum(taxonomy)
+
+	// Embeddings: nearest-neighbour barrier and ETP matching over the barriers'
+	// verbal manifestations, blended with the regex rules
+	classifier, err := nlp.New(nlp.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to configure embeddings: %v", err)
+	}
+	embeddings := "off"
+	if err := orchestrator.UseClassifier(context.Background(), classifier); err != nil {
+		log.Printf("⚠️ Embedding classifier unavailable, detecting barriers with regex only: %v", err)
+	} else if classifier != nil {
+		embeddings = classifier.Name()
+	}
+	log.Printf("🔤 Embeddings: %s", embeddings)
+
+	// Pipeline profile: standard, or agentic (adds CHISG knowledge analysis)
+	if err := orchestrator.UseProfile(getEnvOrDefault("COACH_PROFILE", coach.ProfileStandard)); err != nil {
+		log.Fatalf("Failed to select coach profile: %v", err)
+	}
+	log.Printf("🧭 Coach pipeline (%s): %s", orchestrator.Profile(),
+		strings.Join(orchestrator.Pipeline(orchestrator.Profile()).Stages(), " → "))
+
+	// Initialize profile store (PROFILE_STORE=bolt|mongo|memory)
+	profiles, err := store.Open(context.Background(), store.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to open profile store: %v", err)
+	}
+	defer profiles.Close()
+
+	// Topic mastery is stored with the profile and pitches CHISG and questions
+	orchestrator.UseMasterySource(profileMastery{profiles: profiles})
+
+	// Reward ledger: signed, expiring game unlock codes
+	rewardLedger, err := openRewardLedger()
+	if err != nil {
+		log.Fatalf("Failed to open reward ledger: %v", err)
+	}
+	defer rewardLedger.Close()
+	orchestrator.UseRewardLedger(rewardLedger)
+
+	// Safeguarding outbox: alerts are persisted, retried and audited
+	outbox, err := openSafeguardingOutbox()
+	if err != nil {
+		log.Fatalf("Failed to open safeguarding outbox: %v", err)
+	}
+	defer outbox.Close()
+	orchestrator.UseSafeguardingOutbox(outbox)
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	defer stopOutbox()
+	go outbox.Run(outboxCtx)
+
+	// Case management: human review of escalated alerts
+	cases, caseStore, err := openCaseManager(outbox)
+	if err != nil {
+		log.Fatalf("Failed to open safeguarding cases: %v", err)
+	}
+	defer caseStore.Close()
+	orchestrator.UseCaseManager(cases)
+
+	// Longitudinal tracking: mild indicators accumulate per student
+	signalStore, err := openSignalStore()
+	if err != nil {
+		log.Fatalf("Failed to open safeguarding signals: %v", err)
+	}
+	defer signalStore.Close()
+	signals := safeguarding.NewTracker(signalStore, orchestrator.SafeguardingTracking())
+	orchestrator.UseSignalTracker(signals)
+
+	generator, err := generation.New(generation.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to configure response generator: %v", err)
+	}
+	orchestrator.UseResponseGenerator(generator)
+	log.Printf("✍️ Response generator: %s", generator.Name())
+
+	// CHISG: deadlines, a circuit breaker and a TTL cache keep a slow or
+	// failing knowledge graph from stalling coaching
+	chisgConfig := integration.CHISGConfigFromEnv()
+	chisg := integration.NewCHISGClient(chisgConfig)
+	orchestrator.UseKnowledgeSource(chisg)
+	log.Printf("📚 CHISG: %s (timeout %s, cache %s)", chisgConfig.BaseURL, chisgConfig.Timeout, chisgConfig.CacheTTL)
+
 	server := &Server{
 		orchestrator: orchestrator,
-		profiles:     make(map[string]*StudentProfile),
+		profiles:     profiles,
+		playBreaks:   playbreak.NewEngine(orchestrator.BarrierProfiles()),
+		rewards:      rewardLedger,
+		outbox:       outbox,
+		cases:        cases,
+		signals:      signals,
+		chisg:        chisg,
+		mastery:      orchestrator.Mastery(),
+		embeddings:   embeddings,
 	}
 
+	// Sessions are in memory: idle ones are ended and old ones evicted
+	sessionsCtx, stopSessions := context.WithCancel(context.Background())
+	defer stopSessions()
+	go orchestrator.Sessions().Run(sessionsCtx)
+
+	// Real-time stream: heartbeats, resume and server nudges
+	server.hub = realtime.NewHub(server.processMessage, realtime.ConfigFromEnv())
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	defer stopHub()
+	go server.hub.Run(hubCtx)
+
 	// Setup router
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -58,8 +178,27 @@ func main() {
 
 	// Routes
 	r.Post("/api/coach/message", server.handleCoachMessage)
+	r.Post("/api/coach/agentic/message", server.handleAgenticMessage)
 	r.Get("/api/student/{studentId}/profile", server.handleGetStudentProfile)
+	r.Post("/api/session/start", server.handleStartSession)
+	r.Get("/api/session/{sessionId}", server.handleGetSession)
+	r.Post("/api/session/{sessionId}/end", server.handleEndSession)
 	r.Get("/api/health", server.handleHealth)
+	r.Get("/ws/{studentId}", server.handleWebSocket)
+	r.Post("/api/rewards/redeem", server.handleRedeemReward)
+	r.Get("/api/rewards/validate", server.handleValidateReward)
+
+	// Safeguarding case management (staff only)
+	r.Route("/api/safeguarding", func(r chi.Router) {
+		r.Use(requireStaff)
+		r.Get("/cases", server.handleListCases)
+		r.Get("/cases/{caseId}", server.handleGetCase)
+		r.Post("/cases/{caseId}/assign", server.handleAssignCase)
+		r.Post("/cases/{caseId}/resolve", server.handleResolveCase)
+		r.Post("/cases/{caseId}/reopen", server.handleReopenCase)
+		r.Get("/tuning", server.handleCaseTuning)
+		r.Get("/students/{studentId}/signals", server.handleStudentSignals)
+	})
 
 	// Start server
 	port := getEnvOrDefault("PORT", "8080")
@@ -75,11 +214,40 @@ func main() {
 
 type CoachMessageRequest struct {
 	StudentID string             `json:"student_id"`
+	SessionID string             `json:"session_id,omitempty"` // Optional: defaults to the student's open session
 	Message   string             `json:"message"`
 	Context   etp.StudentContext `json:"context"`
 }
 
+type StartSessionRequest struct {
+	StudentID string `json:"student_id"`
+}
+
+type RedeemRewardRequest struct {
+	Code string `json:"code"`
+}
+
+// RewardStatusResponse tells the game launcher whether to grant access
+type RewardStatusResponse struct {
+	Valid            bool            `json:"valid"`  // Genuine and still usable (unredeemed or playing)
+	Status           string          `json:"status"` // unredeemed, active, used or expired
+	RemainingSeconds int             `json:"remaining_seconds"`
+	Unlock           *rewards.Unlock `json:"unlock,omitempty"`
+}
+
 func (s *Server) handleCoachMessage(w http.ResponseWriter, r *http.Request) {
+	s.serveCoachMessage(w, r, s.orchestrator.Profile())
+}
+
+// handleAgenticMessage always runs the agentic profile, adding CHISG
+// knowledge_context, knowledge_status and framing_strategy to the reply;
+// the same safeguarding, age and offense filters apply, and a safeguarding
+// reply carries none of the knowledge fields
+func (s *Server) handleAgenticMessage(w http.ResponseWriter, r *http.Request) {
+	s.serveCoachMessage(w, r, coach.ProfileAgentic)
+}
+
+func (s *Server) serveCoachMessage(w http.ResponseWriter, r *http.Request, profile string) {
 	var req CoachMessageRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request", http.StatusBadRequest)
@@ -87,86 +255,339 @@ func (s *Server) handleCoachMessage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Process through orchestrator
-	response, err := s.orchestrator.ProcessMessage(req.StudentID, req.Message, req.Context)
+	response, err := s.orchestrator.ProcessProfileMessage(r.Context(), profile, req.SessionID, req.StudentID, req.Message, req.Context)
+	if err == nil {
+		s.saveProfile(r.Context(), req.StudentID, req.Context, response)
+	}
+	if errors.Is(err, coach.ErrSessionNotFound) {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, coach.ErrSessionForbidden) {
+		http.Error(w, "Session belongs to another student", http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, coach.ErrSessionEnded) {
+		http.Error(w, "Session has ended", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		log.Printf("Error processing message: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Update student profile
-	s.updateProfile(req.StudentID, req.Context, response)
+	// Mirror to any open WebSocket so other tabs stay in sync
+	s.hub.Publish(req.StudentID, response)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// processMessage runs the coach on the student's open session and saves the profile
+// Used by the WebSocket path; ctx ends with the connection or the hub
+func (s *Server) processMessage(
+	ctx context.Context,
+	studentID string,
+	message string,
+	student etp.StudentContext,
+) (*coach.CoachResponse, error) {
+	response, err := s.orchestrator.ProcessProfileMessage(ctx, s.orchestrator.Profile(), "", studentID, message, student)
+	if err != nil {
+		return nil, err
+	}
+	s.saveProfile(ctx, studentID, student, response)
+	return response, nil
+}
+
+// profileMastery serves stored topic mastery to the coach
+type profileMastery struct {
+	profiles store.StudentProfileRepository
+}
+
+func (p profileMastery) MasteryProfile(ctx context.Context, studentID string) (etp.MasteryProfile, error) {
+	profile, err := p.profiles.Get(ctx, studentID)
+	if errors.Is(err, store.ErrNotFound) {
+		return etp.MasteryProfile{}, nil
+	}
+	if err != nil {
+		return etp.MasteryProfile{}, err
+	}
+	return profile.Mastery, nil
+}
+
+func (s *Server) saveProfile(
+	ctx context.Context,
+	studentID string,
+	context etp.StudentContext,
+	response *coach.CoachResponse,
+) {
+	if err := s.updateProfile(ctx, studentID, context, response); err != nil {
+		log.Printf("Error saving profile %s: %v", studentID, err)
+	}
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	s.hub.ServeWS(w, r, chi.URLParam(r, "studentId"))
+}
+
 func (s *Server) handleGetStudentProfile(w http.ResponseWriter, r *http.Request) {
 	studentID := chi.URLParam(r, "studentId")
 
 	// Get or create profile
-	profile, exists := s.profiles[studentID]
-	if !exists {
+	profile, err := s.profiles.Get(r.Context(), studentID)
+	if errors.Is(err, store.ErrNotFound) {
 		// Return default profile
-		profile = &StudentProfile{
-			StudentID:      studentID,
-			Age:            12, // Default age for testing
-			PlayBreakStage: "level_1",
-			RewardsEarned:  0,
-			ActiveBarriers: []string{},
-		}
+		profile = store.NewStudentProfile(studentID, 12) // Default age for testing
+	} else if err != nil {
+		log.Printf("Error loading profile %s: %v", studentID, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
 	}
 
+	// Time in stage moves on between interactions
+	s.playBreaks.Refresh(&profile.PlayBreak, time.Now().UTC())
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(profile)
 }
 
+func (s *Server) handleStartSession(w http.ResponseWriter, r *http.Request) {
+	var req StartSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.StudentID == "" {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	session := s.orchestrator.Sessions().StartSession(req.StudentID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	session, err := s.orchestrator.Sessions().GetSession(chi.URLParam(r, "sessionId"))
+	if err != nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+func (s *Server) handleEndSession(w http.ResponseWriter, r *http.Request) {
+	session, err := s.orchestrator.Sessions().EndSession(chi.URLParam(r, "sessionId"))
+	if errors.Is(err, coach.ErrSessionEnded) {
+		http.Error(w, "Session has ended", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+func (s *Server) handleRedeemReward(w http.ResponseWriter, r *http.Request) {
+	var req RedeemRewardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	unlock, err := s.rewards.Redeem(req.Code)
+	switch {
+	case errors.Is(err, rewards.ErrInvalidCode):
+		http.Error(w, "Invalid code", http.StatusBadRequest)
+		return
+	case errors.Is(err, rewards.ErrUnknownCode):
+		http.Error(w, "Unknown code", http.StatusNotFound)
+		return
+	case errors.Is(err, rewards.ErrAlreadyRedeemed):
+		http.Error(w, "Code already redeemed", http.StatusConflict)
+		return
+	case errors.Is(err, rewards.ErrExpired):
+		http.Error(w, "Code expired", http.StatusGone)
+		return
+	case err != nil:
+		log.Printf("Error redeeming reward: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Nudge the student back when the break is over
+	s.hub.StartPlayBreak(unlock.StudentID, *unlock.PlayUntil)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.rewardStatus(unlock))
+}
+
+func (s *Server) handleValidateReward(w http.ResponseWriter, r *http.Request) {
+	unlock, err := s.rewards.Validate(r.URL.Query().Get("code"))
+	switch {
+	case errors.Is(err, rewards.ErrInvalidCode), errors.Is(err, rewards.ErrUnknownCode):
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(RewardStatusResponse{Valid: false, Status: "invalid"})
+		return
+	case err != nil:
+		log.Printf("Error validating reward: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.rewardStatus(unlock))
+}
+
+func (s *Server) rewardStatus(unlock *rewards.Unlock) RewardStatusResponse {
+	now := s.rewards.Now()
+	status := unlock.Status(now)
+
+	response := RewardStatusResponse{
+		Status: status,
+		Unlock: unlock,
+	}
+	switch status {
+	case rewards.StatusActive:
+		response.Valid = true
+		response.RemainingSeconds = int(unlock.PlayUntil.Sub(now).Seconds())
+	case rewards.StatusUnredeemed:
+		response.Valid = true
+		response.RemainingSeconds = int(unlock.ValidUntil.Sub(now).Seconds())
+	}
+	return response
+}
+
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	pipeline := s.orchestrator.Pipeline(s.orchestrator.Profile())
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "healthy",
 		"service": "humanos-api",
 		"version": "0.1.0",
+		"pipeline": map[string]interface{}{
+			"profile": s.orchestrator.Profile(),
+			"stages":  pipeline.Stages(),
+			"timings": pipeline.Stats(),
+		},
+		"chisg": map[string]interface{}{
+			"circuit": s.chisg.CircuitState(),
+		},
+		"embeddings": s.embeddings,
 		"features": []string{
 			"barrier_detection",
+			"embedding_barrier_matching",
 			"age_appropriate_responses",
 			"trauma_detection",
 			"intervention_selection",
+			"agentic_knowledge_context",
+			"conversation_sessions",
+			"realtime_websocket",
+			"play_break_progression",
+			"reward_unlock_codes",
 		},
 	})
 }
 
 func (s *Server) updateProfile(
+	ctx context.Context,
 	studentID string,
 	context etp.StudentContext,
 	response *coach.CoachResponse,
-) {
-	profile, exists := s.profiles[studentID]
-	if !exists {
-		profile = &StudentProfile{
-			StudentID:      studentID,
-			Age:            context.Age,
-			PlayBreakStage: "level_1",
-			RewardsEarned:  0,
+) error {
+	base := store.NewStudentProfile(studentID, context.Age)
+
+	_, err := s.profiles.Update(ctx, studentID, base, func(profile *store.StudentProfile) error {
+		// Update brain state: the server's estimate, not the client's numbers
+		profile.BrainState = context.BrainState
+		if response.BrainState != nil {
+			profile.BrainState = response.BrainState.State
+		}
+
+		// Update barriers
+		profile.ActiveBarriers = response.DetectedBarriers
+
+		// Update rewards if earned
+		if response.RewardEarned {
+			profile.RewardsEarned++
+		}
+
+		// Play break stage progression; safeguarding turns say nothing about focus
+		if !response.SafeguardingAlert {
+			transition := s.playBreaks.Record(&profile.PlayBreak, s.playBreakOutcome(response))
+			profile.PlayBreakStage = profile.PlayBreak.CurrentStage.Level()
+			if transition != nil {
+				log.Printf("Student %s play break stage %s → %s",
+					studentID, transition.From, transition.To)
+			}
+		}
+
+		// Topic mastery: the turn's answer quality against the question's difficulty
+		if evidence := response.Mastery; evidence != nil && evidence.Graded() {
+			observation := evidence.Observation()
+			observation.At = time.Now().UTC()
+			s.mastery.Record(&profile.Mastery, observation)
 		}
+
+		// Update last interaction
+		profile.LastInteraction = response.Timestamp
+		return nil
+	})
+	return err
+}
+
+// playBreakOutcome summarises a coached turn for the play break engine
+func (s *Server) playBreakOutcome(response *coach.CoachResponse) playbreak.Outcome {
+	outcome := playbreak.Outcome{
+		Success:    response.RewardEarned || len(response.DetectedBarrierIDs) == 0,
+		Rewarded:   response.RewardEarned,
+		BarrierIDs: response.DetectedBarrierIDs,
+		At:         time.Now().UTC(),
 	}
 
-	// Update brain state
-	profile.BrainState = context.BrainState
+	if response.RewardEarned && response.SessionID != "" {
+		if session, err := s.orchestrator.Sessions().GetSession(response.SessionID); err == nil {
+			outcome.WorkMinutes = session.WorkMinutes()
+		}
+	}
 
-	// Update barriers
-	profile.ActiveBarriers = response.DetectedBarriers
+	return outcome
+}
 
-	// Update rewards if earned
-	if response.RewardEarned {
-		profile.RewardsEarned++
-		// TODO: Implement play break stage progression
+// openRewardLedger keeps unlocks alongside profiles: in memory for
+// PROFILE_STORE=memory, otherwise in their own bolt file
+func openRewardLedger() (*rewards.Ledger, error) {
+	var rewardStore rewards.Store
+	if os.Getenv("PROFILE_STORE") == "memory" {
+		rewardStore = rewards.NewMemoryStore()
+	} else {
+		boltStore, err := rewards.NewBoltStore(getEnvOrDefault("REWARD_DB_PATH", "data/rewards.db"))
+		if err != nil {
+			return nil, err
+		}
+		rewardStore = boltStore
 	}
 
-	// Update last interaction
-	profile.LastInteraction = response.Timestamp
+	return rewards.NewLedger(rewardStore, rewards.ConfigFromEnv())
+}
 
-	s.profiles[studentID] = profile
+// openSafeguardingOutbox follows the same store choice as the reward ledger;
+// the audit log is always written to disk
+func openSafeguardingOutbox() (*safeguarding.Outbox, error) {
+	cfg := safeguarding.OutboxConfigFromEnv()
+	if os.Getenv("PROFILE_STORE") != "memory" {
+		return safeguarding.OpenOutbox(cfg)
+	}
+
+	audit, err := safeguarding.OpenAuditLog(cfg.AuditPath)
+	if err != nil {
+		return nil, err
+	}
+	return safeguarding.NewOutbox(safeguarding.NewMemoryOutboxStore(), audit, safeguarding.NewHTTPSender(cfg), cfg), nil
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -0,0 +1,33 @@
+// Command fakechisg serves a scripted CHISG knowledge graph for local development
+//
+//	go run ./cmd/fakechisg
+//	COACH_PROFILE=agentic CHISG_API_URL=http://localhost:8082 go run ./cmd/api
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/integration"
+)
+
+func main() {
+	fake := integration.NewFakeServer(integration.DefaultFakeGraph()...)
+	if ms, err := strconv.Atoi(os.Getenv("FAKE_CHISG_DELAY_MS")); err == nil {
+		fake.SetDelay(time.Duration(ms) * time.Millisecond)
+	}
+	if status, err := strconv.Atoi(os.Getenv("FAKE_CHISG_FAIL_STATUS")); err == nil {
+		fake.FailWith(status)
+	}
+
+	port := os.Getenv("FAKE_CHISG_PORT")
+	if port == "" {
+		port = "8082"
+	}
+
+	log.Printf("🧪 Fake CHISG listening on :%s (POST /api/knowledge/analyze, /api/knowledge/prerequisites)", port)
+	log.Fatal(http.ListenAndServe(":"+port, fake))
+}
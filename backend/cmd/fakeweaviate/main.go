@@ -0,0 +1,29 @@
+// Command fakeweaviate serves an in-memory stand-in for the Weaviate REST API
+//
+//	go run ./cmd/fakeweaviate
+//	EMBEDDING_BACKEND=weaviate WEAVIATE_URL=http://localhost:8081 go run ./cmd/api
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/mike5tew/humanos/internal/nlp"
+)
+
+func main() {
+	stub := nlp.NewWeaviateStub()
+	if status, err := strconv.Atoi(os.Getenv("FAKE_WEAVIATE_FAIL_STATUS")); err == nil {
+		stub.FailWith(status)
+	}
+
+	port := os.Getenv("FAKE_WEAVIATE_PORT")
+	if port == "" {
+		port = "8081"
+	}
+
+	log.Printf("🧪 Fake Weaviate listening on :%s (/v1/schema, /v1/batch/objects, /v1/graphql)", port)
+	log.Fatal(http.ListenAndServe(":"+port, stub))
+}
@@ -0,0 +1,33 @@
+// Command mockllm serves a deterministic OpenAI-compatible API for local development
+//
+//	go run ./cmd/mockllm
+//	LLM_BACKEND=openai LLM_BASE_URL=http://localhost:8089/v1 go run ./cmd/api
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/generation"
+)
+
+func main() {
+	mock := generation.NewMockServer()
+	if ms, err := strconv.Atoi(os.Getenv("MOCK_LLM_DELAY_MS")); err == nil {
+		mock.Delay = time.Duration(ms) * time.Millisecond
+	}
+	if status, err := strconv.Atoi(os.Getenv("MOCK_LLM_FAIL_STATUS")); err == nil {
+		mock.FailWith = status
+	}
+
+	port := os.Getenv("MOCK_LLM_PORT")
+	if port == "" {
+		port = "8089"
+	}
+
+	log.Printf("🧪 Mock LLM listening on :%s (POST /v1/chat/completions)", port)
+	log.Fatal(http.ListenAndServe(":"+port, mock))
+}
@@ -1,17 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 
 	"github.com/mike5tew/humanos/internal/coach"
+	"github.com/mike5tew/humanos/internal/curriculum"
 	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/nlp"
 )
 
 func main() {
-	fmt.Println("🚀 HumanOS Demo - Testing Interaction Workflow\n")
+	fmt.Print("🚀 HumanOS Demo - Testing Interaction Workflow\n\n")
 
 	// Get the correct paths relative to the project root (where .git is)
 	projectRoot := getProjectRoot()
@@ -21,16 +24,30 @@ func main() {
 	barriersPath := filepath.Join(projectRoot, "shared/schemas/barriers.json")
 	traumaPath := filepath.Join(projectRoot, "shared/schemas/trauma_detection.json")
 	agePath := filepath.Join(projectRoot, "shared/schemas/age_appropriateness.json")
+	curriculumPath := filepath.Join(projectRoot, "shared/schemas/curriculum_taxonomy.json")
 
 	fmt.Printf("📄 Barriers path: %s\n", barriersPath)
 	fmt.Printf("📄 Trauma path: %s\n", traumaPath)
-	fmt.Printf("📄 Age path: %s\n\n", agePath)
+	fmt.Printf("📄 Age path: %s\n", agePath)
+	fmt.Printf("📄 Curriculum path: %s\n\n", curriculumPath)
 
 	// Initialize orchestrator
 	orchestrator, err := coach.NewOrchestrator(barriersPath, traumaPath, agePath)
 	if err != nil {
 		log.Fatalf("Failed to initialize: %v", err)
 	}
+	taxonomy, err := curriculum.Load(curriculumPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize: %v", err)
+	}
+	orchestrator.UseCurriculum(taxonomy)
+	classifier, err := nlp.New(nlp.DefaultConfig())
+	if err != nil {
+		log.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := orchestrator.UseClassifier(context.Background(), classifier); err != nil {
+		log.Fatalf("Failed to initialize: %v", err)
+	}
 
 	// Test scenarios
 	scenarios := []struct {
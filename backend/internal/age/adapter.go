@@ -0,0 +1,438 @@
+package age
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Change kinds recorded in a Report
+const (
+	ChangeVocabulary     = "vocabulary"      // A word swapped for a simpler one
+	ChangePhraseRemoved  = "phrase_removed"  // An abstract phrase dropped
+	ChangeSentenceSplit  = "sentence_split"  // A long sentence broken in two
+	ChangeSyllables      = "syllables"       // Unresolved: no simpler word known
+	ChangeSentenceLength = "sentence_length" // Unresolved: no natural break point
+)
+
+var wordPattern = regexp.MustCompile(`[A-Za-z][A-Za-z'’]*`)
+
+// Conjunctions a long sentence can be split before, as well as a free-standing dash
+var splitConjunctions = map[string]bool{"and": true, "but": true, "so": true}
+
+// Change is one edit the adapter made, or wanted to make but could not
+type Change struct {
+	Kind        string `json:"kind"`
+	Original    string `json:"original"`
+	Replacement string `json:"replacement,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+// Finding is an offense risk present in the adapted text
+type Finding struct {
+	Risk       string `json:"risk"`
+	Trigger    string `json:"trigger"`
+	Prevention string `json:"prevention"`
+	Evidence   string `json:"evidence"`
+}
+
+// Report describes everything Adapt did to a reply
+type Report struct {
+	Age        int       `json:"age"`
+	Group      string    `json:"group"`
+	Original   string    `json:"original"`
+	Adapted    string    `json:"adapted"`
+	Changes    []Change  `json:"changes"`
+	Unresolved []Change  `json:"unresolved"`
+	Risks      []Finding `json:"risks"`
+}
+
+// Summary is a one-line account for reasoning output
+func (r *Report) Summary() string {
+	counts := map[string]int{}
+	for _, change := range r.Changes {
+		counts[change.Kind]++
+	}
+	parts := []string{}
+	if n := counts[ChangeVocabulary]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d word(s) simplified", n))
+	}
+	if n := counts[ChangePhraseRemoved]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d phrase(s) removed", n))
+	}
+	if n := counts[ChangeSentenceSplit]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d sentence(s) split", n))
+	}
+	if len(r.Unresolved) > 0 {
+		parts = append(parts, fmt.Sprintf("%d unresolved", len(r.Unresolved)))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("🧒 Language already suits %s", r.Group)
+	}
+	return fmt.Sprintf("🧒 Adapted for %s: %s", r.Group, strings.Join(parts, ", "))
+}
+
+// Adapter rewrites replies to fit each age group's language guidelines
+type Adapter struct {
+	schema   *Schema
+	simpler  map[string]string
+	everyday map[string]bool
+	phrases  map[string][]*regexp.Regexp // Abstract phrases to remove, by group name
+}
+
+// NewAdapter loads the age appropriateness schema
+func NewAdapter(schemaPath string) (*Adapter, error) {
+	schema, err := LoadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewAdapterFromSchema(schema), nil
+}
+
+// NewAdapterFromSchema wraps an already validated schema
+func NewAdapterFromSchema(schema *Schema) *Adapter {
+	everyday := map[string]bool{}
+	for _, word := range schema.Language.EverydayWords {
+		everyday[strings.ToLower(word)] = true
+	}
+	phrases := map[string][]*regexp.Regexp{}
+	groups := []*Group{}
+	for i := range schema.Groups {
+		groups = append(groups, &schema.Groups[i])
+	}
+	for _, group := range schema.Interpolated {
+		groups = append(groups, group)
+	}
+	for _, group := range groups {
+		for _, phrase := range group.LanguageGuidelines.Concepts.RemovePhrases {
+			pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(phrase) + `\s*`)
+			phrases[group.Name] = append(phrases[group.Name], pattern)
+		}
+	}
+	return &Adapter{
+		schema:   schema,
+		simpler:  schema.Language.SimplerWords,
+		everyday: everyday,
+		phrases:  phrases,
+	}
+}
+
+// Group returns the guidelines that apply at an age
+func (a *Adapter) Group(age int) *Group {
+	return a.schema.Group(age)
+}
+
+// SafeguardingResponse is the group's message when a concern is raised
+func (a *Adapter) SafeguardingResponse(age int) string {
+	return a.Group(age).SafeguardingResponse
+}
+
+// SafeFallback is the group's reply when an adapted one still carries offense risk
+func (a *Adapter) SafeFallback(age int) string {
+	return a.Group(age).SafeFallback
+}
+
+// AdjustLanguage returns just the adapted text
+func (a *Adapter) AdjustLanguage(text string, age int) string {
+	return a.Adapt(text, age).Adapted
+}
+
+// Adapt rewrites text for an age and reports each change
+// Steps run in order: abstract phrases, vocabulary, then sentence length
+func (a *Adapter) Adapt(text string, age int) *Report {
+	group := a.Group(age)
+	report := &Report{
+		Age:        age,
+		Group:      group.Name,
+		Original:   text,
+		Changes:    []Change{},
+		Unresolved: []Change{},
+	}
+
+	adapted := a.removePhrases(text, group, report)
+	adapted = a.simplifyVocabulary(adapted, group, report)
+	adapted = a.shortenSentences(adapted, group, report)
+
+	report.Adapted = adapted
+	report.Risks = a.checkRisks(adapted, group, report.Unresolved)
+	return report
+}
+
+// CheckOffenseRisk lists the group's offense risks present in text as it stands
+func (a *Adapter) CheckOffenseRisk(text string, age int) []Finding {
+	group := a.Group(age)
+	return a.checkRisks(text, group, a.unresolvedWords(text, group))
+}
+
+// removePhrases drops abstract phrases the group cannot use
+func (a *Adapter) removePhrases(text string, group *Group, report *Report) string {
+	for _, pattern := range a.phrases[group.Name] {
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			report.Changes = append(report.Changes, Change{
+				Kind:     ChangePhraseRemoved,
+				Original: strings.TrimSpace(match),
+				Reason:   "abstract phrase: " + group.LanguageGuidelines.Concepts.Allowed,
+			})
+			return ""
+		})
+	}
+
+	// A removed opener leaves the sentence starting in lowercase
+	sentences := SplitSentences(text)
+	for i := range sentences {
+		sentences[i].Text = capitalize(sentences[i].Text)
+	}
+	return JoinSentences(sentences)
+}
+
+// simplifyVocabulary swaps words over the syllable limit, or named as bad examples,
+// for simpler ones; words with no known alternative are reported as unresolved
+func (a *Adapter) simplifyVocabulary(text string, group *Group, report *Report) string {
+	allowed, discouraged := a.wordLists(group)
+	maxSyllables := group.LanguageGuidelines.Vocabulary.MaxSyllables
+	seen := map[string]bool{}
+
+	return wordPattern.ReplaceAllStringFunc(text, func(word string) string {
+		lower := strings.ToLower(word)
+		if allowed[lower] {
+			return word
+		}
+
+		syllables := CountSyllables(word)
+		tooLong := syllables > maxSyllables
+		if !tooLong && !discouraged[lower] {
+			return word
+		}
+
+		reason := fmt.Sprintf("%d syllables (max %d)", syllables, maxSyllables)
+		if !tooLong {
+			reason = "listed as a word to avoid"
+		}
+
+		if simpler, ok := a.simpler[lower]; ok {
+			replacement := matchCase(word, simpler)
+			report.Changes = append(report.Changes, Change{
+				Kind:        ChangeVocabulary,
+				Original:    word,
+				Replacement: replacement,
+				Reason:      reason,
+			})
+			return replacement
+		}
+
+		if tooLong && !seen[lower] {
+			seen[lower] = true
+			report.Unresolved = append(report.Unresolved, Change{
+				Kind:     ChangeSyllables,
+				Original: word,
+				Reason:   reason + ", no simpler word known",
+			})
+		}
+		return word
+	})
+}
+
+// shortenSentences splits sentences over the group's word limit at commas or conjunctions
+func (a *Adapter) shortenSentences(text string, group *Group, report *Report) string {
+	maxWords := group.LanguageGuidelines.SentenceStructure.MaxWordsPerSentence
+	sentences := SplitSentences(text)
+	result := make([]Sentence, 0, len(sentences))
+
+	for _, sentence := range sentences {
+		parts := splitLong(sentence.Text, maxWords)
+		for i, part := range parts {
+			space := " "
+			if i == len(parts)-1 {
+				space = sentence.Space
+			}
+			result = append(result, Sentence{Text: part, Space: space})
+
+			if n := CountWords(part); n > maxWords {
+				report.Unresolved = append(report.Unresolved, Change{
+					Kind:     ChangeSentenceLength,
+					Original: part,
+					Reason:   fmt.Sprintf("%d words (max %d), no natural break point", n, maxWords),
+				})
+			}
+		}
+		if len(parts) > 1 {
+			report.Changes = append(report.Changes, Change{
+				Kind:        ChangeSentenceSplit,
+				Original:    sentence.Text,
+				Replacement: strings.Join(parts, " "),
+				Reason:      fmt.Sprintf("%d words (max %d)", CountWords(sentence.Text), maxWords),
+			})
+		}
+	}
+	return JoinSentences(result)
+}
+
+// splitLong breaks a sentence at the break point nearest its middle, recursively,
+// never leaving a piece shorter than three words
+// Questions are left whole: splitting one turns its first half into a statement
+func splitLong(sentence string, maxWords int) []string {
+	words := strings.Fields(sentence)
+	if CountWords(sentence) <= maxWords || strings.HasSuffix(strings.TrimRight(sentence, `"'”’)`), "?") {
+		return []string{sentence}
+	}
+
+	const minPiece = 3
+	best := -1
+	for i := minPiece - 1; i < len(words)-minPiece; i++ {
+		breaks := strings.HasSuffix(words[i], ",") || strings.HasSuffix(words[i], ";") ||
+			splitConjunctions[strings.ToLower(words[i+1])] || isDash(words[i+1])
+		if !breaks {
+			continue
+		}
+		if best < 0 || abs(i-len(words)/2) < abs(best-len(words)/2) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return []string{sentence}
+	}
+
+	head := strings.TrimRight(strings.Join(words[:best+1], " "), ",;") + "."
+	tail := words[best+1:]
+	if strings.EqualFold(tail[0], "and") || isDash(tail[0]) {
+		tail = tail[1:]
+	}
+
+	return append(splitLong(head, maxWords), splitLong(capitalize(strings.Join(tail, " ")), maxWords)...)
+}
+
+// checkRisks matches the group's offense risks against text
+func (a *Adapter) checkRisks(text string, group *Group, unresolved []Change) []Finding {
+	findings := []Finding{}
+	lower := strings.ToLower(text)
+	maxWords := group.LanguageGuidelines.SentenceStructure.MaxWordsPerSentence
+
+	for _, risk := range group.LanguageGuidelines.OffenseRisks {
+		evidence := ""
+		for _, marker := range risk.Markers {
+			if containsPhrase(lower, strings.ToLower(marker)) {
+				evidence = fmt.Sprintf("%q", marker)
+				break
+			}
+		}
+
+		if evidence == "" {
+			switch risk.Check {
+			case CheckSentenceLength:
+				for _, sentence := range SplitSentences(text) {
+					if n := CountWords(sentence.Text); n > maxWords*2 {
+						evidence = fmt.Sprintf("sentence of %d words (max %d)", n, maxWords)
+						break
+					}
+				}
+			case CheckSyllables:
+				words := []string{}
+				for _, change := range unresolved {
+					if change.Kind == ChangeSyllables {
+						words = append(words, change.Original)
+					}
+				}
+				if len(words) > 0 {
+					evidence = "complex words: " + strings.Join(words, ", ")
+				}
+			}
+		}
+
+		if evidence != "" {
+			findings = append(findings, Finding{
+				Risk:       risk.Risk,
+				Trigger:    risk.Trigger,
+				Prevention: risk.Prevention,
+				Evidence:   evidence,
+			})
+		}
+	}
+	return findings
+}
+
+// unresolvedWords lists words over the syllable limit that have no simpler form
+func (a *Adapter) unresolvedWords(text string, group *Group) []Change {
+	report := &Report{}
+	a.simplifyVocabulary(text, group, report)
+	return report.Unresolved
+}
+
+// wordLists derives per-group allow and avoid sets from the schema
+// Allowed: words the group can be introduced to, everyday words and good examples
+// Discouraged: words from bad examples, replaced even under the syllable limit
+func (a *Adapter) wordLists(group *Group) (allowed, discouraged map[string]bool) {
+	vocabulary := group.LanguageGuidelines.Vocabulary
+	allowed = map[string]bool{}
+	for word := range a.everyday {
+		allowed[word] = true
+	}
+	for _, phrase := range append(append([]string{}, vocabulary.CanIntroduce...), vocabulary.Examples["good"]...) {
+		for _, word := range wordPattern.FindAllString(phrase, -1) {
+			allowed[strings.ToLower(word)] = true
+		}
+	}
+
+	discouraged = map[string]bool{}
+	for _, phrase := range vocabulary.Examples["bad"] {
+		for _, word := range wordPattern.FindAllString(phrase, -1) {
+			lower := strings.ToLower(word)
+			if _, ok := a.simpler[lower]; ok && !allowed[lower] {
+				discouraged[lower] = true
+			}
+		}
+	}
+	return allowed, discouraged
+}
+
+// containsPhrase matches whole words so "yay!" does not match inside "yayyy"
+func containsPhrase(text, phrase string) bool {
+	for from := 0; ; {
+		i := strings.Index(text[from:], phrase)
+		if i < 0 {
+			return false
+		}
+		start, end := from+i, from+i+len(phrase)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			return true
+		}
+		from = start + 1
+	}
+}
+
+func isDash(word string) bool {
+	return word == "-" || word == "–" || word == "—"
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// matchCase gives replacement the capitalisation of original
+func matchCase(original, replacement string) string {
+	if strings.ToUpper(original) == original && len(original) > 1 {
+		return strings.ToUpper(replacement)
+	}
+	if r, _ := utf8.DecodeRuneInString(original); unicode.IsUpper(r) {
+		return capitalize(replacement)
+	}
+	return replacement
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || !unicode.IsLower(r) {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
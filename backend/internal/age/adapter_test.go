@@ -0,0 +1,41 @@
+package age
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitLong(t *testing.T) {
+	cases := []struct {
+		name     string
+		sentence string
+		maxWords int
+		want     []string
+	}{
+		{"within limit", "Add the two numbers together.", 8, []string{"Add the two numbers together."}},
+		{"at a comma", "First add the tens together, then add the ones to get the answer.", 8,
+			[]string{"First add the tens together.", "Then add the ones to get the answer."}},
+		{"drops a leading and", "We added the tens first and we added the ones last of all.", 8,
+			[]string{"We added the tens first.", "We added the ones last of all."}},
+		{"keeps but", "You can add them in any order but the answer stays the same.", 8,
+			[]string{"You can add them in any order.", "But the answer stays the same."}},
+		{"at a dash", "Look at the last digit carefully - it tells you if the number is even.", 8,
+			[]string{"Look at the last digit carefully.", "It tells you if the number is even."}},
+		{"recursive", "Draw the shape, count the sides, count the corners, then write both numbers down.", 4,
+			[]string{"Draw the shape.", "Count the sides.", "Count the corners.", "Then write both numbers down."}},
+		{"question left whole", "Can you add the tens together, and then add the ones as well?", 8,
+			[]string{"Can you add the tens together, and then add the ones as well?"}},
+		{"no break point", "Write every single number from one to twenty on the line below.", 8,
+			[]string{"Write every single number from one to twenty on the line below."}},
+		{"no piece under three words", "Yes, we can count every number in this long row of numbers.", 8,
+			[]string{"Yes, we can count every number in this long row of numbers."}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := splitLong(tc.sentence, tc.maxWords); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("splitLong(%q, %d) = %q, want %q", tc.sentence, tc.maxWords, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package age
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Offense risk checks that look at structure rather than marker phrases
+const (
+	CheckSentenceLength = "sentence_length" // A sentence runs past twice the group's limit
+	CheckSyllables      = "syllables"       // Words over the syllable limit survived adaptation
+)
+
+// Group is one developmental stage from age_appropriateness.json
+type Group struct {
+	Name                 string        `json:"name"`
+	AgeRange             [2]int        `json:"ageRange"`
+	DevelopmentalStage   string        `json:"developmentalStage"`
+	Characteristics      []string      `json:"characteristics"`
+	SafeguardingResponse string        `json:"safeguardingResponse"`
+	SafeFallback         string        `json:"safeFallback"`
+	LanguageGuidelines   LanguageGuide `json:"languageGuidelines"`
+	Readability          Readability   `json:"readability"`
+
+	// Set on groups built for an age between bands
+	Interpolated bool     `json:"interpolated,omitempty"`
+	Between      []string `json:"between,omitempty"`
+}
+
+// Readability is the band a reply's measured readability should fall within
+type Readability struct {
+	MaxGradeLevel       float64 `json:"maxGradeLevel"`       // Flesch-Kincaid grade
+	MaxSyllablesPerWord float64 `json:"maxSyllablesPerWord"` // Syllable density
+	MaxRareWordRatio    float64 `json:"maxRareWordRatio"`    // Share of words above WordListGrade
+	WordListGrade       int     `json:"wordListGrade"`       // Highest graded word list section the reader knows
+}
+
+// LanguageGuide contains age-appropriate language rules
+type LanguageGuide struct {
+	Vocabulary        VocabularyGuide `json:"vocabulary"`
+	SentenceStructure SentenceGuide   `json:"sentenceStructure"`
+	Concepts          ConceptGuide    `json:"concepts"`
+	OffenseRisks      []OffenseRisk   `json:"offenseRisks"`
+}
+
+type VocabularyGuide struct {
+	Level        string              `json:"level"`
+	MaxSyllables int                 `json:"maxSyllables"`
+	CanIntroduce []string            `json:"canIntroduce,omitempty"`
+	Examples     map[string][]string `json:"examples"`
+}
+
+type SentenceGuide struct {
+	MaxWordsPerSentence int               `json:"maxWordsPerSentence"`
+	Structure           string            `json:"structure"`
+	Examples            map[string]string `json:"examples"`
+}
+
+type ConceptGuide struct {
+	Allowed       string            `json:"allowed"`
+	RemovePhrases []string          `json:"removePhrases,omitempty"`
+	Examples      map[string]string `json:"examples"`
+}
+
+// OffenseRisk is flagged when any marker appears or its structural check fails
+type OffenseRisk struct {
+	Risk       string   `json:"risk"`
+	Trigger    string   `json:"trigger"`
+	Prevention string   `json:"prevention"`
+	Check      string   `json:"check,omitempty"`
+	Markers    []string `json:"markers,omitempty"`
+}
+
+// LanguageSettings is the vocabulary shared by every group
+type LanguageSettings struct {
+	SimplerWords  map[string]string `json:"simplerWords"`
+	EverydayWords []string          `json:"everydayWords"` // Familiar at every age despite their length
+}
+
+type ageSchema struct {
+	LanguageAdapter  LanguageSettings `json:"languageAdapter"`
+	InterpolatedAges struct {
+		Ages []int `json:"ages"`
+	} `json:"interpolatedAges"`
+	AgeGroups []Group `json:"ageGroups"`
+}
+
+// Schema is the validated age appropriateness schema
+// Groups are ordered youngest first and neither overlap nor leave gaps,
+// except for ages the schema declares as interpolated
+type Schema struct {
+	Groups       []Group
+	Language     LanguageSettings
+	Interpolated map[int]*Group
+}
+
+// LoadSchema reads and validates an age appropriateness schema
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSchema(data)
+}
+
+// ParseSchema builds a schema from raw JSON
+// Every problem found is reported, not just the first
+func ParseSchema(data []byte) (*Schema, error) {
+	var raw ageSchema
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid age schema: %w", err)
+	}
+
+	problems := []string{}
+	if len(raw.AgeGroups) == 0 {
+		problems = append(problems, "ageGroups: no groups defined")
+	}
+
+	for i, group := range raw.AgeGroups {
+		where := fmt.Sprintf("ageGroups[%d] (%s)", i, group.Name)
+		guide := group.LanguageGuidelines
+
+		if group.AgeRange[0] <= 0 || group.AgeRange[1] < group.AgeRange[0] {
+			problems = append(problems, fmt.Sprintf("%s: bad ageRange %v", where, group.AgeRange))
+		}
+		if guide.Vocabulary.MaxSyllables <= 0 {
+			problems = append(problems, fmt.Sprintf("%s: vocabulary.maxSyllables must be positive", where))
+		}
+		if guide.SentenceStructure.MaxWordsPerSentence <= 0 {
+			problems = append(problems, fmt.Sprintf("%s: sentenceStructure.maxWordsPerSentence must be positive", where))
+		}
+		if group.SafeguardingResponse == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing safeguardingResponse", where))
+		}
+		if group.SafeFallback == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing safeFallback", where))
+		}
+		if r := group.Readability; r.MaxGradeLevel <= 0 || r.MaxSyllablesPerWord <= 0 || r.MaxRareWordRatio <= 0 || r.WordListGrade <= 0 {
+			problems = append(problems, fmt.Sprintf("%s: readability targets must all be positive", where))
+		}
+		for _, risk := range guide.OffenseRisks {
+			switch risk.Check {
+			case "", CheckSentenceLength, CheckSyllables:
+			default:
+				problems = append(problems, fmt.Sprintf("%s: offense risk %q has unknown check %q", where, risk.Risk, risk.Check))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		sort.SliceStable(raw.AgeGroups, func(i, j int) bool {
+			return raw.AgeGroups[i].AgeRange[0] < raw.AgeGroups[j].AgeRange[0]
+		})
+		problems = append(problems, checkCoverage(raw.AgeGroups, raw.InterpolatedAges.Ages)...)
+	}
+
+	for word, simpler := range raw.LanguageAdapter.SimplerWords {
+		if word != strings.ToLower(word) || strings.ContainsAny(word, " -") || simpler == "" {
+			problems = append(problems, fmt.Sprintf("languageAdapter.simplerWords: bad entry %q → %q", word, simpler))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid age schema:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	schema := &Schema{
+		Groups:       raw.AgeGroups,
+		Language:     raw.LanguageAdapter,
+		Interpolated: map[int]*Group{},
+	}
+	for _, age := range raw.InterpolatedAges.Ages {
+		schema.Interpolated[age] = schema.interpolate(age)
+	}
+	return schema, nil
+}
+
+// checkCoverage reports overlapping bands, and gaps between them that
+// are not declared as interpolated; groups must be sorted by start age
+func checkCoverage(groups []Group, interpolated []int) []string {
+	problems := []string{}
+	declared := map[int]bool{}
+	for _, age := range interpolated {
+		declared[age] = true
+	}
+
+	gaps := map[int]bool{}
+	for i := 1; i < len(groups); i++ {
+		previous, current := groups[i-1], groups[i]
+		if current.AgeRange[0] <= previous.AgeRange[1] {
+			problems = append(problems, fmt.Sprintf("ageGroups: %q %v overlaps %q %v",
+				current.Name, current.AgeRange, previous.Name, previous.AgeRange))
+			continue
+		}
+		for age := previous.AgeRange[1] + 1; age < current.AgeRange[0]; age++ {
+			gaps[age] = true
+			if !declared[age] {
+				problems = append(problems, fmt.Sprintf("ageGroups: age %d falls between %q and %q; add a band or list it in interpolatedAges",
+					age, previous.Name, current.Name))
+			}
+		}
+	}
+
+	for _, age := range interpolated {
+		if !gaps[age] {
+			problems = append(problems, fmt.Sprintf("interpolatedAges: age %d is not between two bands", age))
+		}
+	}
+	return problems
+}
+
+// Group finds the group for an age
+// Declared gaps get an interpolated group; ages beyond the youngest or
+// oldest band use that band
+func (s *Schema) Group(age int) *Group {
+	if group, ok := s.Interpolated[age]; ok {
+		return group
+	}
+	for i := range s.Groups {
+		group := &s.Groups[i]
+		if age <= group.AgeRange[1] {
+			return group
+		}
+	}
+	return &s.Groups[len(s.Groups)-1]
+}
+
+// interpolate builds a group for an age between two bands
+// Numeric limits are blended by distance and rounded down, erring towards the
+// younger band; lists, wording and replies come from the nearer band, and from
+// the younger one when equidistant
+func (s *Schema) interpolate(age int) *Group {
+	var younger, older *Group
+	for i := 1; i < len(s.Groups); i++ {
+		if s.Groups[i-1].AgeRange[1] < age && age < s.Groups[i].AgeRange[0] {
+			younger, older = &s.Groups[i-1], &s.Groups[i]
+			break
+		}
+	}
+
+	weight := float64(age-younger.AgeRange[1]) / float64(older.AgeRange[0]-younger.AgeRange[1])
+	blend := func(low, high int) int {
+		return int(math.Floor(float64(low) + weight*float64(high-low)))
+	}
+	blendFloat := func(low, high float64) float64 {
+		return low + weight*(high-low)
+	}
+
+	nearer := younger
+	if weight > 0.5 {
+		nearer = older
+	}
+
+	group := *nearer
+	group.Name = fmt.Sprintf("Age %d (between %s and %s)", age, younger.Name, older.Name)
+	group.AgeRange = [2]int{age, age}
+	group.Interpolated = true
+	group.Between = []string{younger.Name, older.Name}
+
+	guide := nearer.LanguageGuidelines
+	guide.Vocabulary.MaxSyllables = blend(
+		younger.LanguageGuidelines.Vocabulary.MaxSyllables,
+		older.LanguageGuidelines.Vocabulary.MaxSyllables)
+	guide.SentenceStructure.MaxWordsPerSentence = blend(
+		younger.LanguageGuidelines.SentenceStructure.MaxWordsPerSentence,
+		older.LanguageGuidelines.SentenceStructure.MaxWordsPerSentence)
+	group.LanguageGuidelines = guide
+
+	group.Readability = Readability{
+		MaxGradeLevel:       blendFloat(younger.Readability.MaxGradeLevel, older.Readability.MaxGradeLevel),
+		MaxSyllablesPerWord: blendFloat(younger.Readability.MaxSyllablesPerWord, older.Readability.MaxSyllablesPerWord),
+		MaxRareWordRatio:    blendFloat(younger.Readability.MaxRareWordRatio, older.Readability.MaxRareWordRatio),
+		WordListGrade:       blend(younger.Readability.WordListGrade, older.Readability.WordListGrade),
+	}
+
+	return &group
+}
@@ -0,0 +1,103 @@
+package age
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+const ageSchemaPath = "../../../shared/schemas/age_appropriateness.json"
+
+// testSchema builds schema JSON with one valid group per age range
+func testSchema(t *testing.T, interpolated []int, ranges ...[2]int) []byte {
+	t.Helper()
+	groups := []Group{}
+	for i, ageRange := range ranges {
+		group := Group{
+			Name:                 fmt.Sprintf("group %d", i+1),
+			AgeRange:             ageRange,
+			SafeguardingResponse: "Let's talk to a trusted adult.",
+			SafeFallback:         "Let's try that another way.",
+			Readability:          Readability{MaxGradeLevel: 3, MaxSyllablesPerWord: 1.4, MaxRareWordRatio: 0.1, WordListGrade: 2},
+		}
+		group.LanguageGuidelines.Vocabulary.MaxSyllables = 2 + i
+		group.LanguageGuidelines.SentenceStructure.MaxWordsPerSentence = 8 + 4*i
+		groups = append(groups, group)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"interpolatedAges": map[string]any{"ages": interpolated},
+		"ageGroups":        groups,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseSchemaCoverage(t *testing.T) {
+	cases := []struct {
+		name         string
+		interpolated []int
+		ranges       [][2]int
+		wantErr      string
+	}{
+		{"contiguous", nil, [][2]int{{5, 7}, {8, 9}}, ""},
+		{"declared gap", []int{10}, [][2]int{{8, 9}, {11, 13}}, ""},
+		{"undeclared gap", nil, [][2]int{{8, 9}, {11, 13}}, "age 10 falls between"},
+		{"overlap", nil, [][2]int{{5, 8}, {8, 9}}, "overlaps"},
+		{"interpolated age inside a band", []int{6}, [][2]int{{5, 7}, {8, 9}}, "age 6 is not between two bands"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSchema(testSchema(t, tc.interpolated, tc.ranges...))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want one mentioning %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemaInterpolatesAgeTen(t *testing.T) {
+	schema, err := LoadSchema(ageSchemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	younger, older := schema.Group(9), schema.Group(11)
+	group := schema.Group(10)
+	if !group.Interpolated || group.AgeRange != [2]int{10, 10} {
+		t.Fatalf("age 10 = %s %v, want an interpolated group", group.Name, group.AgeRange)
+	}
+	if len(group.Between) != 2 || group.Between[0] != younger.Name || group.Between[1] != older.Name {
+		t.Errorf("between = %v, want [%s %s]", group.Between, younger.Name, older.Name)
+	}
+
+	// Halfway between the bands: limits blend and round down, wording comes from the younger band
+	blend := func(low, high int) int { return (low + high) / 2 }
+	guide, low, high := group.LanguageGuidelines, younger.LanguageGuidelines, older.LanguageGuidelines
+	if want := blend(low.Vocabulary.MaxSyllables, high.Vocabulary.MaxSyllables); guide.Vocabulary.MaxSyllables != want {
+		t.Errorf("maxSyllables = %d, want %d", guide.Vocabulary.MaxSyllables, want)
+	}
+	if want := blend(low.SentenceStructure.MaxWordsPerSentence, high.SentenceStructure.MaxWordsPerSentence); guide.SentenceStructure.MaxWordsPerSentence != want {
+		t.Errorf("maxWordsPerSentence = %d, want %d", guide.SentenceStructure.MaxWordsPerSentence, want)
+	}
+	if want := (younger.Readability.MaxGradeLevel + older.Readability.MaxGradeLevel) / 2; math.Abs(group.Readability.MaxGradeLevel-want) > 1e-9 {
+		t.Errorf("maxGradeLevel = %.2f, want %.2f", group.Readability.MaxGradeLevel, want)
+	}
+	if want := blend(younger.Readability.WordListGrade, older.Readability.WordListGrade); group.Readability.WordListGrade != want {
+		t.Errorf("wordListGrade = %d, want %d", group.Readability.WordListGrade, want)
+	}
+	if group.SafeguardingResponse != younger.SafeguardingResponse || group.SafeFallback != younger.SafeFallback {
+		t.Error("age 10 replies should come from the younger band when equidistant")
+	}
+}
@@ -0,0 +1,155 @@
+package age
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Abbreviations whose full stop does not end a sentence
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "st": true,
+	"etc": true, "vs": true, "eg": true, "ie": true, "e.g": true, "i.e": true,
+	"approx": true, "fig": true,
+}
+
+// Sentence is one sentence with the whitespace that followed it
+type Sentence struct {
+	Text  string
+	Space string // Separator to restore when joining ("" for the last sentence)
+}
+
+// SplitSentences breaks text into sentences, keeping their end punctuation
+// Abbreviations, decimals, initials and ellipses mid-sentence do not split,
+// and a line break always does
+func SplitSentences(text string) []Sentence {
+	sentences := []Sentence{}
+	start := 0
+
+	i := 0
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == '\n' && i >= start {
+			sentences = appendSentence(sentences, text, &start, i)
+			i += size
+			continue
+		}
+		if !isTerminator(r) {
+			i += size
+			continue
+		}
+
+		// Take the whole run of punctuation and any closing quotes or brackets
+		end := i + size
+		for end < len(text) {
+			next, nsize := utf8.DecodeRuneInString(text[end:])
+			if !isTerminator(next) && !strings.ContainsRune(`"'”’)]`, next) {
+				break
+			}
+			end += nsize
+		}
+		i = end
+
+		if end < len(text) {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(next) {
+				continue // "3.5", "e.g.x", "wait...what"
+			}
+		}
+		if r != '!' && r != '?' && !endsSentence(text, start, end) {
+			continue
+		}
+		sentences = appendSentence(sentences, text, &start, end)
+	}
+
+	if start < len(text) {
+		if rest := strings.TrimSpace(text[start:]); rest != "" {
+			sentences = append(sentences, Sentence{Text: rest})
+		} else if len(sentences) > 0 {
+			sentences[len(sentences)-1].Space += text[start:]
+		}
+	}
+	if len(sentences) > 0 {
+		last := &sentences[len(sentences)-1]
+		last.Space = strings.TrimRightFunc(last.Space, unicode.IsSpace)
+	}
+	return sentences
+}
+
+// JoinSentences reverses SplitSentences
+func JoinSentences(sentences []Sentence) string {
+	var b strings.Builder
+	for _, s := range sentences {
+		b.WriteString(s.Text)
+		b.WriteString(s.Space)
+	}
+	return b.String()
+}
+
+// appendSentence closes the sentence text[*start:end] and absorbs the whitespace after it
+func appendSentence(sentences []Sentence, text string, start *int, end int) []Sentence {
+	spaceEnd := end
+	for spaceEnd < len(text) {
+		r, size := utf8.DecodeRuneInString(text[spaceEnd:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		spaceEnd += size
+	}
+
+	sentenceText := strings.TrimSpace(text[*start:end])
+	space := text[end:spaceEnd]
+	*start = spaceEnd
+
+	if sentenceText == "" {
+		if len(sentences) > 0 {
+			sentences[len(sentences)-1].Space += space
+		}
+		return sentences
+	}
+	if space == "" && spaceEnd < len(text) {
+		space = " "
+	}
+	return append(sentences, Sentence{Text: sentenceText, Space: space})
+}
+
+// endsSentence decides whether a full stop at text[:end] closes the sentence
+func endsSentence(text string, start, end int) bool {
+	body := strings.TrimRight(text[start:end], `.'"”’)]`)
+	fields := strings.Fields(body)
+	if len(fields) == 0 {
+		return true
+	}
+	last := strings.TrimLeft(fields[len(fields)-1], `"'“‘(`)
+	if abbreviations[strings.ToLower(last)] {
+		return false
+	}
+	// A single capital is an initial: "J. K. Rowling"
+	if r, size := utf8.DecodeRuneInString(last); size == len(last) && unicode.IsUpper(r) && r != 'I' {
+		return false
+	}
+
+	// An ellipsis followed by a lowercase word carries on: "I tried... and it worked"
+	rest := strings.TrimLeftFunc(text[end:], unicode.IsSpace)
+	if strings.HasSuffix(text[start:end], "..") || strings.HasSuffix(text[start:end], "…") {
+		if r, _ := utf8.DecodeRuneInString(rest); unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTerminator(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…'
+}
+
+// CountWords counts the words in a sentence, ignoring stray punctuation
+func CountWords(sentence string) int {
+	count := 0
+	for _, field := range strings.Fields(sentence) {
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,56 @@
+package age
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"plain", "First one. Second one! Third one?", []string{"First one.", "Second one!", "Third one?"}},
+		{"abbreviations", "Ask Mr. Smith about it. He knows, e.g. the basics.", []string{"Ask Mr. Smith about it.", "He knows, e.g. the basics."}},
+		{"initials", "J. K. Rowling wrote it. Read it.", []string{"J. K. Rowling wrote it.", "Read it."}},
+		{"decimals", "The answer is 3.5 metres. Check it.", []string{"The answer is 3.5 metres.", "Check it."}},
+		{"ellipsis carries on", "I tried... and it worked. Great!", []string{"I tried... and it worked.", "Great!"}},
+		{"ellipsis ends", "I tried... It worked.", []string{"I tried...", "It worked."}},
+		{"unicode ellipsis", "Wait… then add them. Done.", []string{"Wait… then add them.", "Done."}},
+		{"closing quote", `She said "stop." Then she left.`, []string{`She said "stop."`, "Then she left."}},
+		{"repeated punctuation", "Really?! Yes.", []string{"Really?!", "Yes."}},
+		{"line break", "Step one\nStep two", []string{"Step one", "Step two"}},
+		{"no terminator", "no full stop here", []string{"no full stop here"}},
+		{"empty", "", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sentences := SplitSentences(tc.text)
+			var got []string
+			for _, s := range sentences {
+				got = append(got, s.Text)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("SplitSentences(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+			if joined := JoinSentences(sentences); joined != tc.text {
+				t.Errorf("JoinSentences did not restore %q: got %q", tc.text, joined)
+			}
+		})
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	cases := map[string]int{
+		"Two words.":          2,
+		"Add 3 and 4 - done!": 5,
+		"  ":                  0,
+	}
+	for sentence, want := range cases {
+		if got := CountWords(sentence); got != want {
+			t.Errorf("CountWords(%q) = %d, want %d", sentence, got, want)
+		}
+	}
+}
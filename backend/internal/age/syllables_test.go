@@ -0,0 +1,64 @@
+package age
+
+import "testing"
+
+func TestCountSyllables(t *testing.T) {
+	cases := []struct {
+		word string
+		want int
+	}{
+		// Vowel groups
+		{"cat", 1},
+		{"happy", 2},
+		{"number", 2},
+		{"family", 3},
+		{"understand", 3},
+		{"calculator", 4},
+
+		// Digraphs stay one syllable
+		{"goal", 1},
+		{"boat", 1},
+		{"road", 1},
+		{"coach", 1},
+		{"rain", 1},
+
+		// Vowel pairs spoken separately
+		{"riot", 2},
+		{"giant", 2},
+		{"studio", 3},
+		{"video", 3},
+
+		// Soft c and t before the pair
+		{"social", 2},
+		{"special", 2},
+		{"partial", 2},
+		{"nation", 2},
+		{"delicious", 3},
+
+		// Silent endings
+		{"make", 1},
+		{"shapes", 1},
+		{"jumped", 1},
+		{"table", 2},
+		{"wanted", 2},
+		{"boxes", 2},
+		{"lately", 2},
+		{"definitely", 4},
+		{"freely", 2},
+
+		// Exceptions and punctuation
+		{"poetry", 3},
+		{"idea", 3},
+		{"people", 2},
+		{"Fractions", 2},
+		{"teacher's", 2},
+		{"'quiet'", 2},
+		{"", 0},
+	}
+
+	for _, tc := range cases {
+		if got := CountSyllables(tc.word); got != tc.want {
+			t.Errorf("CountSyllables(%q) = %d, want %d", tc.word, got, tc.want)
+		}
+	}
+}
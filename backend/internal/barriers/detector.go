@@ -1,24 +1,48 @@
 package barriers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"log"
+	"math"
 	"os"
-	"regexp"
-	"strings"
+	"sort"
 
 	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/nlp"
 )
 
+// ETPs whose nearest exemplars are weaker than this are not reported
+const minETPConfidence = 0.5
+
 // BarrierDetector analyzes student input to identify barriers
 type BarrierDetector struct {
-	barriers []etp.StudentBarrier
+	profiles   []etp.BarrierStudentProfile
+	barriers   []etp.StudentBarrier
+	rules      *RuleEngine
+	classifier *nlp.Classifier // Optional nearest-neighbour matching, blended with the rules
 }
 
 // DetectedBarrier represents a barrier with confidence score
 type DetectedBarrier struct {
-	Barrier    etp.StudentBarrier `json:"barrier"`
-	Confidence float64            `json:"confidence"`
-	Reasoning  []string           `json:"reasoning"`
+	Barrier    etp.StudentBarrier         `json:"barrier"`
+	Profile    *etp.BarrierStudentProfile `json:"-"`
+	Confidence float64                    `json:"confidence"`
+	Reasoning  []string                   `json:"reasoning"`
+}
+
+// DetectedETP is an emotional trigger point the message sounds like
+type DetectedETP struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+	Nearest    string  `json:"nearest"` // Closest exemplar carrying this ETP
+}
+
+// Detection is everything found in one message
+type Detection struct {
+	Barriers []DetectedBarrier `json:"barriers"` // Most confident first
+	ETPs     []DetectedETP     `json:"etps"`     // Empty without a classifier
 }
 
 // NewBarrierDetector creates a barrier detector from JSON schema
@@ -29,149 +53,142 @@ func NewBarrierDetector(barriersPath string) (*BarrierDetector, error) {
 	}
 
 	var barriersData struct {
-		Barriers []etp.StudentBarrier `json:"barriers"`
+		Barriers []etp.BarrierStudentProfile `json:"barriers"`
 	}
 
 	if err := json.Unmarshal(data, &barriersData); err != nil {
 		return nil, err
 	}
 
+	// Summary barriers carry levers derived from each intervention strategy
+	summaries := make([]etp.StudentBarrier, len(barriersData.Barriers))
+	for i := range barriersData.Barriers {
+		summaries[i] = barriersData.Barriers[i].ToStudentBarrier()
+	}
+
+	rules, err := NewRuleEngine(barriersData.Barriers)
+	if err != nil {
+		return nil, err
+	}
+	for _, unmatched := range rules.Unmatched() {
+		log.Printf("⚠️ Barrier %s: detection signal %q matches no known message feature and is ignored",
+			unmatched.BarrierID, unmatched.Signal)
+	}
+
 	return &BarrierDetector{
-		barriers: barriersData.Barriers,
+		profiles: barriersData.Barriers,
+		barriers: summaries,
+		rules:    rules,
 	}, nil
 }
 
-// DetectBarriers analyzes input for barrier patterns
-func (d *BarrierDetector) DetectBarriers(input string, context etp.StudentContext) []DetectedBarrier {
-	detected := []DetectedBarrier{}
+// Profiles returns every loaded barrier profile
+func (d *BarrierDetector) Profiles() []etp.BarrierStudentProfile {
+	return d.profiles
+}
 
-	// Check for "I don't know" - primary avoidance
-	if d.containsIDontKnow(input) {
-		// This searches for barrier with id="lack_of_motivation"
-		if barrier := d.findBarrierByID("lack_of_motivation"); barrier != nil {
-			detected = append(detected, DetectedBarrier{
-				Barrier:    *barrier,
-				Confidence: 0.8,
-				Reasoning:  []string{"Student used 'I don't know' - primary avoidance tactic"},
-			})
+// Profile returns the full profile for a barrier ID, or nil if unknown
+func (d *BarrierDetector) Profile(id string) *etp.BarrierStudentProfile {
+	for i := range d.profiles {
+		if d.profiles[i].ID == id {
+			return &d.profiles[i]
 		}
 	}
+	return nil
+}
 
-	// Check for confrontational language
-	if d.isConfrontational(input) {
-		if barrier := d.findBarrierByID("confrontational_showoff"); barrier != nil {
-			detected = append(detected, DetectedBarrier{
-				Barrier:    *barrier,
-				Confidence: 0.7,
-				Reasoning:  []string{"Confrontational or dismissive language detected"},
-			})
-		}
-	}
+// Detect finds barriers and ETPs in the input
+// Rule matches and nearest-neighbour matches are combined as independent
+// evidence; a neighbour match only counts when no verbal rule fired
+func (d *BarrierDetector) Detect(ctx context.Context, input string) Detection {
+	classification := d.classify(ctx, input)
 
-	// Check for minimal response (silent avoider)
-	if d.isMinimalResponse(input) {
-		if barrier := d.findBarrierByID("silent_avoider"); barrier != nil {
-			detected = append(detected, DetectedBarrier{
-				Barrier:    *barrier,
-				Confidence: 0.6,
-				Reasoning:  []string{"Minimal engagement, very short response"},
-			})
-		}
+	fired := map[string][]DetectionRule{}
+	for _, match := range d.rules.Evaluate(input) {
+		fired[match.BarrierID] = match.Fired
 	}
 
-	// Check for playful avoidance
-	if d.isPlayfulAvoider(input) {
-		if barrier := d.findBarrierByID("quiet_playful_avoider"); barrier != nil {
-			detected = append(detected, DetectedBarrier{
-				Barrier:    *barrier,
-				Confidence: 0.65,
-				Reasoning:  []string{"Playful or off-topic responses"},
-			})
+	detected := []DetectedBarrier{}
+	for _, barrierID := range d.rules.order {
+		rules := fired[barrierID]
+		confidence := aggregateConfidence(rules)
+		reasoning := ruleReasons(rules)
+
+		if classification != nil && !verbalFired(rules) {
+			if score, ok := classification.Label(barrierID); ok && score.Confidence > 0 {
+				weight := embeddingWeight(d.Profile(barrierID)) * score.Confidence
+				confidence = 1 - (1-confidence)*(1-weight)
+				reasoning = append(reasoning,
+					fmt.Sprintf("Sounds like %q (%.0f%% similar)", score.Nearest, score.Similarity*100))
+			}
 		}
-	}
-
-	// Check for high achiever boredom
-	if d.indicatesBoredom(input, context) {
-		if barrier := d.findBarrierByID("high_achiever_underengaged"); barrier != nil {
-			detected = append(detected, DetectedBarrier{
-				Barrier:    *barrier,
-				Confidence: 0.7,
-				Reasoning:  []string{"Indicates boredom or unchallenging material"},
-			})
+		if confidence < minDetectionConfidence {
+			continue
 		}
-	}
-
-	return detected
-}
-
-func (d *BarrierDetector) containsIDontKnow(input string) bool {
-	patterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)i don'?t know`),
-		regexp.MustCompile(`(?i)idk`),
-		regexp.MustCompile(`(?i)dunno`),
-		regexp.MustCompile(`(?i)no idea`),
-	}
 
-	for _, pattern := range patterns {
-		if pattern.MatchString(input) {
-			return true
+		barrier := d.findBarrierByID(barrierID)
+		if barrier == nil {
+			continue
 		}
-	}
-	return false
-}
 
-func (d *BarrierDetector) isConfrontational(input string) bool {
-	patterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)this is (stupid|dumb|boring)`),
-		regexp.MustCompile(`(?i)why (do|should) i`),
-		regexp.MustCompile(`(?i)i don'?t (care|want to)`),
-		regexp.MustCompile(`(?i)whatever`),
-		regexp.MustCompile(`(?i)so what`),
-		regexp.MustCompile(`(?i)make me`),
+		detected = append(detected, DetectedBarrier{
+			Barrier:    *barrier,
+			Profile:    d.Profile(barrier.ID),
+			Confidence: confidence,
+			Reasoning:  reasoning,
+		})
 	}
+	sort.SliceStable(detected, func(i, j int) bool {
+		return detected[i].Confidence > detected[j].Confidence
+	})
 
-	for _, pattern := range patterns {
-		if pattern.MatchString(input) {
-			return true
-		}
-	}
-	return false
+	return Detection{Barriers: detected, ETPs: d.detectedETPs(classification)}
 }
 
-func (d *BarrierDetector) isMinimalResponse(input string) bool {
-	trimmed := strings.TrimSpace(input)
-	return len(trimmed) < 10 && !d.containsIDontKnow(input)
-}
-
-func (d *BarrierDetector) isPlayfulAvoider(input string) bool {
-	playfulMarkers := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)(haha|lol|lmao)`),
-		regexp.MustCompile(`(?i)can we (play|do something else)`),
-		regexp.MustCompile(`😀|😂|🎮|🎲`),
-	}
-
-	for _, marker := range playfulMarkers {
-		if marker.MatchString(input) {
-			return true
+// detectedETPs keeps the classifier's confident ETP tags
+// A tag counts no more than a match for the barrier it came from would, scaled
+// by how central the ETP is to that barrier: exemplars carry all of a barrier's
+// ETPs, so the barrier's own ordering is what tells them apart
+func (d *BarrierDetector) detectedETPs(classification *nlp.Classification) []DetectedETP {
+	etps := []DetectedETP{}
+	if classification == nil {
+		return etps
+	}
+	for _, tag := range classification.Tags {
+		profile := d.Profile(tag.Label)
+		if profile == nil {
+			continue
+		}
+		weight := embeddingWeight(profile) * profile.UnderlyingCauses.ETPWeight(tag.Name)
+		confidence := math.Round(tag.Confidence*weight*100) / 100
+		if confidence < minETPConfidence {
+			continue
 		}
+		etps = append(etps, DetectedETP{Name: tag.Name, Confidence: confidence, Nearest: tag.Nearest})
 	}
-	return false
+	sort.SliceStable(etps, func(i, j int) bool {
+		return etps[i].Confidence > etps[j].Confidence
+	})
+	return etps
 }
 
-func (d *BarrierDetector) indicatesBoredom(input string, context etp.StudentContext) bool {
-	boredomMarkers := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)this is (too )?easy`),
-		regexp.MustCompile(`(?i)i (already )?know this`),
-		regexp.MustCompile(`(?i)when do we do something interesting`),
-		regexp.MustCompile(`(?i)can i do something else`),
-	}
+// Rules exposes the compiled detection rules for a barrier
+func (d *BarrierDetector) Rules(barrierID string) []DetectionRule {
+	return d.rules.Rules(barrierID)
+}
 
-	for _, marker := range boredomMarkers {
-		if marker.MatchString(input) {
-			return true
+// ruleReasons lists each distinct reason once, in firing order
+func ruleReasons(fired []DetectionRule) []string {
+	reasons := []string{}
+	seen := map[string]bool{}
+	for _, rule := range fired {
+		if rule.Reason == "" || seen[rule.Reason] {
+			continue
 		}
+		seen[rule.Reason] = true
+		reasons = append(reasons, rule.Reason)
 	}
-	return false
+	return reasons
 }
 
 func (d *BarrierDetector) findBarrierByID(id string) *etp.StudentBarrier {
@@ -0,0 +1,191 @@
+package barriers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/mike5tew/humanos/internal/nlp"
+)
+
+const barriersSchemaPath = "../../../shared/schemas/barriers.json"
+
+// newDetector loads the barrier schema, with the classifier indexed when given
+func newDetector(t *testing.T, classifier *nlp.Classifier) *BarrierDetector {
+	t.Helper()
+	d, err := NewBarrierDetector(barriersSchemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.UseClassifier(context.Background(), classifier); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func localClassifier(t *testing.T) *nlp.Classifier {
+	t.Helper()
+	classifier, err := nlp.New(nlp.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return classifier
+}
+
+func confidences(detection Detection) map[string]float64 {
+	out := map[string]float64{}
+	for _, b := range detection.Barriers {
+		out[b.Barrier.ID] = b.Confidence
+	}
+	return out
+}
+
+// TestDetectBlendsRulesWithNeighbours checks every barrier's confidence is the
+// rule engine's score noisy-ORed with the weighted neighbour match, and that
+// the neighbour match is not counted when a verbal rule already fired
+func TestDetectBlendsRulesWithNeighbours(t *testing.T) {
+	classifier := localClassifier(t)
+	d := newDetector(t, classifier)
+	ctx := context.Background()
+
+	messages := []string{
+		"can I maybe do it later on",       // Weak rule evidence, lifted by a paraphrase
+		"i dont know really",               // Signal rule plus a neighbour
+		"why should I even bother with it", // Verbal rule: the neighbour adds nothing
+		"Can we do fractions today?",       // Neither
+	}
+	for _, message := range messages {
+		classification, err := classifier.Classify(ctx, message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fired := map[string][]DetectionRule{}
+		for _, match := range d.rules.Evaluate(message) {
+			fired[match.BarrierID] = match.Fired
+		}
+		got := confidences(d.Detect(ctx, message))
+
+		for _, barrierID := range d.rules.order {
+			want := aggregateConfidence(fired[barrierID])
+			if score, ok := classification.Label(barrierID); ok && !verbalFired(fired[barrierID]) {
+				weight := embeddingWeight(d.Profile(barrierID)) * score.Confidence
+				want = 1 - (1-want)*(1-weight)
+			}
+			if want < minDetectionConfidence {
+				if confidence, ok := got[barrierID]; ok {
+					t.Errorf("%q: %s detected at %.2f, want below threshold (%.2f)", message, barrierID, confidence, want)
+				}
+				continue
+			}
+			if math.Abs(got[barrierID]-want) > 1e-9 {
+				t.Errorf("%q: %s confidence = %.4f, want %.4f", message, barrierID, got[barrierID], want)
+			}
+		}
+	}
+}
+
+func TestDetectNeighboursFindParaphrases(t *testing.T) {
+	regexOnly := newDetector(t, nil)
+	blended := newDetector(t, localClassifier(t))
+	ctx := context.Background()
+
+	message := "can I maybe do it later on"
+	if _, ok := confidences(regexOnly.Detect(ctx, message))["lack_of_motivation"]; ok {
+		t.Fatalf("%q: rules alone already detect lack_of_motivation; pick a looser paraphrase", message)
+	}
+	detection := blended.Detect(ctx, message)
+	if _, ok := confidences(detection)["lack_of_motivation"]; !ok {
+		t.Fatalf("%q: blended detection missed lack_of_motivation: %+v", message, detection.Barriers)
+	}
+
+	message = "why should I even bother with it"
+	want := confidences(regexOnly.Detect(ctx, message))
+	got := confidences(blended.Detect(ctx, message))
+	if got["confrontational_showoff"] != want["confrontational_showoff"] {
+		t.Errorf("%q: confidence = %.2f with neighbours, %.2f without; a verbal match should not count twice",
+			message, got["confrontational_showoff"], want["confrontational_showoff"])
+	}
+
+	if detection := blended.Detect(ctx, "Can we do fractions today?"); len(detection.Barriers) != 0 || len(detection.ETPs) != 0 {
+		t.Errorf("neutral message detected %+v", detection)
+	}
+}
+
+func TestDetectFallsBackToRulesWhenIndexFails(t *testing.T) {
+	stub, server := nlp.StartWeaviateStub()
+	defer server.Close()
+
+	cfg := nlp.DefaultConfig()
+	cfg.Backend = nlp.BackendWeaviate
+	cfg.WeaviateURL = server.URL
+	classifier, err := nlp.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blended := newDetector(t, classifier)
+	regexOnly := newDetector(t, nil)
+	ctx := context.Background()
+
+	message := "can I maybe do it later on"
+	if _, ok := confidences(blended.Detect(ctx, message))["lack_of_motivation"]; !ok {
+		t.Fatalf("%q: Weaviate-backed detection missed lack_of_motivation", message)
+	}
+
+	stub.FailWith(http.StatusServiceUnavailable)
+	for _, message := range []string{message, "i dont know really"} {
+		got := confidences(blended.Detect(ctx, message))
+		want := confidences(regexOnly.Detect(ctx, message))
+		if len(got) != len(want) {
+			t.Fatalf("%q: with Weaviate down detected %v, want rules only %v", message, got, want)
+		}
+		for id, confidence := range want {
+			if got[id] != confidence {
+				t.Errorf("%q: %s = %.2f with Weaviate down, want %.2f", message, id, got[id], confidence)
+			}
+		}
+	}
+}
+
+// TestDetectWeightsETPsByBarrierOrder checks an exemplar's ETP tags are not
+// reported alike: each is scaled by its place in the barrier's etpActivation
+func TestDetectWeightsETPsByBarrierOrder(t *testing.T) {
+	classifier := localClassifier(t)
+	d := newDetector(t, classifier)
+	ctx := context.Background()
+
+	for _, message := range []string{"Make me", "This is too easy", "can I maybe do it later on"} {
+		classification, err := classifier.Classify(ctx, message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]float64{}
+		for _, tag := range classification.Tags {
+			profile := d.Profile(tag.Label)
+			confidence := math.Round(tag.Confidence*embeddingWeight(profile)*profile.UnderlyingCauses.ETPWeight(tag.Name)*100) / 100
+			if confidence >= minETPConfidence {
+				want[tag.Name] = confidence
+			}
+		}
+
+		got := d.Detect(ctx, message).ETPs
+		if len(got) != len(want) {
+			t.Fatalf("%q: ETPs = %+v, want %v", message, got, want)
+		}
+		for _, e := range got {
+			if e.Confidence != want[e.Name] {
+				t.Errorf("%q: %s = %.2f, want %.2f", message, e.Name, e.Confidence, want[e.Name])
+			}
+		}
+	}
+
+	// One barrier's ETPs fall in the order the schema lists them
+	causes := d.Profile("confrontational_showoff").UnderlyingCauses
+	names := causes.ETPNames()
+	for i := 1; i < len(names); i++ {
+		if causes.ETPWeight(names[i]) >= causes.ETPWeight(names[i-1]) {
+			t.Errorf("%s weighs %.2f, not below %s at %.2f",
+				names[i], causes.ETPWeight(names[i]), names[i-1], causes.ETPWeight(names[i-1]))
+		}
+	}
+}
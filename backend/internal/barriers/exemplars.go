@@ -0,0 +1,86 @@
+package barriers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/nlp"
+)
+
+// Exemplars are the barriers' verbal manifestations as labelled examples
+// Each is labelled with its barrier and tagged with the ETPs that barrier
+// activates; detection weights the tags by the barrier's ETP order. Lines are read as the verbal rules read them: quoted speech,
+// or the line itself unless it describes a message feature
+func (d *BarrierDetector) Exemplars() []nlp.Exemplar {
+	exemplars := []nlp.Exemplar{}
+	for _, profile := range d.profiles {
+		etps := profile.UnderlyingCauses.ETPNames()
+		for i, verbal := range profile.Manifestations.Verbal {
+			phrases := quotedPhrases(verbal)
+			if len(phrases) == 0 && len(matchersForSignal(verbal)) == 0 {
+				phrases = []string{verbal}
+			}
+			for j, phrase := range phrases {
+				exemplars = append(exemplars, nlp.Exemplar{
+					ID:    fmt.Sprintf("%s/verbal/%d/%d", profile.ID, i, j),
+					Text:  phrase,
+					Label: profile.ID,
+					Tags:  etps,
+				})
+			}
+		}
+	}
+	return exemplars
+}
+
+// UseClassifier indexes the exemplars and blends nearest-neighbour matches
+// into detection; on error detection stays regex-only
+func (d *BarrierDetector) UseClassifier(ctx context.Context, classifier *nlp.Classifier) error {
+	if classifier == nil {
+		d.classifier = nil
+		return nil
+	}
+	if err := classifier.Index(ctx, d.Exemplars()); err != nil {
+		return err
+	}
+	d.classifier = classifier
+	return nil
+}
+
+// Classifier is the nearest-neighbour classifier in use, or nil
+func (d *BarrierDetector) Classifier() *nlp.Classifier {
+	return d.classifier
+}
+
+// classify asks the classifier about the input; nil when there is none or it failed
+func (d *BarrierDetector) classify(ctx context.Context, input string) *nlp.Classification {
+	if d.classifier == nil || strings.TrimSpace(input) == "" {
+		return nil
+	}
+	classification, err := d.classifier.Classify(ctx, input)
+	if err != nil {
+		log.Printf("🔤 Embedding classifier %s failed, using regex only: %v", d.classifier.Name(), err)
+		return nil
+	}
+	return classification
+}
+
+// embeddingWeight is how much a full-confidence neighbour match counts for a barrier
+func embeddingWeight(profile *etp.BarrierStudentProfile) float64 {
+	weights := profile.AICoachImplementation.DetectionWeights
+	return weightOrDefault(weights.Embedding, weightOrDefault(weights.Verbal, defaultVerbalWeight))
+}
+
+// verbalFired reports whether a verbal rule already matched, in which case
+// a neighbour match is the same evidence and is not counted again
+func verbalFired(fired []DetectionRule) bool {
+	for _, rule := range fired {
+		if rule.Source == SourceVerbal {
+			return true
+		}
+	}
+	return false
+}
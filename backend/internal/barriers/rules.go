@@ -0,0 +1,236 @@
+package barriers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// Default rule weights when a barrier does not set detectionWeights
+const (
+	defaultVerbalWeight  = 0.6
+	defaultSignalWeight  = 0.5
+	defaultPatternWeight = 0.7
+
+	// Barriers below this aggregated confidence are not reported, so a
+	// single descriptive signal is not enough on its own
+	minDetectionConfidence = 0.55
+)
+
+// Rule sources
+const (
+	SourcePattern = "pattern" // aiCoachImplementation.detectionPatterns
+	SourceVerbal  = "verbal"  // manifestations.verbal
+	SourceSignal  = "signal"  // aiCoachImplementation.detectionSignals
+)
+
+// DetectionRule is one weighted matcher for a barrier
+type DetectionRule struct {
+	BarrierID string  `json:"barrier_id"`
+	Source    string  `json:"source"`
+	Key       string  `json:"key"` // Dedup key: regex source or feature name
+	Weight    float64 `json:"weight"`
+	Reason    string  `json:"reason"`
+
+	match func(input string) bool
+}
+
+// RuleEngine scores barriers by combining the rules that fire
+type RuleEngine struct {
+	rules     map[string][]DetectionRule // keyed by barrier ID
+	order     []string                   // barrier IDs in schema order
+	unmatched []UnmatchedSignal
+}
+
+// UnmatchedSignal is a schema detection signal no message feature covers
+type UnmatchedSignal struct {
+	BarrierID string
+	Signal    string
+}
+
+// NewRuleEngine compiles detection rules for every barrier profile
+func NewRuleEngine(profiles []etp.BarrierStudentProfile) (*RuleEngine, error) {
+	engine := &RuleEngine{rules: map[string][]DetectionRule{}}
+
+	for i := range profiles {
+		rules, err := buildRules(&profiles[i])
+		if err != nil {
+			return nil, err
+		}
+		engine.rules[profiles[i].ID] = rules
+		engine.order = append(engine.order, profiles[i].ID)
+
+		for _, signal := range profiles[i].AICoachImplementation.DetectionSignals {
+			if len(quotedPhrases(signal)) == 0 && len(matchersForSignal(signal)) == 0 {
+				engine.unmatched = append(engine.unmatched, UnmatchedSignal{BarrierID: profiles[i].ID, Signal: signal})
+			}
+		}
+	}
+
+	return engine, nil
+}
+
+// Unmatched lists detection signals that produced no rule
+// They need a cue in signalVocabulary, or quoted speech, before they can fire
+func (re *RuleEngine) Unmatched() []UnmatchedSignal {
+	return re.unmatched
+}
+
+// Rules returns the compiled rules for one barrier
+func (re *RuleEngine) Rules(barrierID string) []DetectionRule {
+	return re.rules[barrierID]
+}
+
+// RuleMatch is the outcome of evaluating one barrier
+type RuleMatch struct {
+	BarrierID  string
+	Confidence float64
+	Fired      []DetectionRule
+}
+
+// Evaluate scores every barrier against the input, highest confidence first
+func (re *RuleEngine) Evaluate(input string) []RuleMatch {
+	matches := []RuleMatch{}
+
+	for _, barrierID := range re.order {
+		fired := []DetectionRule{}
+		for _, rule := range re.rules[barrierID] {
+			if rule.match(input) {
+				fired = append(fired, rule)
+			}
+		}
+		if len(fired) == 0 {
+			continue
+		}
+
+		matches = append(matches, RuleMatch{
+			BarrierID:  barrierID,
+			Confidence: aggregateConfidence(fired),
+			Fired:      fired,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Confidence > matches[j].Confidence
+	})
+	return matches
+}
+
+// aggregateConfidence combines independent evidence (noisy-OR)
+// One rule at weight w gives w; each extra rule closes part of the remaining gap
+func aggregateConfidence(fired []DetectionRule) float64 {
+	miss := 1.0
+	for _, rule := range fired {
+		miss *= 1.0 - rule.Weight
+	}
+	return 1.0 - miss
+}
+
+// buildRules compiles one barrier's schema entries into rules
+func buildRules(profile *etp.BarrierStudentProfile) ([]DetectionRule, error) {
+	weights := profile.AICoachImplementation.DetectionWeights
+	verbalWeight := weightOrDefault(weights.Verbal, defaultVerbalWeight)
+	signalWeight := weightOrDefault(weights.Signal, defaultSignalWeight)
+
+	rules := []DetectionRule{}
+	index := map[string]int{}
+
+	// add keeps one rule per key, preferring the higher weight
+	add := func(rule DetectionRule) {
+		if i, exists := index[rule.Key]; exists {
+			if rule.Weight > rules[i].Weight {
+				rules[i] = rule
+			}
+			return
+		}
+		index[rule.Key] = len(rules)
+		rules = append(rules, rule)
+	}
+
+	// Explicit patterns
+	for _, pattern := range profile.AICoachImplementation.DetectionPatterns {
+		regex, err := regexp.Compile("(?i)" + pattern.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("barrier %s: bad detection pattern %q: %w", profile.ID, pattern.Pattern, err)
+		}
+		add(DetectionRule{
+			BarrierID: profile.ID,
+			Source:    SourcePattern,
+			Key:       regex.String(),
+			Weight:    weightOrDefault(pattern.Weight, defaultPatternWeight),
+			Reason:    pattern.Reason,
+			match:     regex.MatchString,
+		})
+	}
+
+	// Verbal manifestations are either speech or a description of speech
+	for _, verbal := range profile.Manifestations.Verbal {
+		if err := addDescribedRules(add, profile.ID, SourceVerbal, verbal, verbalWeight, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// Detection signals are always descriptions
+	for _, signal := range profile.AICoachImplementation.DetectionSignals {
+		if err := addDescribedRules(add, profile.ID, SourceSignal, signal, signalWeight, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+// addDescribedRules turns one schema line into phrase and feature rules
+// When literal is set and nothing else applies, the line itself is the phrase
+func addDescribedRules(
+	add func(DetectionRule),
+	barrierID string,
+	source string,
+	text string,
+	weight float64,
+	literal bool,
+) error {
+	phrases := quotedPhrases(text)
+	features := matchersForSignal(text)
+
+	if len(phrases) == 0 && len(features) == 0 && literal {
+		phrases = []string{text}
+	}
+
+	for _, phrase := range phrases {
+		regex, err := phraseRegex(phrase)
+		if err != nil {
+			return fmt.Errorf("barrier %s: cannot build matcher for %q: %w", barrierID, phrase, err)
+		}
+		add(DetectionRule{
+			BarrierID: barrierID,
+			Source:    source,
+			Key:       regex.String(),
+			Weight:    weight,
+			Reason:    fmt.Sprintf("Said something like %q (%s)", phrase, source),
+			match:     regex.MatchString,
+		})
+	}
+
+	for _, feature := range features {
+		add(DetectionRule{
+			BarrierID: barrierID,
+			Source:    source,
+			Key:       "feature:" + feature.Key,
+			Weight:    weight,
+			Reason:    text,
+			match:     feature.Match,
+		})
+	}
+
+	return nil
+}
+
+func weightOrDefault(weight, fallback float64) float64 {
+	if weight <= 0 || weight > 1 {
+		return fallback
+	}
+	return weight
+}
@@ -0,0 +1,28 @@
+package barriers
+
+import (
+	"testing"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+func TestRuleEngineReportsUnmatchedSignals(t *testing.T) {
+	profile := etp.BarrierStudentProfile{ID: "silent_avoider"}
+	profile.AICoachImplementation.DetectionSignals = []string{
+		"One-word answers",             // Known feature
+		"Says 'I dunno' a lot",         // Quoted speech
+		"Long delays before answering", // Needs timing data
+	}
+
+	engine, err := NewRuleEngine([]etp.BarrierStudentProfile{profile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmatched := engine.Unmatched()
+	if len(unmatched) != 1 || unmatched[0].Signal != "Long delays before answering" || unmatched[0].BarrierID != "silent_avoider" {
+		t.Fatalf("unmatched = %+v, want only the timing signal", unmatched)
+	}
+	if got := len(engine.Rules("silent_avoider")); got != 2 {
+		t.Errorf("%d rules built, want 2", got)
+	}
+}
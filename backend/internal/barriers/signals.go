@@ -0,0 +1,192 @@
+package barriers
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// signalMatcher detects one observable message feature
+type signalMatcher struct {
+	Key   string
+	Cues  []string // Lowercase phrases that map schema text onto this feature
+	Match func(input string) bool
+}
+
+var (
+	playfulRegex    = regexp.MustCompile(`(?i)\b(haha+|lol|lmao|jk|hehe+)\b`)
+	emojiRegex      = regexp.MustCompile(`[\x{1F300}-\x{1FAFF}\x{2600}-\x{27BF}]`)
+	delayRegex      = regexp.MustCompile(`(?i)\b(later|tomorrow|not now|do (this|it) after|can i stop)\b`)
+	elseRegex       = regexp.MustCompile(`(?i)can (we|i) (do something else|do something different|do a different)`)
+	answerRegex     = regexp.MustCompile(`(?i)(just )?(tell|give|show) me the answer|what'?s the answer|what is the answer`)
+	refusalRegex    = regexp.MustCompile(`(?i)\b(i'?m not doing|i won'?t|not doing (this|it)|i refuse|no way)\b`)
+	dismissiveRegex = regexp.MustCompile(`(?i)\b((this|that)('?s| is) (stupid|dumb|pointless|rubbish)|whatever|so what|who cares|i don'?t care)\b`)
+	boredomRegex    = regexp.MustCompile(`(?i)\b(bored|boring|too easy|so easy)\b`)
+	harderRegex     = regexp.MustCompile(`(?i)\b(harder|more challenging|something (more )?(difficult|challenging|interesting))\b`)
+	scopeRegex      = regexp.MustCompile(`(?i)why are we (learning|doing) this|used for in real life|what'?s (this|it) used for`)
+	paceRegex       = regexp.MustCompile(`(?i)\b(too slow|so slow|hurry up|already did this|can we move on)\b`)
+)
+
+// signalVocabulary maps the plain-English signals used in barriers.json onto
+// message features. Signals that need timing or task data (e.g. "Long delays
+// before answering") have no matcher here; NewBarrierDetector logs them at load.
+var signalVocabulary = []signalMatcher{
+	{
+		Key:   "one_word",
+		Cues:  []string{"one-word", "one word"},
+		Match: func(input string) bool { return len(strings.Fields(input)) == 1 },
+	},
+	{
+		Key:   "minimal_response",
+		Cues:  []string{"minimal response", "minimal responses", "minimal effort", "very little speech", "very short"},
+		Match: func(input string) bool { return len(strings.TrimSpace(input)) < 10 },
+	},
+	{
+		Key:   "playful_language",
+		Cues:  []string{"playful", "jokes", "silly"},
+		Match: playfulRegex.MatchString,
+	},
+	{
+		Key:   "fidget_text",
+		Cues:  []string{"emojis", "random characters"},
+		Match: func(input string) bool { return emojiRegex.MatchString(input) || hasCharacterRun(input, 4) },
+	},
+	{
+		Key:   "task_delay",
+		Cues:  []string{"task delay", "requests to stop"},
+		Match: delayRegex.MatchString,
+	},
+	{
+		Key:   "something_else",
+		Cues:  []string{"something else"},
+		Match: elseRegex.MatchString,
+	},
+	{
+		Key:   "asks_for_answer",
+		Cues:  []string{"asks for answer"},
+		Match: answerRegex.MatchString,
+	},
+	{
+		Key:   "refusal",
+		Cues:  []string{"refusal"},
+		Match: refusalRegex.MatchString,
+	},
+	{
+		Key:   "dismissive",
+		Cues:  []string{"dismissive", "confrontational"},
+		Match: dismissiveRegex.MatchString,
+	},
+	{
+		Key:   "explicit_boredom",
+		Cues:  []string{"boredom"},
+		Match: boredomRegex.MatchString,
+	},
+	{
+		Key:   "wants_harder",
+		Cues:  []string{"harder material"},
+		Match: harderRegex.MatchString,
+	},
+	{
+		Key:   "beyond_scope",
+		Cues:  []string{"beyond scope"},
+		Match: scopeRegex.MatchString,
+	},
+	{
+		Key:   "pace_frustration",
+		Cues:  []string{"frustration with pace"},
+		Match: paceRegex.MatchString,
+	},
+}
+
+// matchersForSignal returns every feature a schema signal refers to
+func matchersForSignal(signal string) []signalMatcher {
+	lower := strings.ToLower(signal)
+	matched := []signalMatcher{}
+
+	for _, matcher := range signalVocabulary {
+		for _, cue := range matcher.Cues {
+			if mentionsCue(lower, cue) {
+				matched = append(matched, matcher)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// mentionsCue finds a cue that is not negated ("soft, not confrontational")
+func mentionsCue(text, cue string) bool {
+	offset := 0
+	for {
+		idx := strings.Index(text[offset:], cue)
+		if idx < 0 {
+			return false
+		}
+		before := strings.TrimSpace(text[:offset+idx])
+		if !strings.HasSuffix(before, "not") && !strings.HasSuffix(before, "no") {
+			return true
+		}
+		offset += idx + len(cue)
+	}
+}
+
+// quotedPhrases extracts 'quoted speech' from a schema description
+// Apostrophes inside words ("don't") are not treated as quote marks
+func quotedPhrases(text string) []string {
+	runes := []rune(text)
+	isLetter := func(i int) bool {
+		return i >= 0 && i < len(runes) && unicode.IsLetter(runes[i])
+	}
+
+	phrases := []string{}
+	start := -1
+	for i, r := range runes {
+		if r != '\'' {
+			continue
+		}
+		opening := !isLetter(i - 1)
+		closing := !isLetter(i + 1)
+
+		switch {
+		case start < 0 && opening:
+			start = i + 1
+		case start >= 0 && closing:
+			if phrase := strings.TrimSpace(string(runes[start:i])); len(phrase) >= 3 {
+				phrases = append(phrases, phrase)
+			}
+			start = -1
+		}
+	}
+	return phrases
+}
+
+var apostropheReplacer = strings.NewReplacer("'", "['’]?", "’", "['’]?")
+
+// phraseRegex turns spoken text into a tolerant matcher
+// Case, optional apostrophes, spacing and trailing punctuation are ignored
+func phraseRegex(phrase string) (*regexp.Regexp, error) {
+	phrase = strings.TrimSpace(strings.TrimRight(phrase, "?!. "))
+	words := strings.Fields(strings.ToLower(phrase))
+	for i, word := range words {
+		words[i] = apostropheReplacer.Replace(regexp.QuoteMeta(word))
+	}
+	return regexp.Compile(`(?i)\b` + strings.Join(words, `\s+`) + `\b`)
+}
+
+// hasCharacterRun reports keyboard mashing such as "!!!!" or "aaaaa"
+func hasCharacterRun(input string, length int) bool {
+	run := 1
+	var prev rune
+	for i, r := range input {
+		if i > 0 && r == prev && !unicode.IsSpace(r) {
+			run++
+			if run >= length {
+				return true
+			}
+		} else {
+			run = 1
+		}
+		prev = r
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package coach
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+var (
+	negativeAffectRegex = regexp.MustCompile(`(?i)\b(hate|angry|mad|furious|annoy(ed|ing)?|frustrat(ed|ing)|upset|scared|afraid|worried|anxious|nervous|stress(ed)?|panic(king)?|cry(ing)?|sad|stupid|dumb|useless|hopeless|awful|terrible|ugh+|argh+|overwhelmed|embarrass(ed|ing)|give up|not fair|can'?t do (this|it))\b`)
+	fatigueRegex        = regexp.MustCompile(`(?i)\b(tired|sleepy|exhausted|knackered|yawn(ing)?|worn out|zzz+|no energy|headache|can'?t (concentrate|focus)|need a (break|rest))\b`)
+	hungerRegex         = regexp.MustCompile(`(?i)\b(hungry|starving|thirsty|haven'?t eaten|need (food|a drink|a snack)|need the toilet|need a wee)\b`)
+)
+
+// BrainStateConfig tunes how message features and history move the estimate
+type BrainStateConfig struct {
+	Baseline  etp.BrainState // Calm, fed and rested; only primal and emotional are used
+	HalfLife  time.Duration  // An earlier turn's pull away from baseline halves over this
+	RiseRate  float64        // Share of a higher reading taken at once
+	FallRate  float64        // Share of a lower reading taken at once, so upsets linger
+	SlowReply time.Duration  // Slower replies suggest flagging attention
+	AwayAfter time.Duration  // Longer gaps are a new sitting, not a slow reply
+	FastReply time.Duration  // Short replies faster than this suggest reacting, not thinking
+	Diverges  float64        // Client values further off than this are reported in reasoning
+}
+
+// DefaultBrainStateConfig returns the standard tuning
+func DefaultBrainStateConfig() BrainStateConfig {
+	return BrainStateConfig{
+		Baseline:  etp.BrainState{PrimalLevel: 0.2, EmotionalLevel: 0.2},
+		HalfLife:  10 * time.Minute,
+		RiseRate:  0.7,
+		FallRate:  0.35,
+		SlowReply: 3 * time.Minute,
+		AwayAfter: 30 * time.Minute,
+		FastReply: 4 * time.Second,
+		Diverges:  0.3,
+	}
+}
+
+// BrainStateEstimate is the server's reading of the student for one turn
+type BrainStateEstimate struct {
+	State   etp.BrainState `json:"state"`
+	Signals []string       `json:"signals"` // What in the message moved it
+
+	// Client is what the frontend sent, when it sent anything; Divergence is
+	// the estimate minus Client, level by level
+	Client        *etp.BrainState `json:"client,omitempty"`
+	Divergence    *etp.BrainState `json:"divergence,omitempty"`
+	MaxDivergence float64         `json:"max_divergence,omitempty"`
+}
+
+// BrainStateEstimator infers brain state from message content and the
+// session's earlier estimates instead of trusting client-supplied numbers
+// Primal and emotional levels are tracked turn to turn and fade back to
+// baseline over time; rational level and override risk follow from them
+type BrainStateEstimator struct {
+	config BrainStateConfig
+	now    func() time.Time
+}
+
+// NewBrainStateEstimator creates an estimator
+func NewBrainStateEstimator(cfg BrainStateConfig) *BrainStateEstimator {
+	return &BrainStateEstimator{config: cfg, now: time.Now}
+}
+
+// Estimate reads one message in the light of the session so far
+// client is the frontend's BrainState; all zeros means it sent none
+func (e *BrainStateEstimator) Estimate(session *Session, message string, client etp.BrainState) BrainStateEstimate {
+	now := e.now()
+	prior, latency := e.prior(session, now)
+	primalEvidence, emotionalEvidence, signals := e.evidence(message, latency)
+
+	base := e.config.Baseline
+	primal := e.step(prior.PrimalLevel, base.PrimalLevel+(1-base.PrimalLevel)*primalEvidence)
+	emotional := e.step(prior.EmotionalLevel, base.EmotionalLevel+(1-base.EmotionalLevel)*emotionalEvidence)
+
+	estimate := BrainStateEstimate{
+		State: etp.BrainState{
+			PrimalLevel:    round2(primal),
+			EmotionalLevel: round2(emotional),
+			RationalLevel:  round2(clamp01(1 - 0.6*emotional - 0.4*primal)),
+			OverrideRisk:   round2(clamp01((0.7*emotional + 0.3*primal - 0.3) / 0.7)),
+		},
+		Signals: signals,
+	}
+
+	if client != (etp.BrainState{}) {
+		diff := etp.BrainState{
+			PrimalLevel:    round2(estimate.State.PrimalLevel - client.PrimalLevel),
+			EmotionalLevel: round2(estimate.State.EmotionalLevel - client.EmotionalLevel),
+			RationalLevel:  round2(estimate.State.RationalLevel - client.RationalLevel),
+			OverrideRisk:   round2(estimate.State.OverrideRisk - client.OverrideRisk),
+		}
+		estimate.Client = &client
+		estimate.Divergence = &diff
+		estimate.MaxDivergence = math.Max(
+			math.Max(math.Abs(diff.PrimalLevel), math.Abs(diff.EmotionalLevel)),
+			math.Max(math.Abs(diff.RationalLevel), math.Abs(diff.OverrideRisk)))
+	}
+	return estimate
+}
+
+// Diverges reports whether the client's values are far enough off to mention
+func (e *BrainStateEstimator) Diverges(estimate BrainStateEstimate) bool {
+	return estimate.Client != nil && estimate.MaxDivergence > e.config.Diverges
+}
+
+// prior is the last turn's estimate faded toward baseline by the time since,
+// and that time (zero on a session's first turn)
+func (e *BrainStateEstimator) prior(session *Session, now time.Time) (etp.BrainState, time.Duration) {
+	base := e.config.Baseline
+	if session == nil || len(session.Turns) == 0 {
+		return base, 0
+	}
+
+	last := session.Turns[len(session.Turns)-1]
+	elapsed := now.Sub(last.Timestamp)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	keep := 1.0
+	if e.config.HalfLife > 0 {
+		keep = math.Pow(0.5, elapsed.Seconds()/e.config.HalfLife.Seconds())
+	}
+	return etp.BrainState{
+		PrimalLevel:    base.PrimalLevel + keep*(last.BrainState.PrimalLevel-base.PrimalLevel),
+		EmotionalLevel: base.EmotionalLevel + keep*(last.BrainState.EmotionalLevel-base.EmotionalLevel),
+	}, elapsed
+}
+
+// step moves toward a reading, quickly up and slowly down
+func (e *BrainStateEstimator) step(prior, reading float64) float64 {
+	rate := e.config.FallRate
+	if reading > prior {
+		rate = e.config.RiseRate
+	}
+	return clamp01(prior + rate*(reading-prior))
+}
+
+// evidence scores the message's primal and emotional features, 0-1 each
+// Independent features combine as noisy-OR
+func (e *BrainStateEstimator) evidence(message string, latency time.Duration) (primal, emotional float64, signals []string) {
+	signals = []string{}
+	emotionalMiss, primalMiss := 1.0, 1.0
+
+	if ratio, letters := capsRatio(message); letters >= 6 && ratio >= 0.5 {
+		emotionalMiss *= 1 - 0.7*clamp01((ratio-0.5)/0.4)
+		signals = append(signals, fmt.Sprintf("shouting (%.0f%% capitals)", ratio*100))
+	}
+
+	if marks := strings.Count(message, "!"); marks >= 2 || hasMarkRun(message) {
+		score := 0.2 * float64(marks)
+		if hasMarkRun(message) {
+			score += 0.3
+		}
+		emotionalMiss *= 1 - 0.5*clamp01(score)
+		signals = append(signals, "emphatic punctuation")
+	}
+
+	if words := negativeAffectRegex.FindAllString(message, -1); len(words) > 0 {
+		emotionalMiss *= 1 - 0.8*(1-math.Pow(0.5, float64(len(words))))
+		signals = append(signals, "negative words: "+strings.ToLower(strings.Join(words, ", ")))
+	}
+
+	if cues := fatigueRegex.FindAllString(message, -1); len(cues) > 0 {
+		primalMiss *= 1 - 0.8*(1-math.Pow(0.4, float64(len(cues))))
+		signals = append(signals, "fatigue: "+strings.ToLower(strings.Join(cues, ", ")))
+	}
+	if cues := hungerRegex.FindAllString(message, -1); len(cues) > 0 {
+		primalMiss *= 1 - 0.8*(1-math.Pow(0.4, float64(len(cues))))
+		signals = append(signals, "hunger or thirst: "+strings.ToLower(strings.Join(cues, ", ")))
+	}
+
+	switch {
+	case latency <= 0, e.config.AwayAfter > 0 && latency > e.config.AwayAfter:
+	case e.config.SlowReply > 0 && latency > e.config.SlowReply:
+		primalMiss *= 1 - 0.3
+		signals = append(signals, fmt.Sprintf("slow reply (%s)", latency.Round(time.Second)))
+	case latency < e.config.FastReply && len(strings.Fields(message)) <= 3:
+		emotionalMiss *= 1 - 0.3
+		signals = append(signals, fmt.Sprintf("snap reply (%s)", latency.Round(100*time.Millisecond)))
+	}
+
+	return 1 - primalMiss, 1 - emotionalMiss, signals
+}
+
+// capsRatio is the share of letters that are capitals, and how many letters there are
+func capsRatio(message string) (float64, int) {
+	letters, upper := 0, 0
+	for _, r := range message {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters == 0 {
+		return 0, 0
+	}
+	return float64(upper) / float64(letters), letters
+}
+
+// hasMarkRun finds "?!", "!?" or three or more marks in a row
+func hasMarkRun(message string) bool {
+	run := 0
+	for _, r := range message {
+		if r != '!' && r != '?' {
+			run = 0
+			continue
+		}
+		run++
+		if run >= 3 {
+			return true
+		}
+	}
+	return strings.Contains(message, "?!") || strings.Contains(message, "!?")
+}
+
+func clamp01(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -39,12 +39,31 @@ type QuestionProgression struct {
 	SemanticDistance  int // Steps between question and answer
 }
 
+// Answer qualities recorded on QuestionProgression
+const (
+	AnswerEngaged    = "engaged"
+	AnswerStruggling = "struggling"
+	AnswerPaused     = "paused" // Safeguarding turn; says nothing about the work
+)
+
+// answerQuality grades a turn for the difficulty streak and mastery
+func answerQuality(safeguarding bool, barrierCount int) string {
+	switch {
+	case safeguarding:
+		return AnswerPaused
+	case barrierCount > 0:
+		return AnswerStruggling
+	default:
+		return AnswerEngaged
+	}
+}
+
 // NextQuestion selects appropriate difficulty level
 func (qp *QuestionProgression) NextQuestion(context etp.StudentContext) QuestionSpec {
 	spec := QuestionSpec{}
 
 	// If struggling: guide toward guaranteed win
-	if qp.LastAnswerQuality == "struggling" {
+	if qp.LastAnswerQuality == AnswerStruggling {
 		spec.Difficulty = 0.2     // Impossibly easy
 		spec.SemanticDistance = 1 // Direct recall
 		spec.Hint = "Think about what we just covered..."
@@ -66,10 +85,10 @@ func (qp *QuestionProgression) NextQuestion(context etp.StudentContext) Question
 
 // QuestionSpec defines question characteristics
 type QuestionSpec struct {
-	Difficulty       float64 // 0-1
-	SemanticDistance int     // 1-5: jumps between Q and A
-	Extrapolation    float64 // 0-1: recall vs novel application
-	Hint             string
+	Difficulty       float64 `json:"difficulty"`        // 0-1
+	SemanticDistance int     `json:"semantic_distance"` // 1-5: jumps between Q and A
+	Extrapolation    float64 `json:"extrapolation"`     // 0-1: recall vs novel application
+	Hint             string  `json:"hint,omitempty"`
 }
 
 // LearningJourneyStage represents progression through content
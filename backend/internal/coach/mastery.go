@@ -0,0 +1,81 @@
+package coach
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/mastery"
+)
+
+// MasterySource loads the per-topic estimates stored with a student's profile
+type MasterySource interface {
+	MasteryProfile(ctx context.Context, studentID string) (etp.MasteryProfile, error)
+}
+
+// MasteryEvidence is what one turn says about the student's grasp of its topic
+// Whoever persists the profile feeds Graded evidence to mastery.Estimator.Record
+type MasteryEvidence struct {
+	Topic      string  `json:"topic"`
+	Level      float64 `json:"level"`      // Estimate the turn was coached at
+	Estimated  bool    `json:"estimated"`  // False until the topic has history
+	Difficulty float64 `json:"difficulty"` // Of the question being answered
+	Quality    string  `json:"quality"`    // Answer quality, as on QuestionProgression
+	Score      float64 `json:"score"`      // 1 full attempt, 0.5 brief, 0 struggling
+}
+
+// Graded reports whether the turn was scored; a safeguarding turn is not
+func (e *MasteryEvidence) Graded() bool {
+	return e.Quality == AnswerEngaged || e.Quality == AnswerStruggling
+}
+
+// Observation converts the evidence for the estimator
+func (e *MasteryEvidence) Observation() mastery.Observation {
+	return mastery.Observation{Topic: e.Topic, Difficulty: e.Difficulty, Score: e.Score}
+}
+
+// gradeMastery scores the finished turn against the question it answers
+// The topic stage opened the evidence; turns without a topic say nothing
+func (o *Orchestrator) gradeMastery(turn *TurnState) {
+	evidence := turn.Response.Mastery
+	if evidence == nil {
+		return
+	}
+
+	evidence.Difficulty = answeredDifficulty(turn.Session, evidence.Topic, o.mastery.TargetDifficulty(evidence.Level))
+	evidence.Quality = answerQuality(turn.Response.SafeguardingAlert, len(turn.Barriers))
+	switch {
+	case evidence.Quality != AnswerEngaged:
+		evidence.Score = 0
+	case len(strings.TrimSpace(turn.Message)) >= attemptMinLength:
+		evidence.Score = 1
+	default:
+		evidence.Score = 0.5
+	}
+}
+
+// answeredDifficulty is the difficulty of the last question pitched on the
+// topic this session, or fallback when there was none
+func answeredDifficulty(session *Session, topic string, fallback float64) float64 {
+	for i := len(session.Turns) - 1; i >= 0; i-- {
+		response := session.Turns[i].Response
+		if response.Topic == topic && response.Question != nil {
+			return response.Question.Difficulty
+		}
+	}
+	return fallback
+}
+
+// masteryLevel looks up the student's level for a topic, falling back to the
+// estimator's starting level when nothing is stored or the store fails
+func (o *Orchestrator) masteryLevel(ctx context.Context, studentID, topic string) (float64, bool, error) {
+	if o.masterySource == nil {
+		return o.mastery.InitialLevel(), false, nil
+	}
+	profile, err := o.masterySource.MasteryProfile(ctx, studentID)
+	if err != nil {
+		return o.mastery.InitialLevel(), false, err
+	}
+	level, estimated := o.mastery.Level(profile, topic)
+	return level, estimated, nil
+}
@@ -2,31 +2,86 @@
 package coach
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/mike5tew/humanos/internal/age"
 	"github.com/mike5tew/humanos/internal/barriers"
+	"github.com/mike5tew/humanos/internal/curriculum"
 	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/generation"
+	"github.com/mike5tew/humanos/internal/integration"
+	"github.com/mike5tew/humanos/internal/mastery"
+	"github.com/mike5tew/humanos/internal/nlp"
+	"github.com/mike5tew/humanos/internal/readability"
+	"github.com/mike5tew/humanos/internal/rewards"
 	"github.com/mike5tew/humanos/internal/safeguarding"
 )
 
+// RewardMinter issues unlock codes for earned play breaks
+type RewardMinter interface {
+	Mint(studentID, earnedThrough string) (*rewards.Unlock, error)
+}
+
+// KnowledgeSource describes what a learning topic builds on (CHISG)
+type KnowledgeSource interface {
+	GetKnowledgeContext(ctx context.Context, topic string, studentLevel float64) (*integration.KnowledgeContext, error)
+}
+
 // Orchestrator coordinates all HumanOS components
+// Each message runs through the pipeline for the selected profile
 type Orchestrator struct {
 	barrierDetector *barriers.BarrierDetector
 	traumaDetector  *safeguarding.TraumaDetector
-	ageFilter       *barriers.AgeAppropriateness
+	language        *age.Adapter
+	readability     *readability.Checker
+	generator       generation.ResponseGenerator
+	sequences       *generation.Library
+	personalization *PersonalizationEngine
+	sessions        *SessionManager
+	rewards         RewardMinter
+	cases           *safeguarding.CaseManager
+	curriculum      *curriculum.Extractor
+	knowledge       KnowledgeSource
+	mastery         *mastery.Estimator
+	masterySource   MasterySource
+	brainState      *BrainStateEstimator
+	pipelines       map[string]*Pipeline
+	profile         string
 }
 
 // CoachResponse is what gets sent back to frontend
 type CoachResponse struct {
-	Message           string                 `json:"message"`
-	Intervention      *etp.InterventionLever `json:"intervention,omitempty"`
-	DetectedBarriers  []string               `json:"detected_barriers"`
-	SafeguardingAlert bool                   `json:"safeguarding_alert"`
-	RewardEarned      bool                   `json:"reward_earned"`
-	Reasoning         []string               `json:"reasoning"`
-	Timestamp         string                 `json:"timestamp"`
+	Message            string                        `json:"message"`
+	Intervention       *etp.InterventionLever        `json:"intervention,omitempty"`
+	Sequence           *generation.Cue               `json:"sequence,omitempty"`
+	FramingStrategy    string                        `json:"framing_strategy,omitempty"`
+	KnowledgeContext   *integration.KnowledgeContext `json:"knowledge_context,omitempty"`
+	KnowledgeStatus    string                        `json:"knowledge_status,omitempty"` // Set by the knowledge stage
+	Topic              string                        `json:"topic,omitempty"`
+	Topics             []curriculum.Match            `json:"topics,omitempty"` // Ranked; Topic is the first
+	Mastery            *MasteryEvidence              `json:"mastery,omitempty"`
+	Question           *QuestionSpec                 `json:"question,omitempty"`
+	DetectedBarriers   []string                      `json:"detected_barriers"`
+	DetectedBarrierIDs []string                      `json:"detected_barrier_ids"`
+	ETPs               []barriers.DetectedETP        `json:"etps,omitempty"`        // Emotional trigger points the message sounds like
+	BrainState         *BrainStateEstimate           `json:"brain_state,omitempty"` // Server estimate, and how far the client's differs
+	SafeguardingAlert  bool                          `json:"safeguarding_alert"`
+	RewardEarned       bool                          `json:"reward_earned"`
+	Reward             *rewards.Unlock               `json:"reward,omitempty"`
+	Adaptation         *age.Report                   `json:"adaptation,omitempty"`
+	Readability        *readability.Verdict          `json:"readability,omitempty"`
+	Reasoning          []string                      `json:"reasoning"`
+	Timestamp          string                        `json:"timestamp"`
+	SessionID          string                        `json:"session_id,omitempty"`
+	TurnIndex          int                           `json:"turn_index"`
+	Profile            string                        `json:"profile"`
+	Timings            []StageTiming                 `json:"timings,omitempty"`
 }
 
 // NewOrchestrator creates orchestrator with all components
@@ -41,96 +96,315 @@ func NewOrchestrator(barriersPath, traumaPath, agePath string) (*Orchestrator, e
 		return nil, fmt.Errorf("failed to load trauma detector: %w", err)
 	}
 
-	af, err := barriers.NewAgeAppropriateness(agePath)
+	la, err := age.NewAdapter(agePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load age filter: %w", err)
+		return nil, fmt.Errorf("failed to load age schema: %w", err)
 	}
 
-	return &Orchestrator{
+	sequences, err := generation.NewLibrary(bd.Profiles())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load response sequences: %w", err)
+	}
+
+	o := &Orchestrator{
 		barrierDetector: bd,
 		traumaDetector:  td,
-		ageFilter:       af,
-	}, nil
+		language:        la,
+		readability:     readability.NewChecker(nil),
+		generator:       generation.NewTemplateGenerator(),
+		sequences:       sequences,
+		personalization: NewPersonalizationEngine(),
+		sessions:        NewSessionManager(),
+		knowledge:       integration.NewCHISGClient(integration.DefaultCHISGConfig()),
+		mastery:         mastery.NewEstimator(mastery.DefaultConfig()),
+		brainState:      NewBrainStateEstimator(DefaultBrainStateConfig()),
+		pipelines:       make(map[string]*Pipeline),
+		profile:         ProfileStandard,
+	}
+	for _, profile := range []string{ProfileStandard, ProfileAgentic} {
+		pipeline, err := o.buildPipeline(profile)
+		if err != nil {
+			return nil, err
+		}
+		o.pipelines[profile] = pipeline
+	}
+	return o, nil
+}
+
+// UseProfile selects the pipeline messages run through by default
+func (o *Orchestrator) UseProfile(profile string) error {
+	if _, ok := o.pipelines[profile]; !ok {
+		return fmt.Errorf("unknown coach profile %q (want %s or %s)", profile, ProfileStandard, ProfileAgentic)
+	}
+	o.profile = profile
+	return nil
+}
+
+// Profile is the default pipeline profile
+func (o *Orchestrator) Profile() string {
+	return o.profile
+}
+
+// Pipeline returns a profile's pipeline so stages can be registered or
+// replaced, or nil for an unknown profile
+func (o *Orchestrator) Pipeline(profile string) *Pipeline {
+	return o.pipelines[profile]
+}
+
+// UseCurriculum recognises learning topics from the taxonomy
+// Without one, no topics are found: no CHISG lookups or mastery updates
+func (o *Orchestrator) UseCurriculum(taxonomy *curriculum.Taxonomy) {
+	o.curriculum = curriculum.NewExtractor(taxonomy)
+}
+
+// UseClassifier indexes the barrier exemplars and blends nearest-neighbour
+// matches into barrier and ETP detection
+func (o *Orchestrator) UseClassifier(ctx context.Context, classifier *nlp.Classifier) error {
+	return o.barrierDetector.UseClassifier(ctx, classifier)
+}
+
+// UseKnowledgeSource swaps the CHISG client the knowledge stage queries
+func (o *Orchestrator) UseKnowledgeSource(source KnowledgeSource) {
+	o.knowledge = source
+}
+
+// UseMasterySource reads each student's stored topic mastery
+// Without one, every topic starts at the estimator's initial level
+func (o *Orchestrator) UseMasterySource(source MasterySource) {
+	o.masterySource = source
+}
+
+// Mastery is the estimator turns are pitched with; record MasteryEvidence with it
+func (o *Orchestrator) Mastery() *mastery.Estimator {
+	return o.mastery
+}
+
+// Sessions exposes the session manager for start/end APIs
+func (o *Orchestrator) Sessions() *SessionManager {
+	return o.sessions
+}
+
+// UseRewardLedger mints an unlock code whenever a reward is earned
+// Without one, RewardEarned is reported but no code is issued
+func (o *Orchestrator) UseRewardLedger(minter RewardMinter) {
+	o.rewards = minter
+}
+
+// UseResponseGenerator swaps the backend that writes replies
+// Whatever it returns still goes through the safety screen and age filters
+func (o *Orchestrator) UseResponseGenerator(generator generation.ResponseGenerator) {
+	o.generator = generator
+}
+
+// UseSafeguardingOutbox queues escalations durably and records them in the audit trail
+func (o *Orchestrator) UseSafeguardingOutbox(outbox *safeguarding.Outbox) {
+	o.traumaDetector.UseOutbox(outbox)
 }
 
-// ProcessMessage is the main workflow
+// UseSignalTracker lets sub-threshold indicators build up across messages and days
+func (o *Orchestrator) UseSignalTracker(tracker *safeguarding.Tracker) {
+	o.traumaDetector.UseTracker(tracker)
+}
+
+// SafeguardingTracking returns the schema's longitudinal tracking settings
+func (o *Orchestrator) SafeguardingTracking() safeguarding.TrackingConfig {
+	return o.traumaDetector.Tracking()
+}
+
+// UseCaseManager opens a review case for every escalated alert, and lets
+// reviewer outcomes tune detection
+func (o *Orchestrator) UseCaseManager(cases *safeguarding.CaseManager) {
+	o.cases = cases
+	o.traumaDetector.UseTuner(cases)
+}
+
+// BarrierProfiles returns the loaded barrier profiles
+func (o *Orchestrator) BarrierProfiles() []etp.BarrierStudentProfile {
+	return o.barrierDetector.Profiles()
+}
+
+// ProcessMessage runs a message through the student's open session,
+// starting one if none is open
 func (o *Orchestrator) ProcessMessage(
 	studentID string,
 	message string,
-	context etp.StudentContext,
+	student etp.StudentContext,
 ) (*CoachResponse, error) {
+	return o.ProcessProfileMessage(context.Background(), o.profile, "", studentID, message, student)
+}
 
-	reasoning := []string{}
+// ProcessSessionMessage runs a message through an explicitly started session
+func (o *Orchestrator) ProcessSessionMessage(
+	sessionID string,
+	studentID string,
+	message string,
+	student etp.StudentContext,
+) (*CoachResponse, error) {
+	return o.ProcessProfileMessage(context.Background(), o.profile, sessionID, studentID, message, student)
+}
 
-	// STEP 1: Trauma/safeguarding check (HIGHEST PRIORITY) - NOW IN BACKEND
-	traumaResult := o.traumaDetector.Scan(message, context.Age)
-	if traumaResult.Severity >= 3 {
-		// Escalation already handled in trauma_detector
-		safeguardingMsg := o.ageFilter.SafeguardingResponse(context.Age)
-		reasoning = append(reasoning, "⚠️ Safeguarding concern - human team notified")
+// ProcessProfileMessage runs a message through a named profile's pipeline
+// An empty sessionID uses the student's open session, starting one if needed;
+// ctx bounds the turn, including any CHISG lookup
+func (o *Orchestrator) ProcessProfileMessage(
+	ctx context.Context,
+	profile string,
+	sessionID string,
+	studentID string,
+	message string,
+	student etp.StudentContext,
+) (*CoachResponse, error) {
+	pipeline := o.pipelines[profile]
+	if pipeline == nil {
+		return nil, fmt.Errorf("unknown coach profile %q", profile)
+	}
 
-		return &CoachResponse{
-			Message:           safeguardingMsg,
-			SafeguardingAlert: true,
-			DetectedBarriers:  []string{},
-			Reasoning:         reasoning,
-			Timestamp:         time.Now().Format(time.RFC3339),
-		}, nil
+	if sessionID == "" {
+		return o.processTurn(ctx, pipeline, o.sessions.ActiveSession(studentID), message, student)
 	}
 
-	// STEP 2: Detect barriers
-	detectedBarriers := o.barrierDetector.DetectBarriers(message, context)
+	session, err := o.sessions.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.StudentID != studentID {
+		return nil, ErrSessionForbidden
+	}
+	if !session.Active() {
+		return nil, ErrSessionEnded
+	}
+	return o.processTurn(ctx, pipeline, session, message, student)
+}
 
-	if len(detectedBarriers) > 0 {
-		topBarrier := detectedBarriers[0]
-		reasoning = append(reasoning,
-			fmt.Sprintf("🎯 Detected: %s (%.0f%%)",
-				topBarrier.Barrier.Name, topBarrier.Confidence*100))
-		reasoning = append(reasoning, topBarrier.Reasoning...)
+// processTurn runs the message through the pipeline, then records the
+// exchange; earlier turns in session inform detection and rewards
+func (o *Orchestrator) processTurn(
+	ctx context.Context,
+	pipeline *Pipeline,
+	session *Session,
+	message string,
+	student etp.StudentContext,
+) (*CoachResponse, error) {
+	turn := &TurnState{
+		Session: session,
+		Message: message,
+		Student: student,
+		Response: &CoachResponse{
+			DetectedBarriers:   []string{},
+			DetectedBarrierIDs: []string{},
+			Reasoning:          []string{},
+			Profile:            pipeline.Name(),
+		},
 	}
 
-	// STEP 3: Select intervention based on barrier + brain state
-	intervention := o.selectIntervention(detectedBarriers, context)
-	if intervention != nil {
-		reasoning = append(reasoning,
-			fmt.Sprintf("💡 Intervention: %s", intervention.Name))
+	if err := pipeline.Run(ctx, turn); err != nil {
+		return nil, err
 	}
+	o.gradeMastery(turn)
+	turn.Response.Timestamp = time.Now().Format(time.RFC3339)
 
-	// STEP 4: Generate response using intervention strategy
-	rawResponse := o.generateResponse(intervention, context, detectedBarriers)
+	recorded, err := o.recordTurn(session, message, turn.Student, turn.Barriers, turn.Response)
+	if err != nil {
+		return nil, err
+	}
 
-	// STEP 5: Make response age-appropriate
-	finalResponse := o.ageFilter.AdjustLanguage(rawResponse, context.Age)
+	// Escalations and level 2 flags (which carry on coaching) both reach a reviewer
+	o.openCase(session.ID, turn.Trauma)
+	return recorded, nil
+}
 
-	// STEP 6: Check for offense risk
-	offenseRisks := o.ageFilter.CheckOffenseRisk(finalResponse, context.Age)
-	if len(offenseRisks) > 0 {
-		reasoning = append(reasoning, "⚠️ Regenerating safer response...")
-		finalResponse = o.regenerateSafeResponse(context, intervention)
+// verifyReadability scores the final message against the student's age band
+// The metrics always go into reasoning so QA can track drift over time
+func (o *Orchestrator) verifyReadability(response *CoachResponse, age int) {
+	verdict := o.readability.Verify(response.Message, o.language.Group(age))
+	response.Readability = verdict
+	response.Reasoning = append(response.Reasoning, verdict.Summary())
+	for _, issue := range verdict.Issues {
+		response.Reasoning = append(response.Reasoning, "⚠️ Readability: "+issue)
 	}
+}
 
-	// STEP 7: Check if reward earned
-	rewardEarned := o.checkRewardEarned(message, detectedBarriers)
-	if rewardEarned {
-		reasoning = append(reasoning, "🎮 Play break earned!")
+// recordTurn appends the exchange to the session and stamps the response with its position
+func (o *Orchestrator) recordTurn(
+	session *Session,
+	message string,
+	context etp.StudentContext,
+	detectedBarriers []barriers.DetectedBarrier,
+	response *CoachResponse,
+) (*CoachResponse, error) {
+	turn := Turn{
+		StudentMessage:   message,
+		Response:         *response,
+		DetectedBarriers: toTurnBarriers(detectedBarriers),
+		BrainState:       context.BrainState,
+		Timestamp:        time.Now().UTC(),
 	}
 
-	return &CoachResponse{
-		Message:          finalResponse,
-		Intervention:     intervention,
-		DetectedBarriers: extractBarrierNames(detectedBarriers),
-		RewardEarned:     rewardEarned,
-		Reasoning:        reasoning,
-		Timestamp:        time.Now().Format(time.RFC3339),
-	}, nil
+	updated, err := o.sessions.RecordTurn(session.ID, turn)
+	if err != nil {
+		return nil, err
+	}
+
+	response.SessionID = updated.ID
+	response.TurnIndex = len(updated.Turns) - 1
+	return response, nil
+}
+
+// openCase gives reviewers the session so far, ending with the flagged turn
+func (o *Orchestrator) openCase(sessionID string, result safeguarding.TraumaResult) {
+	if o.cases == nil || result.Alert == nil {
+		return
+	}
+
+	session, err := o.sessions.GetSession(sessionID)
+	if err != nil {
+		log.Printf("🚨 Safeguarding case %s opened without context: %v", result.AlertID, err)
+		session = &Session{}
+	}
+
+	turns := session.recentTurns(caseContextTurns)
+	context := make([]safeguarding.CaseTurn, 0, len(turns))
+	for i, turn := range turns {
+		barrierIDs := make([]string, 0, len(turn.DetectedBarriers))
+		for _, barrier := range turn.DetectedBarriers {
+			barrierIDs = append(barrierIDs, barrier.ID)
+		}
+		context = append(context, safeguarding.CaseTurn{
+			StudentMessage: turn.StudentMessage,
+			CoachMessage:   turn.Response.Message,
+			Barriers:       barrierIDs,
+			Flagged:        i == len(turns)-1,
+			Timestamp:      turn.Timestamp,
+		})
+	}
+
+	if _, err := o.cases.Open(*result.Alert, sessionID, context); err != nil {
+		log.Printf("🚨 SAFEGUARDING CASE NOT OPENED - manual follow-up required: %v", err)
+	}
 }
 
+// selectIntervention picks a lever for the top barrier, or addresses
+// prerequisite gaps first when there is no barrier but CHISG found some
 func (o *Orchestrator) selectIntervention(
 	detectedBarriers []barriers.DetectedBarrier,
 	context etp.StudentContext,
+	knowledge *integration.KnowledgeContext,
 ) *etp.InterventionLever {
 
 	if len(detectedBarriers) == 0 {
+		if knowledge != nil && len(knowledge.PrerequisiteGaps) > 0 {
+			// Student needs foundational knowledge first
+			return &etp.InterventionLever{
+				Name:        "address_prerequisites",
+				Description: "Fill foundational knowledge gaps before tackling main topic",
+				Steps: []string{
+					"Acknowledge the challenge",
+					"Identify what's needed first: " + strings.Join(knowledge.PrerequisiteGaps, ", "),
+					"Work on prerequisites before main topic",
+				},
+				BrainStateTarget: "build_confidence_through_mastery",
+			}
+		}
 		return nil
 	}
 
@@ -140,7 +414,8 @@ func (o *Orchestrator) selectIntervention(
 	if context.BrainState.EmotionalLevel > 0.7 {
 		for i := range topBarrier.EffectiveLevers {
 			lever := &topBarrier.EffectiveLevers[i]
-			if strings.Contains(strings.ToLower(lever.BrainStateTarget), "lower") {
+			target := strings.ToLower(lever.BrainStateTarget)
+			if strings.Contains(target, "lower") || strings.Contains(target, "calm") {
 				return lever
 			}
 		}
@@ -154,117 +429,101 @@ func (o *Orchestrator) selectIntervention(
 	return nil
 }
 
-// generateResponse creates response from intervention strategy
-func (o *Orchestrator) generateResponse(
-	intervention *etp.InterventionLever,
-	context etp.StudentContext,
+// nextCue picks the sequence step for this turn
+// The top barrier showing again is avoidance of the last step; a genuine
+// barrier-free reply is an attempt; a barrier seen earlier in the session
+// resumes where it left off
+func (o *Orchestrator) nextCue(
+	session *Session,
+	message string,
 	detectedBarriers []barriers.DetectedBarrier,
-) string {
+) *generation.Cue {
+	last := session.lastCue(historyWindow)
 
-	// PRIORITY 1: No barriers detected = positive engagement
 	if len(detectedBarriers) == 0 {
-		return o.generatePositiveEngagementResponse(context)
-	}
-
-	// PRIORITY 2: Barrier-specific response (most contextual)
-	if len(detectedBarriers) > 0 {
-		barrierID := detectedBarriers[0].Barrier.ID
-		if response, ok := o.getBarrierSpecificResponse(barrierID); ok {
-			return response
+		if last == nil || len(strings.TrimSpace(message)) < attemptMinLength {
+			return nil
 		}
+		return o.sequences.Next(*last, generation.ReplyAttempt)
 	}
 
-	// PRIORITY 3: Intervention-based response
-	if intervention != nil {
-		if intervention.Description != "" {
-			return o.translateStepToResponse(intervention.Description)
-		}
-		if len(intervention.Steps) > 0 {
-			return o.translateStepToResponse(intervention.Steps[0])
-		}
+	barrierID := detectedBarriers[0].Barrier.ID
+	if last != nil && last.BarrierID == barrierID {
+		return o.sequences.Next(*last, generation.ReplyAvoidance)
 	}
-
-	// PRIORITY 4: Fallback generic
-	return "I'm here to help. What would you like to work on?"
+	if earlier := session.lastCueFor(barrierID); earlier != nil {
+		return o.sequences.Resume(*earlier)
+	}
+	return o.sequences.Start(barrierID)
 }
 
-// generatePositiveEngagementResponse handles cases where student is genuinely engaged
-func (o *Orchestrator) generatePositiveEngagementResponse(context etp.StudentContext) string {
-	responses := []string{
-		"That's exactly the kind of thinking I want to see! You're really understanding this.",
-		"Excellent question! It shows you're thinking deeply about the material.",
-		"You're making real progress. Let's keep building on this momentum.",
-		"I can tell you're genuinely engaged. That's how real learning happens!",
-		"Perfect! You're asking the right questions. Let's explore this further.",
+// generateResponse asks the generator for a reply and screens it before any
+// filtering; a reply that trips a safeguarding pattern is never sent
+func (o *Orchestrator) generateResponse(ctx context.Context, turn *TurnState) (string, []string) {
+	session, student := turn.Session, turn.Student
+	o.personalization.TrackInterests(session.StudentID, o.personalization.DetectInterests(turn.Message))
+	interests := o.interestNames(session.StudentID)
+
+	req := generation.Request{
+		StudentID:      session.StudentID,
+		SessionID:      session.ID,
+		StudentMessage: turn.Message,
+		Age:            student.Age,
+		Guidelines:     o.language.Group(student.Age),
+		Intervention:   turn.Intervention,
+		Framing:        turn.Framing,
+		BrainState:     student.BrainState,
+		Interests:      interests,
 	}
-
-	// Vary response based on age
-	if context.Age < 10 {
-		responses = []string{
-			"That's great thinking! You're doing really well.",
-			"I love that you want to learn more! Keep going!",
-			"You're asking smart questions. That's how you get better!",
+	if cue := turn.Cue; cue != nil && !cue.Complete() {
+		values := generation.Placeholders{Name: student.Name}
+		if len(interests) > 0 {
+			values.Interest = interests[0]
 		}
+		o.sequences.Render(cue, student.Age, values)
+		req.Script = cue
 	}
-
-	// Return a random response from the list
-	return responses[len(responses)%len(responses)]
-}
-
-// getBarrierSpecificResponse returns contextual response based on barrier type
-// Maps barrier IDs directly to responses (no context needed, just the ID)
-func (o *Orchestrator) getBarrierSpecificResponse(barrierID string) (string, bool) {
-	responses := map[string]string{
-		"lack_of_motivation":         "How about we try something really simple first? Just to get warmed up. You might surprise yourself!",
-		"confrontational_showoff":    "I can see you're feeling frustrated with this. That's totally okay. Let's find something small you're doing well with.",
-		"silent_avoider":             "No pressure at all. I'm right here with you. Can you just try one tiny thing - even just writing one word?",
-		"quiet_playful_avoider":      "I like your energy! Let's turn that into something fun AND educational. Ready for a challenge?",
-		"high_achiever_underengaged": "You're clearly capable of more. Let me show you something that will actually challenge you. This gets interesting.",
+	if len(turn.Barriers) > 0 {
+		req.Barrier = &turn.Barriers[0].Barrier
+		req.Confidence = turn.Barriers[0].Confidence
 	}
-
-	if response, exists := responses[barrierID]; exists {
-		return response, true
+	req.Topic = turn.Topic
+	if turn.Knowledge != nil {
+		req.PrerequisiteGaps = turn.Knowledge.PrerequisiteGaps
 	}
-
-	return "", false
-}
-
-// translateStepToResponse converts intervention step/description to actual response
-func (o *Orchestrator) translateStepToResponse(step string) string {
-	if step == "" {
-		return "I'm here to help. What would you like to work on?"
+	if turn.Question != nil {
+		req.QuestionDifficulty = turn.Question.Difficulty
 	}
 
-	step_lower := strings.ToLower(step)
-
-	// Pattern-based matching for common intervention phrases
-	if strings.Contains(step_lower, "find") || strings.Contains(step_lower, "recognize") {
-		return "I can see you're working on this. Let's find something small you're doing well!"
-	}
-	if strings.Contains(step_lower, "lower") || strings.Contains(step_lower, "easy") {
-		return "How about we try something super easy first? Just to get warmed up."
-	}
-	if strings.Contains(step_lower, "show interest") || strings.Contains(step_lower, "chat") {
-		return "Before we dive in, how are you feeling today? Anything on your mind?"
-	}
-	if strings.Contains(step_lower, "guide") || strings.Contains(step_lower, "help") {
-		return "Let me help you out here. Can you tell me just one thing you think about this topic?"
-	}
-	if strings.Contains(step_lower, "celebrate") || strings.Contains(step_lower, "praise") {
-		return "That's a great start! You're doing really well just by being here and trying."
-	}
-	if strings.Contains(step_lower, "game") || strings.Contains(step_lower, "reward") {
-		return "Complete this and you'll get a reward. Let's see what you can do!"
+	notes := []string{}
+	reply, err := o.generator.Generate(ctx, req)
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("⚠️ Response generator failed: %v", err))
+		return o.language.SafeFallback(student.Age), notes
 	}
-	if strings.Contains(step_lower, "micro") || strings.Contains(step_lower, "tiny") {
-		return "Let's start with something really easy - just to warm up. You've got this!"
+	notes = append(notes, fmt.Sprintf("✍️ Reply from %s (%s framing)", reply.Backend, req.Framing))
+	if reply.Fallback != "" {
+		notes = append(notes, fmt.Sprintf("⚠️ Fell back to %s: %s", reply.Backend, reply.Fallback))
 	}
-	if strings.Contains(step_lower, "shoulder") || strings.Contains(step_lower, "right here") {
-		return "I'm right here with you. Let's start with something tiny - what's one thing you could try?"
+
+	if severity, patternID := o.traumaDetector.Screen(reply.Text); severity >= 2 {
+		notes = append(notes, fmt.Sprintf("⚠️ Generated reply matched safeguarding pattern %s - using safe fallback", patternID))
+		return o.language.SafeFallback(student.Age), notes
 	}
+	return reply.Text, notes
+}
 
-	// Fallback: use step text directly with supportive framing
-	return "Let's work through this together. " + step
+// interestNames lists what the student has mentioned enjoying, most mentioned first
+func (o *Orchestrator) interestNames(studentID string) []string {
+	interests := o.personalization.GetStudentInterests(studentID)
+	sort.SliceStable(interests, func(i, j int) bool {
+		return interests[i].MentionCount > interests[j].MentionCount
+	})
+	names := make([]string, 0, len(interests))
+	for _, interest := range interests {
+		names = append(names, interest.Specific)
+	}
+	return names
 }
 
 func (o *Orchestrator) regenerateSafeResponse(
@@ -272,34 +531,91 @@ func (o *Orchestrator) regenerateSafeResponse(
 	intervention *etp.InterventionLever,
 ) string {
 
-	// Age-appropriate safe fallbacks
-	if context.Age < 8 {
-		return "Let's try something fun! What would you like to do?"
-	} else if context.Age < 13 {
-		return "I'm here to help you learn. What part are you finding tricky?"
-	} else {
-		return "Let's work through this together. Where would you like to start?"
-	}
+	// Age-appropriate safe fallback from the schema
+	return o.language.SafeFallback(context.Age)
 }
 
+// rewardDecision is the outcome of the reward check
+type rewardDecision struct {
+	Earned  bool
+	Through string // What earned it, recorded on the unlock
+	Reason  string // Reasoning line to surface, if any
+}
+
+// checkRewardEarned decides whether this turn earns a play break
 func (o *Orchestrator) checkRewardEarned(
 	message string,
 	barriers []barriers.DetectedBarrier,
-) bool {
+	session *Session,
+) rewardDecision {
+
+	// Check if not pure avoidance
+	for _, b := range barriers {
+		if strings.Contains(b.Barrier.ID, "lack_of_motivation") ||
+			strings.Contains(b.Barrier.ID, "confrontational") {
+			return rewardDecision{}
+		}
+	}
+
+	trimmed := strings.TrimSpace(message)
 
 	// Longer, engaged responses = reward
-	if len(message) > 50 {
-		// Check if not pure avoidance
-		for _, b := range barriers {
-			if strings.Contains(b.Barrier.ID, "lack_of_motivation") ||
-				strings.Contains(b.Barrier.ID, "confrontational") {
-				return false
-			}
+	earned := len(trimmed) > 50
+
+	// A genuine attempt straight after a run of avoidance is a breakthrough
+	breakthrough := len(barriers) == 0 &&
+		len(trimmed) >= attemptMinLength &&
+		session.AvoidanceStreak() >= breakthroughStreak
+
+	if !earned && !breakthrough {
+		return rewardDecision{}
+	}
+
+	// Pasting the same answer again is not new effort
+	if session.RepeatsLastMessage(message) {
+		return rewardDecision{Reason: "🔁 Repeated message - no new reward"}
+	}
+
+	// Space rewards out so they stay meaningful
+	if session.RewardedWithin(rewardCooldown) {
+		return rewardDecision{Reason: "⏳ Reward recently earned - keep going!"}
+	}
+
+	if breakthrough && !earned {
+		return rewardDecision{
+			Earned:  true,
+			Through: "first_attempt_after_avoidance",
+			Reason: fmt.Sprintf("🎮 Play break earned - first real attempt after %d avoidant turns!",
+				session.AvoidanceStreak()),
 		}
-		return true
 	}
+	return rewardDecision{
+		Earned:  true,
+		Through: "engaged_response",
+		Reason:  "🎮 Play break earned!",
+	}
+}
 
-	return false
+// mintReward turns an earned reward into an unlock code
+// A reward the ledger refuses (daily cap) is withdrawn so counts stay honest
+func (o *Orchestrator) mintReward(studentID string, reward *rewardDecision) *rewards.Unlock {
+	if !reward.Earned || o.rewards == nil {
+		return nil
+	}
+
+	unlock, err := o.rewards.Mint(studentID, reward.Through)
+	switch {
+	case errors.Is(err, rewards.ErrDailyCap):
+		reward.Earned = false
+		reward.Reason = "🌟 Great effort! (Today's play breaks are all used up)"
+		return nil
+	case err != nil:
+		log.Printf("Failed to mint reward for %s: %v", studentID, err)
+		reward.Earned = false
+		reward.Reason = ""
+		return nil
+	}
+	return unlock
 }
 
 func extractBarrierNames(barriers []barriers.DetectedBarrier) []string {
@@ -309,3 +625,11 @@ func extractBarrierNames(barriers []barriers.DetectedBarrier) []string {
 	}
 	return names
 }
+
+func extractBarrierIDs(barriers []barriers.DetectedBarrier) []string {
+	ids := make([]string, len(barriers))
+	for i, b := range barriers {
+		ids[i] = b.Barrier.ID
+	}
+	return ids
+}
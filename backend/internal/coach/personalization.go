@@ -95,11 +95,11 @@ func (pe *PersonalizationEngine) TrackInterests(studentID string, interests []In
 	pe.studentInterests[studentID] = existing
 }
 
-// GetStudentInterests retrieves tracked interests
+// GetStudentInterests retrieves a copy of the tracked interests
 func (pe *PersonalizationEngine) GetStudentInterests(studentID string) []Interest {
 	pe.mu.RLock()
 	defer pe.mu.RUnlock()
-	return pe.studentInterests[studentID]
+	return append([]Interest{}, pe.studentInterests[studentID]...)
 }
 
 // min helper
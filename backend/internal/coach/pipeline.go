@@ -0,0 +1,234 @@
+package coach
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/barriers"
+	"github.com/mike5tew/humanos/internal/curriculum"
+	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/generation"
+	"github.com/mike5tew/humanos/internal/integration"
+	"github.com/mike5tew/humanos/internal/safeguarding"
+)
+
+// Pipeline profiles selectable with COACH_PROFILE
+const (
+	ProfileStandard = "standard" // brain_state → safeguard → detect → topic → select → generate → filter → reward
+	ProfileAgentic  = "agentic"  // standard plus CHISG knowledge analysis after topic
+)
+
+// Built-in stage names
+const (
+	StageBrainState = "brain_state"
+	StageSafeguard  = "safeguard"
+	StageDetect     = "detect"
+	StageTopic      = "topic"
+	StageKnowledge  = "knowledge"
+	StageSelect     = "select"
+	StageGenerate   = "generate"
+	StageFilter     = "filter"
+	StageReward     = "reward"
+)
+
+// TurnState is what a pipeline's stages read and fill in for one message
+type TurnState struct {
+	Session *Session
+	Message string
+	Student etp.StudentContext // BrainState is the server's estimate once brain_state has run
+
+	Trauma       safeguarding.TraumaResult
+	Barriers     []barriers.DetectedBarrier
+	ETPs         []barriers.DetectedETP
+	Topics       []curriculum.Match // Ranked; Topic is the first
+	Topic        string
+	Level        float64 // Student's mastery of Topic, 0-1
+	Knowledge    *integration.KnowledgeContext
+	Intervention *etp.InterventionLever
+	Cue          *generation.Cue
+	Question     *QuestionSpec // Difficulty the next question is pitched at
+	Framing      string
+	Draft        string // Generated reply, before filtering
+
+	Response *CoachResponse // Built up stage by stage
+	Done     bool           // Set by a stage that has fully answered the turn
+}
+
+// Stage is one step of the coaching pipeline
+type Stage interface {
+	Name() string
+	Run(ctx context.Context, turn *TurnState) error
+}
+
+// StageFunc adapts a function to a Stage
+func StageFunc(name string, run func(ctx context.Context, turn *TurnState) error) Stage {
+	return stageFunc{name: name, run: run}
+}
+
+type stageFunc struct {
+	name string
+	run  func(ctx context.Context, turn *TurnState) error
+}
+
+func (s stageFunc) Name() string                                   { return s.name }
+func (s stageFunc) Run(ctx context.Context, turn *TurnState) error { return s.run(ctx, turn) }
+
+// StageTiming is how long one stage took on one turn
+type StageTiming struct {
+	Stage  string  `json:"stage"`
+	Millis float64 `json:"ms"`
+}
+
+// StageStats aggregates one stage's timings across turns
+type StageStats struct {
+	Runs    int64   `json:"runs"`
+	Errors  int64   `json:"errors"`
+	TotalMs float64 `json:"total_ms"`
+	MeanMs  float64 `json:"mean_ms"`
+	MaxMs   float64 `json:"max_ms"`
+}
+
+// Pipeline runs its stages in order, timing each one
+type Pipeline struct {
+	name   string
+	mu     sync.RWMutex
+	stages []Stage
+	stats  map[string]*StageStats
+}
+
+// NewPipeline creates a pipeline from stages in run order
+func NewPipeline(name string, stages ...Stage) *Pipeline {
+	p := &Pipeline{name: name, stats: make(map[string]*StageStats)}
+	for _, stage := range stages {
+		p.Register(stage)
+	}
+	return p
+}
+
+// Name is the profile the pipeline was built for
+func (p *Pipeline) Name() string {
+	return p.name
+}
+
+// Register appends a stage, or replaces the stage with the same name in place
+func (p *Pipeline) Register(stage Stage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if i := p.indexLocked(stage.Name()); i >= 0 {
+		p.stages[i] = stage
+		return
+	}
+	p.stages = append(p.stages, stage)
+}
+
+// RegisterAfter inserts a stage straight after the named one
+func (p *Pipeline) RegisterAfter(after string, stage Stage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.indexLocked(stage.Name()) >= 0 {
+		return fmt.Errorf("stage %q is already registered", stage.Name())
+	}
+	i := p.indexLocked(after)
+	if i < 0 {
+		return fmt.Errorf("no stage %q to register after", after)
+	}
+	p.stages = append(p.stages[:i+1], append([]Stage{stage}, p.stages[i+1:]...)...)
+	return nil
+}
+
+// Remove drops a stage, reporting whether it was registered
+func (p *Pipeline) Remove(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := p.indexLocked(name)
+	if i < 0 {
+		return false
+	}
+	p.stages = append(p.stages[:i], p.stages[i+1:]...)
+	return true
+}
+
+// Stages lists the stage names in run order
+func (p *Pipeline) Stages() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name()
+	}
+	return names
+}
+
+// Run passes the turn through each stage until one finishes it or fails
+// Every stage that runs is timed on the response and in the pipeline stats
+func (p *Pipeline) Run(ctx context.Context, turn *TurnState) error {
+	p.mu.RLock()
+	stages := append([]Stage{}, p.stages...)
+	p.mu.RUnlock()
+
+	for _, stage := range stages {
+		if turn.Done {
+			break
+		}
+
+		start := time.Now()
+		err := stage.Run(ctx, turn)
+		elapsed := float64(time.Since(start).Microseconds()) / 1000
+
+		turn.Response.Timings = append(turn.Response.Timings, StageTiming{Stage: stage.Name(), Millis: elapsed})
+		p.record(stage.Name(), elapsed, err)
+
+		if err != nil {
+			return fmt.Errorf("%s stage: %w", stage.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Stats returns each stage's aggregate timings
+func (p *Pipeline) Stats() map[string]StageStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	out := make(map[string]StageStats, len(p.stats))
+	for name, stats := range p.stats {
+		snapshot := *stats
+		if snapshot.Runs > 0 {
+			snapshot.MeanMs = snapshot.TotalMs / float64(snapshot.Runs)
+		}
+		out[name] = snapshot
+	}
+	return out
+}
+
+func (p *Pipeline) record(name string, elapsed float64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats[name]
+	if stats == nil {
+		stats = &StageStats{}
+		p.stats[name] = stats
+	}
+	stats.Runs++
+	stats.TotalMs += elapsed
+	stats.MaxMs = max(stats.MaxMs, elapsed)
+	if err != nil {
+		stats.Errors++
+	}
+}
+
+func (p *Pipeline) indexLocked(name string) int {
+	for i, stage := range p.stages {
+		if stage.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
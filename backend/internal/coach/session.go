@@ -0,0 +1,426 @@
+package coach
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/barriers"
+	"github.com/mike5tew/humanos/internal/curriculum"
+	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/generation"
+)
+
+// Session errors
+var (
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrSessionEnded     = errors.New("session already ended")
+	ErrSessionForbidden = errors.New("session belongs to another student")
+)
+
+// Session retention: sessions live in memory, so idle ones are closed and
+// closed ones dropped once reviewers no longer need them
+const (
+	sessionIdleAfter  = 2 * time.Hour  // Open sessions with no turn for this long are ended
+	sessionRetention  = 24 * time.Hour // Ended sessions are kept this long, then evicted
+	sessionPruneEvery = 10 * time.Minute
+)
+
+// History tuning
+const (
+	historyWindow      = 5    // Turns considered for recent-frequency checks
+	historyEvidence    = 0.15 // Extra evidence per recent prior detection
+	rewardCooldown     = 2    // Previous turns that must be reward-free
+	attemptMinLength   = 20   // Shortest message counted as a genuine attempt
+	breakthroughStreak = 2    // Avoidance turns before a genuine attempt is rewarded
+	caseContextTurns   = 20   // Turns handed to safeguarding reviewers with a case
+)
+
+// TurnBarrier is the per-turn record of a detected barrier
+type TurnBarrier struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Turn is one student message and the coach's reply
+type Turn struct {
+	Index            int            `json:"index"`
+	StudentMessage   string         `json:"student_message"`
+	Response         CoachResponse  `json:"response"`
+	DetectedBarriers []TurnBarrier  `json:"detected_barriers"`
+	BrainState       etp.BrainState `json:"brain_state"` // Server estimate; later turns build on it
+	Timestamp        time.Time      `json:"timestamp"`
+}
+
+// Session is an ordered conversation between one student and the coach
+type Session struct {
+	ID          string              `json:"id"`
+	StudentID   string              `json:"student_id"`
+	StartedAt   time.Time           `json:"started_at"`
+	EndedAt     *time.Time          `json:"ended_at,omitempty"`
+	Turns       []Turn              `json:"turns"`
+	Progression QuestionProgression `json:"progression"`
+}
+
+// Active reports whether the session is still open
+func (s *Session) Active() bool {
+	return s.EndedAt == nil
+}
+
+// lastTopics is what the previous turn was about, for follow-ups
+func (s *Session) lastTopics() []curriculum.Match {
+	if s == nil || len(s.Turns) == 0 {
+		return nil
+	}
+	return s.Turns[len(s.Turns)-1].Response.Topics
+}
+
+// BarrierStreak counts consecutive most-recent turns that detected a barrier
+func (s *Session) BarrierStreak(barrierID string) int {
+	streak := 0
+	for i := len(s.Turns) - 1; i >= 0; i-- {
+		if !s.Turns[i].hasBarrier(barrierID) {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+// RecentBarrierCount counts detections of a barrier in the last n turns
+func (s *Session) RecentBarrierCount(barrierID string, n int) int {
+	count := 0
+	for _, turn := range s.recentTurns(n) {
+		if turn.hasBarrier(barrierID) {
+			count++
+		}
+	}
+	return count
+}
+
+// AvoidanceStreak counts consecutive most-recent turns with any barrier
+func (s *Session) AvoidanceStreak() int {
+	streak := 0
+	for i := len(s.Turns) - 1; i >= 0; i-- {
+		if len(s.Turns[i].DetectedBarriers) == 0 {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+// RewardedWithin reports whether any of the last n turns earned a reward
+func (s *Session) RewardedWithin(n int) bool {
+	for _, turn := range s.recentTurns(n) {
+		if turn.Response.RewardEarned {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkMinutes is the focused time up to the latest turn since the session
+// started or the student's previous play break
+func (s *Session) WorkMinutes() float64 {
+	if len(s.Turns) == 0 {
+		return 0
+	}
+
+	start := s.StartedAt
+	for i := len(s.Turns) - 2; i >= 0; i-- {
+		if s.Turns[i].Response.RewardEarned {
+			start = s.Turns[i].Timestamp
+			break
+		}
+	}
+	return s.Turns[len(s.Turns)-1].Timestamp.Sub(start).Minutes()
+}
+
+// RepeatsLastMessage reports whether the student sent the same text again
+func (s *Session) RepeatsLastMessage(message string) bool {
+	if len(s.Turns) == 0 {
+		return false
+	}
+	return s.Turns[len(s.Turns)-1].StudentMessage == message
+}
+
+// lastCue returns the most recent sequence step given in the last n turns
+func (s *Session) lastCue(n int) *generation.Cue {
+	turns := s.recentTurns(n)
+	for i := len(turns) - 1; i >= 0; i-- {
+		if cue := turns[i].Response.Sequence; cue != nil {
+			return cue
+		}
+	}
+	return nil
+}
+
+// lastCueFor returns the most recent step of one barrier's sequence
+func (s *Session) lastCueFor(barrierID string) *generation.Cue {
+	for i := len(s.Turns) - 1; i >= 0; i-- {
+		if cue := s.Turns[i].Response.Sequence; cue != nil && cue.BarrierID == barrierID {
+			return cue
+		}
+	}
+	return nil
+}
+
+// lastActivity is when the latest turn was taken, or the start if none
+func (s *Session) lastActivity() time.Time {
+	if len(s.Turns) == 0 {
+		return s.StartedAt
+	}
+	return s.Turns[len(s.Turns)-1].Timestamp
+}
+
+func (s *Session) recentTurns(n int) []Turn {
+	if len(s.Turns) <= n {
+		return s.Turns
+	}
+	return s.Turns[len(s.Turns)-n:]
+}
+
+func (t *Turn) hasBarrier(barrierID string) bool {
+	for _, b := range t.DetectedBarriers {
+		if b.ID == barrierID {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionManager tracks coaching sessions (in-memory for MVP)
+type SessionManager struct {
+	sessions map[string]*Session
+	active   map[string]string // studentID → open session ID
+	mu       sync.RWMutex
+	now      func() time.Time
+}
+
+// NewSessionManager creates an empty session manager; call Run to evict old sessions
+func NewSessionManager() *SessionManager {
+	return &SessionManager{
+		sessions: make(map[string]*Session),
+		active:   make(map[string]string),
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run prunes sessions periodically until ctx is cancelled
+func (sm *SessionManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(sessionPruneEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sm.Prune()
+		}
+	}
+}
+
+// Prune ends sessions idle for sessionIdleAfter and evicts sessions ended
+// more than sessionRetention ago, returning how many of each
+func (sm *SessionManager) Prune() (ended, evicted int) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	now := sm.now()
+	for id, session := range sm.sessions {
+		if session.Active() && now.Sub(session.lastActivity()) >= sessionIdleAfter {
+			sm.endLocked(session)
+			ended++
+		}
+		if !session.Active() && now.Sub(*session.EndedAt) >= sessionRetention {
+			delete(sm.sessions, id)
+			evicted++
+		}
+	}
+	return ended, evicted
+}
+
+// StartSession opens a new session, ending any open one for the student
+func (sm *SessionManager) StartSession(studentID string) *Session {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.startLocked(studentID)
+}
+
+// EndSession closes a session and returns its final state
+func (sm *SessionManager) EndSession(sessionID string) (*Session, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session, ok := sm.sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if !session.Active() {
+		return nil, ErrSessionEnded
+	}
+
+	sm.endLocked(session)
+	return cloneSession(session), nil
+}
+
+// GetSession returns a snapshot of a session
+func (sm *SessionManager) GetSession(sessionID string) (*Session, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	session, ok := sm.sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return cloneSession(session), nil
+}
+
+// ActiveSession returns a snapshot of the student's open session, starting one if needed
+func (sm *SessionManager) ActiveSession(studentID string) *Session {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if id, ok := sm.active[studentID]; ok {
+		return cloneSession(sm.sessions[id])
+	}
+	return cloneSession(sm.startLocked(studentID))
+}
+
+// RecordTurn appends a turn and updates the session's question progression
+func (sm *SessionManager) RecordTurn(sessionID string, turn Turn) (*Session, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session, ok := sm.sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if !session.Active() {
+		return nil, ErrSessionEnded
+	}
+
+	turn.Index = len(session.Turns)
+	session.Turns = append(session.Turns, turn)
+	updateProgression(&session.Progression, turn)
+
+	return cloneSession(session), nil
+}
+
+func (sm *SessionManager) startLocked(studentID string) *Session {
+	if id, ok := sm.active[studentID]; ok {
+		sm.endLocked(sm.sessions[id])
+	}
+
+	session := &Session{
+		ID:        newSessionID(),
+		StudentID: studentID,
+		StartedAt: sm.now(),
+		Turns:     []Turn{},
+		Progression: QuestionProgression{
+			StartDifficulty:  0.3,
+			SemanticDistance: 1,
+		},
+	}
+	sm.sessions[session.ID] = session
+	sm.active[studentID] = session.ID
+	return session
+}
+
+func (sm *SessionManager) endLocked(session *Session) {
+	now := sm.now()
+	session.EndedAt = &now
+	if sm.active[session.StudentID] == session.ID {
+		delete(sm.active, session.StudentID)
+	}
+}
+
+// updateProgression feeds turn outcomes into the difficulty streak
+func updateProgression(qp *QuestionProgression, turn Turn) {
+	qp.LastAnswerQuality = answerQuality(turn.Response.SafeguardingAlert, len(turn.DetectedBarriers))
+	if qp.LastAnswerQuality == AnswerEngaged {
+		qp.CurrentStreak++
+	} else {
+		qp.CurrentStreak = 0
+	}
+}
+
+// applyHistory raises confidence for barriers that keep recurring
+// Each recent prior detection is folded in as extra noisy-OR evidence
+func applyHistory(session *Session, detected []barriers.DetectedBarrier) []barriers.DetectedBarrier {
+	if session == nil || len(session.Turns) == 0 {
+		return detected
+	}
+
+	for i := range detected {
+		id := detected[i].Barrier.ID
+		streak := session.BarrierStreak(id)
+		recent := session.RecentBarrierCount(id, historyWindow)
+		if recent == 0 {
+			continue
+		}
+
+		miss := 1.0 - detected[i].Confidence
+		for j := 0; j < recent; j++ {
+			miss *= 1.0 - historyEvidence
+		}
+		detected[i].Confidence = 1.0 - miss
+
+		if streak > 0 {
+			detected[i].Reasoning = append(detected[i].Reasoning,
+				fmt.Sprintf("Same pattern %d turns in a row", streak+1))
+		} else {
+			detected[i].Reasoning = append(detected[i].Reasoning,
+				fmt.Sprintf("Seen %d of the last %d turns", recent, historyWindow))
+		}
+	}
+
+	sortByConfidence(detected)
+	return detected
+}
+
+func sortByConfidence(detected []barriers.DetectedBarrier) {
+	for i := 1; i < len(detected); i++ {
+		for j := i; j > 0 && detected[j].Confidence > detected[j-1].Confidence; j-- {
+			detected[j], detected[j-1] = detected[j-1], detected[j]
+		}
+	}
+}
+
+func toTurnBarriers(detected []barriers.DetectedBarrier) []TurnBarrier {
+	out := make([]TurnBarrier, len(detected))
+	for i, b := range detected {
+		out[i] = TurnBarrier{
+			ID:         b.Barrier.ID,
+			Name:       b.Barrier.Name,
+			Confidence: b.Confidence,
+		}
+	}
+	return out
+}
+
+func cloneSession(session *Session) *Session {
+	clone := *session
+	clone.Turns = append([]Turn{}, session.Turns...)
+	if session.EndedAt != nil {
+		ended := *session.EndedAt
+		clone.EndedAt = &ended
+	}
+	return &clone
+}
+
+func newSessionID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,40 @@
+package coach
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSessionManagerPrunesIdleAndEndedSessions(t *testing.T) {
+	sm := NewSessionManager()
+	clock := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	sm.now = func() time.Time { return clock }
+
+	idle := sm.StartSession("student-1")
+	busy := sm.StartSession("student-2")
+
+	clock = clock.Add(sessionIdleAfter - time.Minute)
+	if _, err := sm.RecordTurn(busy.ID, Turn{Timestamp: clock}); err != nil {
+		t.Fatal(err)
+	}
+
+	clock = clock.Add(time.Minute)
+	if ended, evicted := sm.Prune(); ended != 1 || evicted != 0 {
+		t.Fatalf("prune = %d ended, %d evicted; want the idle session ended", ended, evicted)
+	}
+	if session, err := sm.GetSession(idle.ID); err != nil || session.Active() {
+		t.Fatalf("idle session = %+v, %v; want ended but kept for review", session, err)
+	}
+	if sm.ActiveSession("student-2") == nil {
+		t.Fatal("session with a recent turn was ended")
+	}
+
+	clock = clock.Add(sessionRetention)
+	if _, evicted := sm.Prune(); evicted != 1 {
+		t.Fatalf("evicted = %d, want the idle session dropped after retention", evicted)
+	}
+	if _, err := sm.GetSession(idle.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("evicted session: err = %v, want ErrSessionNotFound", err)
+	}
+}
@@ -0,0 +1,279 @@
+package coach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mike5tew/humanos/internal/generation"
+	"github.com/mike5tew/humanos/internal/integration"
+)
+
+// Knowledge stage outcomes, reported as CoachResponse.KnowledgeStatus
+const (
+	KnowledgeOK          = "ok"          // CHISG answered; knowledge_context is set
+	KnowledgeNoTopic     = "no_topic"    // No learning topic recognised in the message
+	KnowledgeUnavailable = "unavailable" // CHISG failed; coaching continued emotion-only
+)
+
+// buildPipeline assembles the stages for a profile
+func (o *Orchestrator) buildPipeline(profile string) (*Pipeline, error) {
+	stages := []Stage{
+		StageFunc(StageBrainState, o.brainStateStage),
+		StageFunc(StageSafeguard, o.safeguardStage),
+		StageFunc(StageDetect, o.detectStage),
+		StageFunc(StageTopic, o.topicStage),
+	}
+	switch profile {
+	case ProfileStandard:
+	case ProfileAgentic:
+		stages = append(stages, StageFunc(StageKnowledge, o.knowledgeStage))
+	default:
+		return nil, fmt.Errorf("unknown coach profile %q", profile)
+	}
+	stages = append(stages,
+		StageFunc(StageSelect, o.selectStage),
+		StageFunc(StageGenerate, o.generateStage),
+		StageFunc(StageFilter, o.filterStage),
+		StageFunc(StageReward, o.rewardStage),
+	)
+	return NewPipeline(profile, stages...), nil
+}
+
+// brainStateStage replaces the client's brain state with the server's estimate
+// It runs first so even a safeguarded turn leaves an estimate in the history
+func (o *Orchestrator) brainStateStage(ctx context.Context, turn *TurnState) error {
+	estimate := o.brainState.Estimate(turn.Session, turn.Message, turn.Student.BrainState)
+	turn.Student.BrainState = estimate.State
+	turn.Response.BrainState = &estimate
+
+	state := estimate.State
+	reading := fmt.Sprintf("🌡️ Brain state: primal %.2f, emotional %.2f, rational %.2f, override risk %.2f",
+		state.PrimalLevel, state.EmotionalLevel, state.RationalLevel, state.OverrideRisk)
+	if len(estimate.Signals) > 0 {
+		reading += " (" + strings.Join(estimate.Signals, "; ") + ")"
+	}
+	turn.Response.Reasoning = append(turn.Response.Reasoning, reading)
+	if o.brainState.Diverges(estimate) {
+		turn.Response.Reasoning = append(turn.Response.Reasoning,
+			fmt.Sprintf("🌡️ Client brain state is up to %.2f off the estimate; using the estimate", estimate.MaxDivergence))
+	}
+	return nil
+}
+
+// safeguardStage is the trauma/safeguarding check (HIGHEST PRIORITY)
+// A severe concern answers the turn itself and skips every later stage
+func (o *Orchestrator) safeguardStage(ctx context.Context, turn *TurnState) error {
+	age := turn.Student.Age
+	turn.Trauma = o.traumaDetector.Scan(turn.Session.StudentID, turn.Message, age)
+	if turn.Trauma.Severity < 3 {
+		return nil
+	}
+
+	// Escalation already handled in trauma_detector
+	response := turn.Response
+	response.Message = o.language.SafeguardingResponse(age)
+	response.SafeguardingAlert = true
+	response.Reasoning = append(response.Reasoning, "⚠️ Safeguarding concern - human team notified")
+	o.verifyReadability(response, age)
+	turn.Done = true
+	return nil
+}
+
+// detectStage finds barriers, weighted by what this session has already shown
+func (o *Orchestrator) detectStage(ctx context.Context, turn *TurnState) error {
+	detection := o.barrierDetector.Detect(ctx, turn.Message)
+	turn.Barriers = applyHistory(turn.Session, detection.Barriers)
+	turn.ETPs = detection.ETPs
+
+	response := turn.Response
+	response.DetectedBarriers = extractBarrierNames(turn.Barriers)
+	response.DetectedBarrierIDs = extractBarrierIDs(turn.Barriers)
+	if len(turn.Barriers) > 0 {
+		top := turn.Barriers[0]
+		response.Reasoning = append(response.Reasoning,
+			fmt.Sprintf("🎯 Detected: %s (%.0f%%)", top.Barrier.Name, top.Confidence*100))
+		response.Reasoning = append(response.Reasoning, top.Reasoning...)
+	}
+	if len(turn.ETPs) > 0 {
+		response.ETPs = turn.ETPs
+		names := make([]string, len(turn.ETPs))
+		for i, e := range turn.ETPs {
+			names[i] = fmt.Sprintf("%s (%.0f%%)", e.Name, e.Confidence*100)
+		}
+		response.Reasoning = append(response.Reasoning, "🧠 Sounds like: "+strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// topicStage finds the learning topics, carrying the session's topics into
+// follow-ups, and the student's mastery of the main one
+func (o *Orchestrator) topicStage(ctx context.Context, turn *TurnState) error {
+	if o.curriculum == nil {
+		return nil
+	}
+	turn.Topics = o.curriculum.Resolve(turn.Message, turn.Student.Age, turn.Session.lastTopics())
+	if len(turn.Topics) == 0 {
+		return nil
+	}
+	primary := turn.Topics[0]
+	turn.Topic = primary.Topic
+	turn.Response.Topic = turn.Topic
+	turn.Response.Topics = turn.Topics
+	turn.Response.Reasoning = append(turn.Response.Reasoning,
+		fmt.Sprintf("🧩 Topic: %s (%.0f%%, %s)", primary.Topic, primary.Confidence*100, primary.Source))
+	if len(turn.Topics) > 1 {
+		others := make([]string, 0, len(turn.Topics)-1)
+		for _, match := range turn.Topics[1:] {
+			others = append(others, fmt.Sprintf("%s (%.0f%%)", match.Topic, match.Confidence*100))
+		}
+		turn.Response.Reasoning = append(turn.Response.Reasoning, "🧩 Also mentioned: "+strings.Join(others, ", "))
+	}
+
+	level, estimated, err := o.masteryLevel(ctx, turn.Session.StudentID, turn.Topic)
+	if err != nil {
+		log.Printf("Mastery unavailable for %s: %v", turn.Session.StudentID, err)
+	}
+	turn.Level = level
+	turn.Response.Mastery = &MasteryEvidence{Topic: turn.Topic, Level: level, Estimated: estimated}
+
+	if estimated {
+		turn.Response.Reasoning = append(turn.Response.Reasoning,
+			fmt.Sprintf("📈 Mastery of %s: %.2f", turn.Topic, level))
+	} else {
+		turn.Response.Reasoning = append(turn.Response.Reasoning,
+			fmt.Sprintf("📈 Mastery of %s: no history yet, starting at %.2f", turn.Topic, level))
+	}
+	return nil
+}
+
+// knowledgeStage asks CHISG what the topic needs at the student's level;
+// coaching carries on emotion-only when it is unreachable
+func (o *Orchestrator) knowledgeStage(ctx context.Context, turn *TurnState) error {
+	if turn.Topic == "" {
+		turn.Response.KnowledgeStatus = KnowledgeNoTopic
+		return nil
+	}
+
+	knowledge, err := o.lookupKnowledge(ctx, turn.Topic, turn.Level)
+	if err != nil {
+		log.Printf("CHISG unavailable for %q: %v", turn.Topic, err)
+		turn.Response.KnowledgeStatus = KnowledgeUnavailable
+		turn.Response.Reasoning = append(turn.Response.Reasoning,
+			"📚 CHISG unavailable, proceeding with emotion-only analysis")
+		return nil
+	}
+
+	turn.Knowledge = knowledge
+	turn.Response.KnowledgeContext = knowledge
+	turn.Response.KnowledgeStatus = KnowledgeOK
+	turn.Response.Reasoning = append(turn.Response.Reasoning,
+		fmt.Sprintf("📚 CHISG: %s (%d prerequisite gaps)", turn.Topic, len(knowledge.PrerequisiteGaps)))
+	return nil
+}
+
+// lookupKnowledge queries the knowledge source, treating an empty answer as a failure
+func (o *Orchestrator) lookupKnowledge(ctx context.Context, topic string, level float64) (*integration.KnowledgeContext, error) {
+	if o.knowledge == nil {
+		return nil, errors.New("no knowledge source configured")
+	}
+	knowledge, err := o.knowledge.GetKnowledgeContext(ctx, topic, level)
+	if err != nil {
+		return nil, err
+	}
+	if knowledge == nil {
+		return nil, errors.New("empty knowledge context")
+	}
+	return knowledge, nil
+}
+
+// selectStage picks the intervention, the sequence step, the framing and
+// how hard the next question should be
+func (o *Orchestrator) selectStage(ctx context.Context, turn *TurnState) error {
+	response := turn.Response
+
+	turn.Intervention = o.selectIntervention(turn.Barriers, turn.Student, turn.Knowledge)
+	response.Intervention = turn.Intervention
+	if turn.Intervention != nil {
+		response.Reasoning = append(response.Reasoning,
+			fmt.Sprintf("💡 Intervention: %s", turn.Intervention.Name))
+	}
+
+	// Walk the barrier's response sequence on from the last scripted step
+	turn.Cue = o.nextCue(turn.Session, turn.Message, turn.Barriers)
+	response.Sequence = turn.Cue
+	if cue := turn.Cue; cue != nil {
+		if cue.Complete() {
+			response.Reasoning = append(response.Reasoning, fmt.Sprintf("🏁 %s sequence complete", cue.BarrierID))
+		} else {
+			response.Reasoning = append(response.Reasoning, fmt.Sprintf("🪜 %s step %d (%s): %s",
+				cue.BarrierID, cue.Step, cue.Transition, cue.Action))
+		}
+	}
+
+	barrierID := ""
+	if len(turn.Barriers) > 0 {
+		barrierID = turn.Barriers[0].Barrier.ID
+	}
+	gaps := turn.Knowledge != nil && len(turn.Knowledge.PrerequisiteGaps) > 0
+	turn.Framing = generation.ChooseFraming(barrierID, turn.Student.BrainState, gaps)
+	response.FramingStrategy = turn.Framing
+
+	// Pitch from the student's mastery when the topic is known, otherwise
+	// from the session's streak alone, counting this answer
+	progression := turn.Session.Progression
+	updateProgression(&progression, Turn{DetectedBarriers: toTurnBarriers(turn.Barriers)})
+	if turn.Topic != "" {
+		progression.StartDifficulty = o.mastery.TargetDifficulty(turn.Level)
+	}
+	question := progression.NextQuestion(turn.Student)
+	turn.Question = &question
+	response.Question = turn.Question
+	return nil
+}
+
+// generateStage drafts the reply using the intervention strategy and sequence step
+func (o *Orchestrator) generateStage(ctx context.Context, turn *TurnState) error {
+	draft, notes := o.generateResponse(ctx, turn)
+	turn.Draft = draft
+	turn.Response.Reasoning = append(turn.Response.Reasoning, notes...)
+	return nil
+}
+
+// filterStage makes the draft age-appropriate, checks offense risk and
+// scores readability
+func (o *Orchestrator) filterStage(ctx context.Context, turn *TurnState) error {
+	age := turn.Student.Age
+	response := turn.Response
+
+	adaptation := o.language.Adapt(turn.Draft, age)
+	response.Message = adaptation.Adapted
+	response.Adaptation = adaptation
+	response.Reasoning = append(response.Reasoning, adaptation.Summary())
+
+	if len(adaptation.Risks) > 0 {
+		for _, risk := range adaptation.Risks {
+			response.Reasoning = append(response.Reasoning, fmt.Sprintf("⚠️ %s risk: %s", risk.Risk, risk.Evidence))
+		}
+		response.Reasoning = append(response.Reasoning, "⚠️ Regenerating safer response...")
+		response.Message = o.regenerateSafeResponse(turn.Student, turn.Intervention)
+	}
+
+	o.verifyReadability(response, age)
+	return nil
+}
+
+// rewardStage checks whether the turn earns a play break and mints its code
+func (o *Orchestrator) rewardStage(ctx context.Context, turn *TurnState) error {
+	reward := o.checkRewardEarned(turn.Message, turn.Barriers, turn.Session)
+	unlock := o.mintReward(turn.Session.StudentID, &reward)
+
+	turn.Response.RewardEarned = reward.Earned
+	turn.Response.Reward = unlock
+	if reward.Reason != "" {
+		turn.Response.Reasoning = append(turn.Response.Reasoning, reward.Reason)
+	}
+	return nil
+}
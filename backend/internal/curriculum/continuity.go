@@ -0,0 +1,92 @@
+package curriculum
+
+import "sort"
+
+// Resolve finds a message's topics, keeping the session's topics when the
+// message names none of its own
+// previous is the last turn's result. "What about the second one?" picks
+// one of them; other follow-ups keep them all at full confidence; anything
+// else lets them fade by continuityDecay until they drop out
+func (e *Extractor) Resolve(message string, age int, previous []Match) []Match {
+	found := e.Extract(message, age)
+	if len(found) > 0 || len(previous) == 0 {
+		return found
+	}
+
+	tokens := tokenize(message)
+	if match, ok := e.reference(tokens, previous); ok {
+		return []Match{match}
+	}
+
+	decay := e.taxonomy.Matching.ContinuityDecay
+	if e.followsUp(tokens) {
+		decay = 1
+	}
+
+	carried := []Match{}
+	for _, match := range previous {
+		match.Confidence = round2(match.Confidence * decay)
+		match.Source = SourceContinued
+		match.Terms = nil
+		if match.Confidence >= e.taxonomy.Matching.MinConfidence {
+			carried = append(carried, match)
+		}
+	}
+	return carried
+}
+
+// reference finds an ordinal pointing at one of the previous topics:
+// "the second one", "the last topic", or a message ending "the first"
+func (e *Extractor) reference(tokens []string, previous []Match) (Match, bool) {
+	for i, token := range tokens {
+		position, ok := e.ordinals[token]
+		if !ok {
+			continue
+		}
+		followedByNoun := i+1 < len(tokens) && e.references[tokens[i+1]]
+		endsWithThe := i == len(tokens)-1 && i > 0 && tokens[i-1] == "the"
+		if !followedByNoun && !endsWithThe {
+			continue
+		}
+
+		if match, ok := pick(previous, position); ok {
+			match.Source = SourceReference
+			match.Terms = nil
+			return match, true
+		}
+	}
+	return Match{}, false
+}
+
+// pick chooses by mention order: 1-based, -1 the last, 0 the one that is
+// not currently the main topic
+func pick(previous []Match, position int) (Match, bool) {
+	mentioned := append([]Match(nil), previous...)
+	sort.SliceStable(mentioned, func(i, j int) bool { return mentioned[i].Position < mentioned[j].Position })
+
+	switch {
+	case position == 0:
+		for _, match := range mentioned {
+			if match.ID != previous[0].ID {
+				return match, true
+			}
+		}
+	case position < 0:
+		return mentioned[len(mentioned)-1], true
+	case position <= len(mentioned):
+		return mentioned[position-1], true
+	}
+	return Match{}, false
+}
+
+// followsUp reports whether the message refers back, e.g. "what about it?"
+func (e *Extractor) followsUp(tokens []string) bool {
+	for _, cue := range e.followUps {
+		for i := 0; i+len(cue) <= len(tokens); i++ {
+			if equalTokens(tokens[i:i+len(cue)], cue) {
+				return true
+			}
+		}
+	}
+	return false
+}
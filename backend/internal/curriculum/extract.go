@@ -0,0 +1,299 @@
+package curriculum
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Where a topic match came from
+const (
+	SourceMessage   = "message"   // Named in this message
+	SourceReference = "reference" // Picked from the last turn's topics, e.g. "the second one"
+	SourceContinued = "continued" // Carried over from earlier turns
+)
+
+// Match is a topic recognised for one message
+type Match struct {
+	ID         string   `json:"id"`
+	Topic      string   `json:"topic"` // Topic name, as sent to CHISG
+	Subject    string   `json:"subject"`
+	Confidence float64  `json:"confidence"`
+	Terms      []string `json:"terms,omitempty"` // What in the message matched
+	Position   int      `json:"position"`        // Word index of the first mention; orders "first", "second"
+	Source     string   `json:"source"`
+}
+
+// term is one matchable phrase, already tokenised
+type term struct {
+	tokens  []string
+	text    string
+	topic   *Topic // Nil for subject terms
+	subject string
+	weight  float64
+}
+
+// Extractor finds curriculum topics in student messages
+// Matching is deterministic: ties go to the topic mentioned first
+type Extractor struct {
+	taxonomy   *Taxonomy
+	terms      map[string][]term // First token → topic terms, longest first
+	subjects   map[string][]term // First token → subject terms, longest first
+	followUps  [][]string
+	ordinals   map[string]int
+	references map[string]bool
+}
+
+// NewExtractor indexes a loaded taxonomy
+func NewExtractor(taxonomy *Taxonomy) *Extractor {
+	e := &Extractor{
+		taxonomy:   taxonomy,
+		terms:      map[string][]term{},
+		subjects:   map[string][]term{},
+		ordinals:   map[string]int{},
+		references: map[string]bool{},
+	}
+
+	matching := taxonomy.Matching
+	for i := range taxonomy.Topics {
+		topic := &taxonomy.Topics[i]
+		e.addTerm(e.terms, term{text: topic.Name, topic: topic, subject: topic.Subject, weight: matching.NameWeight})
+		for _, synonym := range topic.Synonyms {
+			e.addTerm(e.terms, term{text: synonym, topic: topic, subject: topic.Subject, weight: matching.SynonymWeight})
+		}
+	}
+	for _, subject := range taxonomy.Subjects {
+		for _, synonym := range subject.Synonyms {
+			e.addTerm(e.subjects, term{text: synonym, subject: subject.ID})
+		}
+	}
+	for _, index := range []map[string][]term{e.terms, e.subjects} {
+		for first := range index {
+			sort.SliceStable(index[first], func(i, j int) bool {
+				return len(index[first][i].tokens) > len(index[first][j].tokens)
+			})
+		}
+	}
+
+	for _, cue := range taxonomy.Continuity.FollowUpCues {
+		if tokens := tokenize(cue); len(tokens) > 0 {
+			e.followUps = append(e.followUps, tokens)
+		}
+	}
+	for word, position := range taxonomy.Continuity.Ordinals {
+		e.ordinals[stem(strings.ToLower(word))] = position
+	}
+	for _, noun := range taxonomy.Continuity.ReferenceNouns {
+		e.references[stem(strings.ToLower(noun))] = true
+	}
+	return e
+}
+
+func (e *Extractor) addTerm(index map[string][]term, t term) {
+	t.tokens = tokenize(t.text)
+	if len(t.tokens) == 0 {
+		return
+	}
+	// Each phrase counts once per topic, at its strongest weight
+	for i, existing := range index[t.tokens[0]] {
+		if existing.topic == t.topic && existing.subject == t.subject && equalTokens(existing.tokens, t.tokens) {
+			index[t.tokens[0]][i].weight = math.Max(existing.weight, t.weight)
+			return
+		}
+	}
+	index[t.tokens[0]] = append(index[t.tokens[0]], t)
+}
+
+// Taxonomy is the curriculum the extractor matches against
+func (e *Extractor) Taxonomy() *Taxonomy {
+	return e.taxonomy
+}
+
+// topicHit gathers one topic's evidence in a message
+type topicHit struct {
+	topic    *Topic
+	evidence []float64
+	terms    []string
+	position int
+}
+
+// Extract ranks the topics a message mentions, most confident first
+// age (0 if unknown) lowers topics outside the student's key stage
+func (e *Extractor) Extract(message string, age int) []Match {
+	tokens := tokenize(message)
+	matching := e.taxonomy.Matching
+
+	hits := map[string]*topicHit{}
+	for i := 0; i < len(tokens); {
+		found := longestTerms(e.terms, tokens, i)
+		if len(found) == 0 {
+			i++
+			continue
+		}
+		for _, t := range found {
+			hit := hits[t.topic.ID]
+			if hit == nil {
+				hit = &topicHit{topic: t.topic, position: i}
+				hits[t.topic.ID] = hit
+			}
+			bonus := matching.PhraseBonus * float64(min(len(t.tokens)-1, 2))
+			hit.evidence = append(hit.evidence, math.Min(t.weight+bonus, 0.95))
+			hit.terms = append(hit.terms, t.text)
+		}
+		i += len(found[0].tokens)
+	}
+	if len(hits) == 0 {
+		return []Match{}
+	}
+
+	mentioned := map[string]bool{}
+	for i := range tokens {
+		for _, t := range longestTerms(e.subjects, tokens, i) {
+			mentioned[t.subject] = true
+		}
+	}
+	stage := e.taxonomy.KeyStageFor(age)
+
+	// A broader topic named alongside one of its subtopics is context for
+	// the subtopic ("balancing equations in chemistry"), not a topic of its own
+	broader := map[string]bool{}
+	for id := range hits {
+		for _, ancestor := range e.taxonomy.Ancestors(id) {
+			if hits[ancestor] != nil {
+				broader[ancestor] = true
+				hits[id].terms = append(hits[id].terms, hits[ancestor].terms...)
+			}
+		}
+	}
+
+	matches := []Match{}
+	for id, hit := range hits {
+		if broader[id] {
+			continue
+		}
+
+		// Independent evidence: each hit, the subject mention and a broader
+		// topic mention can each explain the topic on their own (noisy-OR)
+		miss := 1.0
+		for _, w := range hit.evidence {
+			miss *= 1 - w
+		}
+		if mentioned[hit.topic.Subject] {
+			miss *= 1 - matching.SubjectBoost
+		}
+		for _, ancestor := range e.taxonomy.Ancestors(id) {
+			if broader[ancestor] {
+				miss *= 1 - matching.SubjectBoost
+				break
+			}
+		}
+		confidence := 1 - miss
+		if stage != "" && !contains(hit.topic.KeyStages, stage) {
+			confidence *= matching.OffStagePenalty
+		}
+		if confidence < matching.MinConfidence {
+			continue
+		}
+
+		matches = append(matches, Match{
+			ID:         hit.topic.ID,
+			Topic:      hit.topic.Name,
+			Subject:    hit.topic.Subject,
+			Confidence: round2(confidence),
+			Terms:      hit.terms,
+			Position:   hit.position,
+			Source:     SourceMessage,
+		})
+	}
+
+	sortMatches(matches)
+	if len(matches) > matching.MaxTopics {
+		matches = matches[:matching.MaxTopics]
+	}
+	return matches
+}
+
+// longestTerms returns every term of the greatest length that starts at tokens[i]
+func longestTerms(index map[string][]term, tokens []string, i int) []term {
+	found := []term{}
+	for _, t := range index[tokens[i]] {
+		if len(found) > 0 && len(t.tokens) < len(found[0].tokens) {
+			break
+		}
+		if i+len(t.tokens) <= len(tokens) && equalTokens(tokens[i:i+len(t.tokens)], t.tokens) {
+			found = append(found, t)
+		}
+	}
+	return found
+}
+
+// sortMatches orders by confidence, then by which was mentioned first
+func sortMatches(matches []Match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		if matches[i].Position != matches[j].Position {
+			return matches[i].Position < matches[j].Position
+		}
+		return matches[i].ID < matches[j].ID
+	})
+}
+
+// tokenize lower-cases, splits on anything but letters, digits and
+// apostrophes, and stems each word
+func tokenize(text string) []string {
+	text = strings.ToLower(strings.ReplaceAll(text, "’", "'"))
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimSuffix(strings.Trim(word, "'"), "'s")
+		if word = strings.ReplaceAll(word, "'", ""); word != "" {
+			tokens = append(tokens, stem(word))
+		}
+	}
+	return tokens
+}
+
+// stem folds common plurals so "fractions" matches "fraction"
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 4 && strings.HasSuffix(word, "oes"):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") &&
+		!strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func equalTokens(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,209 @@
+package curriculum
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Taxonomy is curriculum_taxonomy.json: subjects, topics and how to match them
+type Taxonomy struct {
+	Curriculum string     `json:"curriculum"`
+	Matching   Matching   `json:"matching"`
+	KeyStages  []KeyStage `json:"keyStages"`
+	Subjects   []Subject  `json:"subjects"`
+	Topics     []Topic    `json:"topics"`
+	Continuity Continuity `json:"continuity"`
+
+	byID     map[string]*Topic
+	children map[string][]string
+}
+
+// Matching weighs the evidence for a topic
+type Matching struct {
+	NameWeight      float64 `json:"nameWeight"`      // A topic's own name was used
+	SynonymWeight   float64 `json:"synonymWeight"`   // One of its synonyms was used
+	PhraseBonus     float64 `json:"phraseBonus"`     // Added per extra word in a matched term
+	SubjectBoost    float64 `json:"subjectBoost"`    // The message also names the topic's subject
+	OffStagePenalty float64 `json:"offStagePenalty"` // Multiplier when the topic is outside the student's key stage
+	MinConfidence   float64 `json:"minConfidence"`   // Weaker topics are dropped
+	MaxTopics       int     `json:"maxTopics"`       // Most topics reported for one message
+	ContinuityDecay float64 `json:"continuityDecay"` // Per-turn fade for topics carried from earlier turns
+}
+
+// KeyStage is a band of school years, by age
+type KeyStage struct {
+	ID   string `json:"id"`
+	Ages [2]int `json:"ages"`
+}
+
+// Subject groups topics; naming it in a message supports its topics
+type Subject struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// Topic is one curriculum topic; Name is what CHISG and mastery are keyed by
+type Topic struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Subject   string   `json:"subject"`
+	Parent    string   `json:"parent,omitempty"`
+	KeyStages []string `json:"keyStages"`
+	Synonyms  []string `json:"synonyms"`
+}
+
+// Continuity is how follow-up messages keep the session's topics
+type Continuity struct {
+	FollowUpCues   []string       `json:"followUpCues"`
+	Ordinals       map[string]int `json:"ordinals"`       // 1-based mention order; -1 last, 0 the one not currently main
+	ReferenceNouns []string       `json:"referenceNouns"` // "the second one", "the first topic"
+}
+
+// Load reads and validates a taxonomy file
+func Load(path string) (*Taxonomy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read curriculum taxonomy: %w", err)
+	}
+
+	var taxonomy Taxonomy
+	if err := json.Unmarshal(data, &taxonomy); err != nil {
+		return nil, fmt.Errorf("failed to parse curriculum taxonomy: %w", err)
+	}
+	if err := taxonomy.init(); err != nil {
+		return nil, err
+	}
+	return &taxonomy, nil
+}
+
+// Topic looks up a topic by ID
+func (t *Taxonomy) Topic(id string) (*Topic, bool) {
+	topic, ok := t.byID[id]
+	return topic, ok
+}
+
+// Children lists the IDs of a topic's direct subtopics
+func (t *Taxonomy) Children(id string) []string {
+	return append([]string(nil), t.children[id]...)
+}
+
+// Ancestors lists a topic's parents, nearest first
+func (t *Taxonomy) Ancestors(id string) []string {
+	ancestors := []string{}
+	for topic := t.byID[id]; topic != nil && topic.Parent != ""; topic = t.byID[topic.Parent] {
+		ancestors = append(ancestors, topic.Parent)
+	}
+	return ancestors
+}
+
+// KeyStageFor is the key stage covering an age, or "" outside every band
+// Ages past the last band count as its final stage
+func (t *Taxonomy) KeyStageFor(age int) string {
+	if age <= 0 || len(t.KeyStages) == 0 {
+		return ""
+	}
+	for _, stage := range t.KeyStages {
+		if age >= stage.Ages[0] && age < stage.Ages[1] {
+			return stage.ID
+		}
+	}
+	last := t.KeyStages[len(t.KeyStages)-1]
+	if age >= last.Ages[1] {
+		return last.ID
+	}
+	return ""
+}
+
+// init indexes topics and collects every problem before failing
+func (t *Taxonomy) init() error {
+	problems := []string{}
+	t.byID = make(map[string]*Topic, len(t.Topics))
+	t.children = make(map[string][]string)
+
+	stages := map[string]bool{}
+	for _, stage := range t.KeyStages {
+		if stage.Ages[0] >= stage.Ages[1] {
+			problems = append(problems, fmt.Sprintf("key stage %s: ages %v are not a range", stage.ID, stage.Ages))
+		}
+		stages[stage.ID] = true
+	}
+	subjects := map[string]bool{}
+	for _, subject := range t.Subjects {
+		subjects[subject.ID] = true
+	}
+
+	for i := range t.Topics {
+		topic := &t.Topics[i]
+		switch {
+		case topic.ID == "":
+			problems = append(problems, fmt.Sprintf("topic %d: missing id", i))
+			continue
+		case t.byID[topic.ID] != nil:
+			problems = append(problems, fmt.Sprintf("topic %s: duplicate id", topic.ID))
+			continue
+		}
+		t.byID[topic.ID] = topic
+
+		if strings.TrimSpace(topic.Name) == "" {
+			problems = append(problems, fmt.Sprintf("topic %s: missing name", topic.ID))
+		}
+		if !subjects[topic.Subject] {
+			problems = append(problems, fmt.Sprintf("topic %s: unknown subject %q", topic.ID, topic.Subject))
+		}
+		for _, stage := range topic.KeyStages {
+			if !stages[stage] {
+				problems = append(problems, fmt.Sprintf("topic %s: unknown key stage %q", topic.ID, stage))
+			}
+		}
+	}
+
+	for i := range t.Topics {
+		topic := &t.Topics[i]
+		if topic.Parent == "" {
+			continue
+		}
+		parent := t.byID[topic.Parent]
+		if parent == nil {
+			problems = append(problems, fmt.Sprintf("topic %s: unknown parent %q", topic.ID, topic.Parent))
+			continue
+		}
+		if parent.Subject != topic.Subject {
+			problems = append(problems, fmt.Sprintf("topic %s: parent %s is in another subject", topic.ID, parent.ID))
+		}
+		t.children[parent.ID] = append(t.children[parent.ID], topic.ID)
+	}
+	for id := range t.byID {
+		if t.cyclic(id) {
+			problems = append(problems, fmt.Sprintf("topic %s: parent links form a cycle", id))
+		}
+	}
+
+	if t.Matching.MaxTopics <= 0 {
+		problems = append(problems, "matching: maxTopics must be positive")
+	}
+	if d := t.Matching.ContinuityDecay; d <= 0 || d > 1 {
+		problems = append(problems, "matching: continuityDecay must be in (0, 1]")
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid curriculum taxonomy:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// cyclic reports whether following parents from id comes back round
+func (t *Taxonomy) cyclic(id string) bool {
+	seen := map[string]bool{}
+	for topic := t.byID[id]; topic != nil; topic = t.byID[topic.Parent] {
+		if seen[topic.ID] {
+			return true
+		}
+		seen[topic.ID] = true
+	}
+	return false
+}
@@ -0,0 +1,448 @@
+package etp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BarrierStudentProfile is the full barrier definition from barriers.json
+type BarrierStudentProfile struct {
+	ID                    string                `json:"id"`
+	Name                  string                `json:"name"`
+	Category              string                `json:"category"`
+	Manifestations        BarrierManifestations `json:"manifestations"`
+	UnderlyingCauses      UnderlyingCauses      `json:"underlyingCauses"`
+	InterventionStrategy  InterventionStrategy  `json:"interventionStrategy"`
+	AICoachImplementation AICoachImplementation `json:"aiCoachImplementation"`
+	ProgressIndicators    ProgressIndicators    `json:"progressIndicators"`
+	CommonPitfalls        CommonPitfalls        `json:"commonPitfalls,omitempty"`
+	TimelineExpectation   TimelineExpectation   `json:"timelineExpectation"`
+
+	// Free-form sections that differ per barrier
+	DiagnosticAmbiguity   json.RawMessage `json:"diagnosticAmbiguity,omitempty"`
+	SpecialConsiderations json.RawMessage `json:"specialConsiderations,omitempty"`
+	ResourceRequirements  json.RawMessage `json:"resourceRequirements,omitempty"`
+}
+
+// BarrierManifestations describes how a barrier shows up
+type BarrierManifestations struct {
+	Observable []string `json:"observable"`
+	Verbal     []string `json:"verbal"`
+	Paradox    string   `json:"paradox,omitempty"`
+}
+
+// UnderlyingCauses explains why a barrier exists
+// Ambiguous barriers give separate developmental and trauma causes
+type UnderlyingCauses struct {
+	Primary            string   `json:"primary,omitempty"`
+	PrimaryDevelopment string   `json:"primaryDevelopment,omitempty"`
+	PrimaryTrauma      string   `json:"primaryTrauma,omitempty"`
+	Secondary          []string `json:"secondary"`
+	ETPActivation      []string `json:"etpActivation"`
+}
+
+// PrimaryCause returns the best single-line cause description
+func (uc UnderlyingCauses) PrimaryCause() string {
+	if uc.Primary != "" {
+		return uc.Primary
+	}
+	if uc.PrimaryDevelopment != "" && uc.PrimaryTrauma != "" {
+		return uc.PrimaryDevelopment + " or " + strings.ToLower(uc.PrimaryTrauma[:1]) + uc.PrimaryTrauma[1:]
+	}
+	if uc.PrimaryDevelopment != "" {
+		return uc.PrimaryDevelopment
+	}
+	return uc.PrimaryTrauma
+}
+
+// ETPNames strips annotations, e.g. "shame (looking stupid)" → "shame"
+func (uc UnderlyingCauses) ETPNames() []string {
+	names := make([]string, 0, len(uc.ETPActivation))
+	for _, entry := range uc.ETPActivation {
+		name := entry
+		if idx := strings.Index(name, "("); idx >= 0 {
+			name = name[:idx]
+		}
+		names = append(names, strings.TrimSpace(name))
+	}
+	return names
+}
+
+// ETPWeight is how central an ETP is to the barrier, from its place in
+// etpActivation: the first listed is 1, falling evenly to 1/n for the last,
+// and 0 when the barrier does not activate it
+func (uc UnderlyingCauses) ETPWeight(name string) float64 {
+	names := uc.ETPNames()
+	for i, listed := range names {
+		if listed == name {
+			return float64(len(names)-i) / float64(len(names))
+		}
+	}
+	return 0
+}
+
+// InterventionStrategy holds the ordered intervention phases
+type InterventionStrategy struct {
+	Principle string              `json:"principle,omitempty"`
+	Phases    []InterventionPhase `json:"phases"`
+}
+
+// InterventionPhase is one phaseN_* entry of an intervention strategy
+type InterventionPhase struct {
+	Key              string `json:"key"`   // e.g. "phase1_immediateEngagement"
+	Order            int    `json:"order"` // N from the key
+	Name             string `json:"name"`
+	Purpose          string `json:"purpose,omitempty"`
+	Rationale        string `json:"rationale,omitempty"`
+	KeyPhrase        string `json:"keyPhrase,omitempty"`
+	KeyInsight       string `json:"keyInsight,omitempty"`
+	TransitionGoal   string `json:"transitionGoal,omitempty"`
+	BrainStateTarget string `json:"brainStateTarget,omitempty"`
+
+	Implementation      json.RawMessage   `json:"implementation,omitempty"`
+	ProgressionCriteria map[string]string `json:"progressionCriteria,omitempty"`
+	AIImplementation    json.RawMessage   `json:"aiImplementation,omitempty"`
+	Outcomes            map[string]string `json:"outcomes,omitempty"`
+	Examples            json.RawMessage   `json:"examples,omitempty"`
+	Personalization     json.RawMessage   `json:"personalization,omitempty"`
+}
+
+// Steps flattens the implementation block into readable steps, in schema order
+func (p InterventionPhase) Steps() []string {
+	return flattenStrings(p.Implementation, "")
+}
+
+// UnmarshalJSON splits principle strings from phaseN_* objects
+func (s *InterventionStrategy) UnmarshalJSON(data []byte) error {
+	keys, fields, err := orderedObject(data)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		raw := fields[key]
+
+		if !strings.HasPrefix(key, "phase") {
+			var text string
+			if err := json.Unmarshal(raw, &text); err == nil {
+				s.Principle = text
+			}
+			continue
+		}
+
+		var phase InterventionPhase
+		if err := json.Unmarshal(raw, &phase); err != nil {
+			return fmt.Errorf("interventionStrategy.%s: %w", key, err)
+		}
+		phase.Key = key
+		phase.Order = phaseOrder(key)
+		s.Phases = append(s.Phases, phase)
+	}
+
+	sort.SliceStable(s.Phases, func(i, j int) bool {
+		return s.Phases[i].Order < s.Phases[j].Order
+	})
+	return nil
+}
+
+// AICoachImplementation holds the coach-facing detection and response data
+type AICoachImplementation struct {
+	DetectionSignals     []string              `json:"detectionSignals"`
+	ResponseSequence     []ResponseStep        `json:"responseSequence,omitempty"`
+	RewardGeneration     *RewardGeneration     `json:"rewardGeneration,omitempty"`
+	PlayRewardGeneration *PlayRewardGeneration `json:"playRewardGeneration,omitempty"`
+	ExampleInteraction   map[string]string     `json:"exampleInteraction,omitempty"`
+	DetectionPatterns    []DetectionPattern    `json:"detectionPatterns,omitempty"`
+	DetectionWeights     DetectionWeights      `json:"detectionWeights,omitempty"`
+
+	// Free-form protocol sections that differ per barrier
+	ResponseProtocol   json.RawMessage `json:"responseProtocol,omitempty"`
+	ResponseStrategy   json.RawMessage `json:"responseStrategy,omitempty"`
+	InitialAssessment  json.RawMessage `json:"initialAssessment,omitempty"`
+	AssessmentProtocol json.RawMessage `json:"assessmentProtocol,omitempty"`
+	AdaptiveAdjustment json.RawMessage `json:"adaptiveAdjustment,omitempty"`
+}
+
+// DetectionPattern is an explicit regex rule for a barrier
+type DetectionPattern struct {
+	Pattern string  `json:"pattern"`
+	Weight  float64 `json:"weight"`
+	Reason  string  `json:"reason"`
+}
+
+// DetectionWeights sets how much rules built from each schema source count
+// Zero values fall back to the detector defaults
+type DetectionWeights struct {
+	Verbal    float64 `json:"verbal,omitempty"`
+	Signal    float64 `json:"signal,omitempty"`
+	Embedding float64 `json:"embedding,omitempty"` // Nearest-neighbour match; defaults to Verbal
+}
+
+// ResponseStep is one scripted coach turn
+// Messages may use {name} and {interest} placeholders
+type ResponseStep struct {
+	Step            int              `json:"step"`
+	Action          string           `json:"action"`
+	Message         string           `json:"message"`
+	Variants        []MessageVariant `json:"variants,omitempty"`
+	Next            StepTransitions  `json:"next"`
+	ExpectedOutcome string           `json:"expectedOutcome"`
+}
+
+// MessageVariant replaces a step's message for an age range
+type MessageVariant struct {
+	Ages    []int  `json:"ages"` // [min, max], inclusive
+	Message string `json:"message"`
+}
+
+// StepTransitions names the step that follows each kind of student reply
+// 0 ends the sequence; a missing transition falls back to the defaults
+// (attempt moves on one step, avoidance repeats the step)
+type StepTransitions struct {
+	Attempt   *int `json:"attempt,omitempty"`
+	Avoidance *int `json:"avoidance,omitempty"`
+}
+
+// RewardGeneration describes when a reward is issued
+type RewardGeneration struct {
+	Trigger              string `json:"trigger"`
+	Code                 string `json:"code"`
+	TrackUsage           bool   `json:"trackUsage"`
+	TransitionMonitoring string `json:"transitionMonitoring"`
+}
+
+// PlayRewardGeneration describes play-based rewards
+type PlayRewardGeneration struct {
+	Mechanism  string   `json:"mechanism"`
+	Duration   string   `json:"duration"`
+	Content    []string `json:"content"`
+	Tracking   string   `json:"tracking"`
+	Transition string   `json:"transition"`
+}
+
+// ProgressIndicators lists signs of progress by stage
+type ProgressIndicators struct {
+	Early       []string `json:"early"`
+	Developing  []string `json:"developing"`
+	Established []string `json:"established"`
+}
+
+// Pitfall is a known way an intervention goes wrong
+// The schema uses risk/mistake/problem and mitigation/correction/solution interchangeably
+type Pitfall struct {
+	Key        string `json:"key"`
+	Risk       string `json:"risk"`
+	Result     string `json:"result,omitempty"`
+	Mitigation string `json:"mitigation"`
+	Transition string `json:"transition,omitempty"`
+	Response   string `json:"response,omitempty"`
+	Tone       string `json:"tone,omitempty"`
+}
+
+// CommonPitfalls keeps pitfalls in schema order
+type CommonPitfalls []Pitfall
+
+// UnmarshalJSON normalises the pitfall field names
+func (cp *CommonPitfalls) UnmarshalJSON(data []byte) error {
+	keys, fields, err := orderedObject(data)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		var raw map[string]string
+		if err := json.Unmarshal(fields[key], &raw); err != nil {
+			return fmt.Errorf("commonPitfalls.%s: %w", key, err)
+		}
+		*cp = append(*cp, Pitfall{
+			Key:        key,
+			Risk:       firstNonEmpty(raw["risk"], raw["mistake"], raw["problem"]),
+			Result:     raw["result"],
+			Mitigation: firstNonEmpty(raw["mitigation"], raw["correction"], raw["solution"]),
+			Transition: raw["transition"],
+			Response:   raw["response"],
+			Tone:       raw["tone"],
+		})
+	}
+	return nil
+}
+
+// TimelineStage is one entry of a barrier's expected timeline
+type TimelineStage struct {
+	Key         string `json:"key"`
+	Expectation string `json:"expectation"`
+}
+
+// TimelineExpectation keeps timeline stages in schema order
+type TimelineExpectation []TimelineStage
+
+// UnmarshalJSON preserves the order stages are written in
+func (te *TimelineExpectation) UnmarshalJSON(data []byte) error {
+	keys, fields, err := orderedObject(data)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		var text string
+		if err := json.Unmarshal(fields[key], &text); err != nil {
+			return fmt.Errorf("timelineExpectation.%s: %w", key, err)
+		}
+		*te = append(*te, TimelineStage{Key: key, Expectation: text})
+	}
+	return nil
+}
+
+// Lookup returns the expectation for a stage key
+func (te TimelineExpectation) Lookup(key string) (string, bool) {
+	for _, stage := range te {
+		if stage.Key == key {
+			return stage.Expectation, true
+		}
+	}
+	return "", false
+}
+
+// Levers converts the intervention phases into intervention levers
+func (p *BarrierStudentProfile) Levers() []InterventionLever {
+	levers := make([]InterventionLever, 0, len(p.InterventionStrategy.Phases))
+	etps := p.UnderlyingCauses.ETPNames()
+
+	for _, phase := range p.InterventionStrategy.Phases {
+		description := firstNonEmpty(phase.Purpose, phase.Rationale, phase.KeyInsight)
+
+		lever := InterventionLever{
+			Name:             phase.Name,
+			Description:      description,
+			Steps:            phase.Steps(),
+			ETPReduction:     etps,
+			BrainStateTarget: phase.BrainStateTarget,
+		}
+		if phase.TransitionGoal != "" {
+			lever.Benefits = []string{phase.TransitionGoal}
+		}
+		if len(p.TimelineExpectation) > 0 {
+			lever.ExpectedTimeline = p.TimelineExpectation[0].Expectation
+		}
+
+		levers = append(levers, lever)
+	}
+
+	return levers
+}
+
+// ToStudentBarrier builds the summary barrier used across the coach
+func (p *BarrierStudentProfile) ToStudentBarrier() StudentBarrier {
+	return StudentBarrier{
+		ID:               p.ID,
+		Name:             p.Name,
+		Category:         BarrierCategory(p.Category),
+		Description:      p.UnderlyingCauses.PrimaryCause(),
+		ActivatedETPs:    p.UnderlyingCauses.ETPNames(),
+		AvoidanceTactics: p.Manifestations.Verbal,
+		EffectiveLevers:  p.Levers(),
+		UnderlyingCause:  p.UnderlyingCauses.PrimaryCause(),
+	}
+}
+
+// orderedObject decodes a JSON object keeping its key order
+func orderedObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, nil, fmt.Errorf("expected JSON object")
+	}
+
+	keys := []string{}
+	fields := map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		if _, dup := fields[key]; !dup {
+			keys = append(keys, key)
+		}
+		fields[key] = raw
+	}
+
+	return keys, fields, nil
+}
+
+// flattenStrings walks arbitrary JSON and returns its strings in order
+// Object values are prefixed with their key so steps stay readable
+func flattenStrings(raw json.RawMessage, prefix string) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	switch raw[0] {
+	case '"':
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil
+		}
+		if prefix != "" {
+			return []string{prefix + ": " + text}
+		}
+		return []string{text}
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil
+		}
+		out := []string{}
+		for _, item := range items {
+			out = append(out, flattenStrings(item, prefix)...)
+		}
+		return out
+	case '{':
+		keys, fields, err := orderedObject(raw)
+		if err != nil {
+			return nil
+		}
+		out := []string{}
+		for _, key := range keys {
+			out = append(out, flattenStrings(fields[key], key)...)
+		}
+		return out
+	}
+
+	return nil
+}
+
+// phaseOrder extracts N from "phaseN_name"
+func phaseOrder(key string) int {
+	digits := strings.TrimPrefix(key, "phase")
+	if idx := strings.Index(digits, "_"); idx >= 0 {
+		digits = digits[:idx]
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+package etp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Level returns the stored stage name ("level_1" … "level_4")
+func (s PlayBreakStage) Level() string {
+	return fmt.Sprintf("level_%d", int(s)+1)
+}
+
+// String names the stage as the graduation system does
+func (s PlayBreakStage) String() string {
+	switch s {
+	case ConcentrationStage:
+		return "concentration_breaks"
+	case RewardsStage:
+		return "reward_breaks"
+	case ExamPeriodStage:
+		return "exam_period_rewards"
+	case PraiseStage:
+		return "praise_only"
+	default:
+		return s.Level()
+	}
+}
+
+// ParsePlayBreakStage reads a "level_N" stage name, defaulting to ConcentrationStage
+func ParsePlayBreakStage(level string) PlayBreakStage {
+	n, err := strconv.Atoi(strings.TrimPrefix(level, "level_"))
+	if err != nil || n < 1 {
+		return ConcentrationStage
+	}
+	if stage := PlayBreakStage(n - 1); stage <= PraiseStage {
+		return stage
+	}
+	return PraiseStage
+}
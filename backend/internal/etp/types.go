@@ -36,6 +36,7 @@ type ETP struct {
 // StudentContext contains full student state for intervention selection
 type StudentContext struct {
 	StudentID          string         `json:"student_id"`
+	Name               string         `json:"name,omitempty"` // First name the coach may use
 	Age                int            `json:"age"`
 	BrainState         BrainState     `json:"brain_state"`
 	ActivatedETPs      []ETP          `json:"activated_etps"`
@@ -139,6 +140,35 @@ type PlayBreakProfile struct {
 	RewardsEarned   int            `json:"rewards_earned"`   // Count
 	LastProgression time.Time      `json:"last_progression"` // When moved to current stage
 	ReadyToAdvance  bool           `json:"ready_to_advance"` // Calculated flag
+
+	RecentOutcomes []bool            `json:"recent_outcomes"` // Rolling success window, oldest first
+	PacingBarriers []string          `json:"pacing_barriers"` // Barriers seen this stage; their timelines pace it
+	ReadyStreak    int               `json:"ready_streak"`    // Consecutive evaluations meeting criteria
+	Regressions    int               `json:"regressions"`     // Times moved back a stage
+	Requirements   StageRequirements `json:"requirements"`    // What the current stage needs to advance
+}
+
+// MasteryProfile tracks a student's estimated grasp of each topic
+type MasteryProfile struct {
+	Topics map[string]TopicMastery `json:"topics"` // Keyed by normalised topic name
+}
+
+// TopicMastery is the running estimate for one topic
+type TopicMastery struct {
+	Topic        string    `json:"topic"`
+	Level        float64   `json:"level"`        // 0-1, comparable with question difficulty
+	Observations int       `json:"observations"` // Answers the estimate is built on
+	Successes    int       `json:"successes"`    // Of those, engaged attempts
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+// StageRequirements are the thresholds for leaving a play break stage
+type StageRequirements struct {
+	MinDaysInStage  int     `json:"min_days_in_stage"`
+	MinSuccessRate  float64 `json:"min_success_rate"`
+	MinWorkDuration int     `json:"min_work_duration"`  // Minutes of focus before a break
+	RegressBelow    float64 `json:"regress_below"`      // Success rate that sends the student back a stage
+	PacedBy         string  `json:"paced_by,omitempty"` // Barrier whose timeline set MinDaysInStage
 }
 
 // TODO: Future types from project/backend/main.go ideas
@@ -0,0 +1,156 @@
+package generation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/age"
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// Backends selectable with LLM_BACKEND
+const (
+	BackendTemplate = "template"
+	BackendOpenAI   = "openai"
+)
+
+// Request is everything a generator knows about the turn it is replying to
+type Request struct {
+	StudentID      string
+	SessionID      string
+	StudentMessage string
+	Age            int
+	Guidelines     *age.Group // Language rules for the student's age band
+	Barrier        *etp.StudentBarrier
+	Confidence     float64 // Detection confidence for Barrier
+	Intervention   *etp.InterventionLever
+	Framing        string
+	BrainState     etp.BrainState
+	Interests      []string
+	Script         *Cue // Scripted sequence step for this turn, already rendered
+
+	Topic              string   // Learning topic, when one was recognised
+	PrerequisiteGaps   []string // What the topic builds on that the student lacks
+	QuestionDifficulty float64  // 0-1 pitch for any question the reply asks; 0 when unset
+}
+
+// Reply is a generated coach message, before safety and age filtering
+type Reply struct {
+	Text     string `json:"text"`
+	Backend  string `json:"backend"`
+	Fallback string `json:"fallback,omitempty"` // Why the primary backend was skipped
+}
+
+// ResponseGenerator writes the coach's next message
+// Output is raw: callers must still run safety and age filters over it
+type ResponseGenerator interface {
+	Name() string
+	Generate(ctx context.Context, req Request) (*Reply, error)
+}
+
+// Config selects and configures a generator backend
+type Config struct {
+	Backend     string // "template" (default) or "openai"
+	BaseURL     string // OpenAI-compatible API root, e.g. http://localhost:8089/v1
+	APIKey      string
+	Model       string
+	Timeout     time.Duration
+	Temperature float64
+	MaxTokens   int
+	Seed        int64 // Non-zero makes template phrasing choices repeatable
+}
+
+// DefaultConfig returns the deterministic template backend
+func DefaultConfig() Config {
+	return Config{
+		Backend:     BackendTemplate,
+		BaseURL:     "http://localhost:8089/v1",
+		Model:       "gpt-4o-mini",
+		Timeout:     8 * time.Second,
+		Temperature: 0.7,
+		MaxTokens:   200,
+	}
+}
+
+// ConfigFromEnv overlays LLM_* settings on the defaults
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	if v := os.Getenv("LLM_BACKEND"); v != "" {
+		cfg.Backend = strings.ToLower(v)
+	}
+	if v := os.Getenv("LLM_BASE_URL"); v != "" {
+		cfg.BaseURL = v
+	}
+	if v := os.Getenv("LLM_MODEL"); v != "" {
+		cfg.Model = v
+	}
+	cfg.APIKey = os.Getenv("LLM_API_KEY")
+	if v, err := strconv.Atoi(os.Getenv("LLM_TIMEOUT_SECONDS")); err == nil && v > 0 {
+		cfg.Timeout = time.Duration(v) * time.Second
+	}
+	if v, err := strconv.ParseInt(os.Getenv("RESPONSE_SEED"), 10, 64); err == nil {
+		cfg.Seed = v
+	}
+	return cfg
+}
+
+// New builds the configured generator
+// Remote backends fall back to templates so a student always gets a reply
+func New(cfg Config) (ResponseGenerator, error) {
+	templates := NewTemplateGenerator()
+	if cfg.Seed != 0 {
+		templates.UseVariety(NewVariety(cfg.Seed))
+	}
+
+	switch cfg.Backend {
+	case "", BackendTemplate:
+		return templates, nil
+	case BackendOpenAI:
+		if cfg.BaseURL == "" || cfg.Model == "" {
+			return nil, fmt.Errorf("openai backend needs LLM_BASE_URL and LLM_MODEL")
+		}
+		return WithFallback(NewOpenAIGenerator(cfg), templates), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM_BACKEND %q", cfg.Backend)
+	}
+}
+
+// FallbackGenerator tries a primary backend and uses a second one when it fails
+type FallbackGenerator struct {
+	primary  ResponseGenerator
+	fallback ResponseGenerator
+}
+
+// WithFallback wraps primary so errors and empty replies go to fallback
+func WithFallback(primary, fallback ResponseGenerator) *FallbackGenerator {
+	return &FallbackGenerator{primary: primary, fallback: fallback}
+}
+
+// Name reports the primary backend
+func (f *FallbackGenerator) Name() string {
+	return f.primary.Name()
+}
+
+// Generate returns the primary reply, or the fallback's with the reason recorded
+func (f *FallbackGenerator) Generate(ctx context.Context, req Request) (*Reply, error) {
+	reply, err := f.primary.Generate(ctx, req)
+	if err == nil && strings.TrimSpace(reply.Text) != "" {
+		return reply, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("empty reply")
+	}
+	log.Printf("Response generator %s failed, using %s: %v", f.primary.Name(), f.fallback.Name(), err)
+
+	reply, ferr := f.fallback.Generate(ctx, req)
+	if ferr != nil {
+		return nil, fmt.Errorf("%s: %v; %s: %w", f.primary.Name(), err, f.fallback.Name(), ferr)
+	}
+	reply.Fallback = fmt.Sprintf("%s: %v", f.primary.Name(), err)
+	return reply, nil
+}
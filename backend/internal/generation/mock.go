@@ -0,0 +1,125 @@
+package generation
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// MockServer is a local stand-in for an OpenAI-compatible API
+// Replies are deterministic for a given prompt and use the intervention and
+// interests it describes, so the full generate-then-filter path can run offline
+type MockServer struct {
+	Delay    time.Duration // Added before every reply, to exercise timeouts
+	FailWith int           // Non-zero: answer every request with this status
+	requests atomic.Int64
+}
+
+// NewMockServer creates a mock that answers immediately
+func NewMockServer() *MockServer {
+	return &MockServer{}
+}
+
+// Requests is how many completions the mock has served
+func (m *MockServer) Requests() int64 {
+	return m.requests.Load()
+}
+
+// ServeHTTP answers POST .../chat/completions
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+		http.NotFound(w, r)
+		return
+	}
+	m.requests.Add(1)
+
+	if m.Delay > 0 {
+		select {
+		case <-time.After(m.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if m.FailWith != 0 {
+		writeMockError(w, m.FailWith, "mock failure")
+		return
+	}
+
+	var req chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMockError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.Model == "" || len(req.Messages) == 0 {
+		writeMockError(w, http.StatusBadRequest, "model and messages are required")
+		return
+	}
+
+	prompt := ""
+	for _, message := range req.Messages {
+		if message.Role == "user" {
+			prompt = message.Content
+		}
+	}
+
+	resp := map[string]any{
+		"id":      fmt.Sprintf("mock-%d", m.requests.Load()),
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   req.Model,
+		"choices": []map[string]any{{
+			"index":         0,
+			"message":       chatMessage{Role: "assistant", Content: mockReply(prompt)},
+			"finish_reason": "stop",
+		}},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// mockReply writes a plausible coach message from the prompt's fields
+func mockReply(prompt string) string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(prompt, "\n") {
+		if key, value, ok := strings.Cut(line, ": "); ok {
+			if strings.HasPrefix(key, "Scripted step") {
+				key = "Scripted step"
+			}
+			if _, seen := fields[key]; !seen {
+				fields[key] = value
+			}
+		}
+	}
+
+	openers := []string{
+		"I'm glad you told me that.",
+		"Thanks for sticking with this.",
+		"Okay, let's take this one step at a time.",
+	}
+	h := fnv.New32a()
+	h.Write([]byte(prompt))
+	reply := openers[h.Sum32()%uint32(len(openers))]
+
+	if interests := fields["Interests"]; interests != "" {
+		first, _, _ := strings.Cut(interests, ", ")
+		reply += fmt.Sprintf(" Think of it like a level in %s.", first)
+	}
+	if script := fields["Scripted step"]; script != "" {
+		reply += " " + script
+	} else if step := fields["Step 1"]; step != "" {
+		reply += " Let's try this: " + strings.TrimSuffix(step, ".") + "."
+	} else {
+		reply += " What's one small thing you could try next?"
+	}
+	return reply
+}
+
+func writeMockError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"message": message}})
+}
@@ -0,0 +1,109 @@
+package generation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// OpenAIGenerator calls any OpenAI-compatible chat completions API
+// (OpenAI, Azure-style proxies, vLLM, Ollama, or the local mock)
+type OpenAIGenerator struct {
+	cfg    Config
+	client *http.Client
+}
+
+// NewOpenAIGenerator creates a client for cfg.BaseURL
+func NewOpenAIGenerator(cfg Config) *OpenAIGenerator {
+	return &OpenAIGenerator{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Name identifies the backend and model in reasoning
+func (g *OpenAIGenerator) Name() string {
+	return BackendOpenAI + ":" + g.cfg.Model
+}
+
+// Generate sends the built prompt and returns the first choice
+func (g *OpenAIGenerator) Generate(ctx context.Context, req Request) (*Reply, error) {
+	prompt := BuildPrompt(req)
+	body, err := json.Marshal(chatRequest{
+		Model: g.cfg.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: prompt.System},
+			{Role: "user", Content: prompt.User},
+		},
+		Temperature: g.cfg.Temperature,
+		MaxTokens:   g.cfg.MaxTokens,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimRight(g.cfg.BaseURL, "/") + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if g.cfg.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+g.cfg.APIKey)
+	}
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed chatResponse
+	jsonErr := json.Unmarshal(raw, &parsed)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if jsonErr == nil && parsed.Error != nil {
+			return nil, fmt.Errorf("%s: %s", resp.Status, parsed.Error.Message)
+		}
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	if jsonErr != nil {
+		return nil, fmt.Errorf("invalid completion response: %w", jsonErr)
+	}
+	if len(parsed.Choices) == 0 {
+		return nil, fmt.Errorf("completion has no choices")
+	}
+
+	text := strings.TrimSpace(parsed.Choices[0].Message.Content)
+	return &Reply{Text: text, Backend: g.Name()}, nil
+}
@@ -0,0 +1,131 @@
+package generation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// Framing strategies: which emotional lens the reply uses
+const (
+	FramingSmallWins = "achievement_with_small_wins"
+	FramingCuriosity = "curiosity_building_blocks"
+	FramingStatus    = "status_through_mastery"
+	FramingProgress  = "achievement_progress_tracking"
+)
+
+var framingGuidance = map[string]string{
+	FramingSmallWins: "The student is anxious. Break the task into one tiny, certain win before anything else.",
+	FramingCuriosity: "There are gaps in what they know. Treat each missing piece as an interesting building block, never a failing.",
+	FramingStatus:    "The student seeks status. Offer mastery as the way to stand out; never compete with them or shame them.",
+	FramingProgress:  "Point to the progress they are making and the next step on from it.",
+}
+
+// ChooseFraming picks the framing strategy for a turn
+// Anxiety comes first, then knowledge gaps, then confrontational status seeking
+func ChooseFraming(barrierID string, brain etp.BrainState, knowledgeGaps bool) string {
+	switch {
+	case brain.EmotionalLevel > 0.6:
+		return FramingSmallWins
+	case knowledgeGaps:
+		return FramingCuriosity
+	case barrierID == "confrontational_showoff":
+		return FramingStatus
+	default:
+		return FramingProgress
+	}
+}
+
+// Prompt is a chat prompt for an LLM backend
+type Prompt struct {
+	System string `json:"system"`
+	User   string `json:"user"`
+}
+
+// BuildPrompt describes the turn and the rules the reply must follow
+// The age band's limits are restated so the model writes to them; the
+// adapter still enforces them afterwards
+func BuildPrompt(req Request) Prompt {
+	var system strings.Builder
+	system.WriteString("You are HumanOS, a calm, warm learning coach. You help students past the emotional barriers that stop them learning.\n")
+	system.WriteString("Reply with the coach's next message only: two or three short sentences, ending with one small, concrete thing to try.\n")
+	system.WriteString("Never diagnose, label or shame the student. Never discuss self-harm, abuse or violence; if the student raises a worry about their safety, gently suggest talking to a trusted adult.\n")
+
+	if g := req.Guidelines; g != nil {
+		guide := g.LanguageGuidelines
+		fmt.Fprintf(&system, "\nThe student is %d (%s, %s stage).\n", req.Age, g.Name, g.DevelopmentalStage)
+		fmt.Fprintf(&system, "- Vocabulary: %s; words of at most %d syllables.\n", guide.Vocabulary.Level, guide.Vocabulary.MaxSyllables)
+		if len(guide.Vocabulary.CanIntroduce) > 0 {
+			fmt.Fprintf(&system, "- You may introduce: %s.\n", strings.Join(guide.Vocabulary.CanIntroduce, ", "))
+		}
+		fmt.Fprintf(&system, "- Sentences: at most %d words. %s\n", guide.SentenceStructure.MaxWordsPerSentence, guide.SentenceStructure.Structure)
+		if guide.Concepts.Allowed != "" {
+			fmt.Fprintf(&system, "- Concepts: %s\n", guide.Concepts.Allowed)
+		}
+		for _, risk := range guide.OffenseRisks {
+			fmt.Fprintf(&system, "- Avoid %s (%s): %s\n", strings.ToLower(risk.Risk), strings.ToLower(risk.Trigger), risk.Prevention)
+		}
+	}
+
+	var user strings.Builder
+	fmt.Fprintf(&user, "Student message: %q\n", req.StudentMessage)
+
+	if b := req.Barrier; b != nil {
+		fmt.Fprintf(&user, "Barrier: %s (%s), confidence %.0f%%\n", b.Name, b.ID, req.Confidence*100)
+		if b.Description != "" {
+			fmt.Fprintf(&user, "Barrier description: %s\n", b.Description)
+		}
+	} else {
+		user.WriteString("Barrier: none detected; the student is engaging\n")
+	}
+
+	if lever := req.Intervention; lever != nil {
+		fmt.Fprintf(&user, "Intervention: %s\n", lever.Name)
+		if lever.Description != "" {
+			fmt.Fprintf(&user, "Intervention description: %s\n", lever.Description)
+		}
+		for i, step := range lever.Steps {
+			fmt.Fprintf(&user, "Step %d: %s\n", i+1, step)
+		}
+		if lever.BrainStateTarget != "" {
+			fmt.Fprintf(&user, "Brain state target: %s\n", lever.BrainStateTarget)
+		}
+	}
+
+	if script := req.Script; script != nil && script.Message != "" {
+		fmt.Fprintf(&user, "Scripted step %d (%s): %s\n", script.Step, script.Action, script.Message)
+		if script.ExpectedOutcome != "" {
+			fmt.Fprintf(&user, "Aim of this step: %s\n", script.ExpectedOutcome)
+		}
+		user.WriteString("Keep the scripted step's intent; you may reword it to fit the conversation.\n")
+	}
+
+	if req.Topic != "" {
+		fmt.Fprintf(&user, "Topic: %s\n", req.Topic)
+	}
+	if len(req.PrerequisiteGaps) > 0 {
+		fmt.Fprintf(&user, "Prerequisite gaps: %s - secure these before the main topic\n", strings.Join(req.PrerequisiteGaps, ", "))
+	}
+	if req.QuestionDifficulty > 0 {
+		fmt.Fprintf(&user, "Question difficulty: %.2f on a 0-1 scale - pitch any question you ask at this level\n", req.QuestionDifficulty)
+	}
+
+	if req.Framing != "" {
+		fmt.Fprintf(&user, "Framing: %s - %s\n", req.Framing, framingGuidance[req.Framing])
+	}
+
+	brain := req.BrainState
+	fmt.Fprintf(&user, "Brain state: primal %.1f, emotional %.1f, rational %.1f, override risk %.1f\n",
+		brain.PrimalLevel, brain.EmotionalLevel, brain.RationalLevel, brain.OverrideRisk)
+	if brain.EmotionalLevel > 0.7 || brain.OverrideRisk > 0.7 {
+		user.WriteString("Emotional voltage is high: calm and reassure before asking for any thinking.\n")
+	}
+
+	if len(req.Interests) > 0 {
+		fmt.Fprintf(&user, "Interests: %s\n", strings.Join(req.Interests, ", "))
+	}
+
+	user.WriteString("\nWrite the coach's next message.")
+	return Prompt{System: system.String(), User: user.String()}
+}
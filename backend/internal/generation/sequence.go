@@ -0,0 +1,261 @@
+package generation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// ReplyKind is how the student answered the previous scripted step
+type ReplyKind string
+
+const (
+	ReplyAttempt   ReplyKind = "attempt"   // A genuine, barrier-free reply
+	ReplyAvoidance ReplyKind = "avoidance" // The same barrier showed again
+)
+
+// How a cue was reached from the one before it
+const (
+	TransitionStart    = "start"
+	TransitionResume   = "resume"
+	TransitionAdvance  = "advance"
+	TransitionFallback = "fallback"
+	TransitionRepeat   = "repeat"
+	TransitionComplete = "complete"
+)
+
+// defaultInterest stands in for {interest} before the student has mentioned one
+const defaultInterest = "the things you enjoy"
+
+var namePlaceholder = regexp.MustCompile(`\s*,?\s*\{name\}(,\s*)?`)
+
+// Cue is the scripted sequence step chosen for a turn
+type Cue struct {
+	BarrierID       string `json:"barrier_id"`
+	Step            int    `json:"step"` // 0 once the sequence is complete
+	Action          string `json:"action,omitempty"`
+	ExpectedOutcome string `json:"expected_outcome,omitempty"`
+	Transition      string `json:"transition"`
+	Message         string `json:"message,omitempty"` // Rendered for the student
+}
+
+// Complete reports whether the sequence has run to its end
+func (c *Cue) Complete() bool {
+	return c.Step == 0
+}
+
+// Placeholders are the values substituted into step messages
+type Placeholders struct {
+	Name     string
+	Interest string
+}
+
+// Library walks each barrier's aiCoachImplementation.responseSequence
+// across turns, moving on or falling back according to the student's reply
+type Library struct {
+	sequences map[string][]etp.ResponseStep // barrier ID → steps in order
+}
+
+// NewLibrary validates and indexes the response sequences in profiles
+func NewLibrary(profiles []etp.BarrierStudentProfile) (*Library, error) {
+	lib := &Library{sequences: make(map[string][]etp.ResponseStep)}
+	problems := []string{}
+
+	for _, profile := range profiles {
+		steps := profile.AICoachImplementation.ResponseSequence
+		if len(steps) == 0 {
+			continue
+		}
+
+		numbers := map[int]bool{}
+		for _, step := range steps {
+			if step.Step <= 0 || numbers[step.Step] {
+				problems = append(problems, fmt.Sprintf("%s: step %d is not a unique positive number", profile.ID, step.Step))
+			}
+			numbers[step.Step] = true
+		}
+
+		for _, step := range steps {
+			where := fmt.Sprintf("%s step %d", profile.ID, step.Step)
+			if strings.TrimSpace(step.Message) == "" {
+				problems = append(problems, where+": message is empty")
+			}
+			for _, kind := range []ReplyKind{ReplyAttempt, ReplyAvoidance} {
+				target := step.Next.Attempt
+				if kind == ReplyAvoidance {
+					target = step.Next.Avoidance
+				}
+				if target != nil && *target != 0 && !numbers[*target] {
+					problems = append(problems, fmt.Sprintf("%s: next.%s goes to missing step %d", where, kind, *target))
+				}
+			}
+			for _, variant := range step.Variants {
+				if len(variant.Ages) != 2 || variant.Ages[0] > variant.Ages[1] {
+					problems = append(problems, fmt.Sprintf("%s: variant ages %v must be [min, max]", where, variant.Ages))
+				}
+				if strings.TrimSpace(variant.Message) == "" {
+					problems = append(problems, where+": variant message is empty")
+				}
+			}
+		}
+
+		lib.sequences[profile.ID] = steps
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid response sequences:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return lib, nil
+}
+
+// Has reports whether a barrier has a response sequence
+func (l *Library) Has(barrierID string) bool {
+	return len(l.sequences[barrierID]) > 0
+}
+
+// Start returns the first step of a barrier's sequence, or nil if it has none
+func (l *Library) Start(barrierID string) *Cue {
+	steps := l.sequences[barrierID]
+	if len(steps) == 0 {
+		return nil
+	}
+	return newCue(barrierID, steps[0], TransitionStart)
+}
+
+// Next follows the previous step's transition for the student's reply
+// Avoidance after a completed sequence starts it again; an attempt after
+// one needs no script, so Next returns nil
+func (l *Library) Next(prev Cue, reply ReplyKind) *Cue {
+	if prev.Complete() {
+		if reply == ReplyAvoidance {
+			return l.Start(prev.BarrierID)
+		}
+		return nil
+	}
+
+	current, ok := l.step(prev.BarrierID, prev.Step)
+	if !ok {
+		return l.Start(prev.BarrierID)
+	}
+
+	target := l.target(prev.BarrierID, current, reply)
+	if target == 0 {
+		return &Cue{BarrierID: prev.BarrierID, Transition: TransitionComplete}
+	}
+
+	next, ok := l.step(prev.BarrierID, target)
+	if !ok {
+		return nil
+	}
+	transition := TransitionRepeat
+	switch {
+	case target > current.Step:
+		transition = TransitionAdvance
+	case target < current.Step:
+		transition = TransitionFallback
+	}
+	return newCue(prev.BarrierID, next, transition)
+}
+
+// Resume picks a sequence back up when its barrier returns after other turns
+// The barrier showing again counts as avoidance of the step left off at
+func (l *Library) Resume(prev Cue) *Cue {
+	cue := l.Next(prev, ReplyAvoidance)
+	if cue != nil && cue.Transition != TransitionStart {
+		cue.Transition = TransitionResume
+	}
+	return cue
+}
+
+// Render fills in the cue's message for the student's age
+func (l *Library) Render(cue *Cue, studentAge int, values Placeholders) {
+	step, ok := l.step(cue.BarrierID, cue.Step)
+	if !ok {
+		cue.Message = ""
+		return
+	}
+
+	message := step.Message
+	for _, variant := range step.Variants {
+		if len(variant.Ages) == 2 && studentAge >= variant.Ages[0] && studentAge <= variant.Ages[1] {
+			message = variant.Message
+			break
+		}
+	}
+	cue.Message = fillPlaceholders(message, values)
+}
+
+func (l *Library) step(barrierID string, number int) (etp.ResponseStep, bool) {
+	for _, step := range l.sequences[barrierID] {
+		if step.Step == number {
+			return step, true
+		}
+	}
+	return etp.ResponseStep{}, false
+}
+
+// target applies the step's transition, or the default when the schema has none
+func (l *Library) target(barrierID string, step etp.ResponseStep, reply ReplyKind) int {
+	if reply == ReplyAvoidance {
+		if step.Next.Avoidance != nil {
+			return *step.Next.Avoidance
+		}
+		return step.Step
+	}
+
+	if step.Next.Attempt != nil {
+		return *step.Next.Attempt
+	}
+	steps := l.sequences[barrierID]
+	for i, s := range steps {
+		if s.Step == step.Step && i+1 < len(steps) {
+			return steps[i+1].Step
+		}
+	}
+	return 0
+}
+
+func newCue(barrierID string, step etp.ResponseStep, transition string) *Cue {
+	return &Cue{
+		BarrierID:       barrierID,
+		Step:            step.Step,
+		Action:          step.Action,
+		ExpectedOutcome: step.ExpectedOutcome,
+		Transition:      transition,
+	}
+}
+
+// fillPlaceholders substitutes {name} and {interest}
+// An unknown name is dropped along with its comma, e.g. "Nice one, {name}." → "Nice one."
+func fillPlaceholders(message string, values Placeholders) string {
+	interest := values.Interest
+	if interest == "" {
+		interest = defaultInterest
+	}
+	message = strings.ReplaceAll(message, "{interest}", interest)
+
+	if values.Name != "" {
+		return strings.ReplaceAll(message, "{name}", values.Name)
+	}
+
+	startsWithName := strings.HasPrefix(message, "{name}")
+	message = namePlaceholder.ReplaceAllStringFunc(message, func(match string) string {
+		// Between two words keep one separator: "Well, {name}, you" → "Well, you"
+		i := strings.Index(match, "{name}")
+		lead, trail := match[:i], match[i+len("{name}"):]
+		if lead != "" && trail != "" {
+			return strings.TrimSpace(lead) + " "
+		}
+		return ""
+	})
+	message = strings.TrimSpace(message)
+	if startsWithName {
+		r, size := utf8.DecodeRuneInString(message)
+		message = string(unicode.ToUpper(r)) + message[size:]
+	}
+	return message
+}
@@ -0,0 +1,126 @@
+package generation
+
+import (
+	"context"
+	"strings"
+)
+
+// TemplateGenerator replies from canned, reviewed wording
+// It is deterministic and needs no network, so it is the default and the fallback
+type TemplateGenerator struct {
+	variety *Variety
+}
+
+// NewTemplateGenerator creates the template backend
+func NewTemplateGenerator() *TemplateGenerator {
+	return &TemplateGenerator{variety: NewRandomVariety()}
+}
+
+// UseVariety replaces the phrasing picker, e.g. with a seeded one for tests
+func (t *TemplateGenerator) UseVariety(variety *Variety) {
+	t.variety = variety
+}
+
+// Name identifies the backend in reasoning
+func (t *TemplateGenerator) Name() string {
+	return BackendTemplate
+}
+
+// Generate picks wording for the barrier and intervention
+func (t *TemplateGenerator) Generate(ctx context.Context, req Request) (*Reply, error) {
+	text := t.compose(req)
+	if len(req.PrerequisiteGaps) > 0 {
+		text += " To get there, let's first make sure you're solid on " + strings.Join(req.PrerequisiteGaps, ", ") + "."
+	}
+	return &Reply{Text: text, Backend: t.Name()}, nil
+}
+
+func (t *TemplateGenerator) compose(req Request) string {
+	// PRIORITY 1: The scripted sequence step (most contextual); after a
+	// genuine attempt it carries on even though no barrier is detected
+	if script := req.Script; script != nil && script.Message != "" {
+		return script.Message
+	}
+
+	// PRIORITY 2: No barriers detected = positive engagement
+	if req.Barrier == nil {
+		return t.variety.Pick(req.StudentID, req.SessionID, positiveEngagementPhrases(req.Age))
+	}
+
+	// PRIORITY 3: Intervention-based response
+	if lever := req.Intervention; lever != nil {
+		if lever.Description != "" {
+			return translateStepToResponse(lever.Description)
+		}
+		if len(lever.Steps) > 0 {
+			return translateStepToResponse(lever.Steps[0])
+		}
+	}
+
+	// PRIORITY 4: Fallback generic
+	return "I'm here to help. What would you like to work on?"
+}
+
+// positiveEngagementPhrases are the praise lines for a genuinely engaged student
+// Praise for effort and strategy is weighted above general praise
+func positiveEngagementPhrases(age int) []Phrase {
+	// Vary response based on age
+	if age < 10 {
+		return []Phrase{
+			{Text: "That's great thinking! You're doing really well.", Weight: 1},
+			{Text: "I love that you want to learn more! Keep going!", Weight: 1},
+			{Text: "You're asking smart questions. That's how you get better!", Weight: 2},
+			{Text: "You kept trying, and look - you did it!", Weight: 2},
+			{Text: "Wow, you really thought about that. Nice work!", Weight: 1.5},
+			{Text: "You're working hard. I can tell!", Weight: 1.5},
+		}
+	}
+
+	return []Phrase{
+		{Text: "That's exactly the kind of thinking I want to see! You're really understanding this.", Weight: 1},
+		{Text: "Excellent question! It shows you're thinking deeply about the material.", Weight: 1.5},
+		{Text: "You're making real progress. Let's keep building on this momentum.", Weight: 1},
+		{Text: "I can tell you're genuinely engaged. That's how real learning happens!", Weight: 1},
+		{Text: "Perfect! You're asking the right questions. Let's explore this further.", Weight: 1.5},
+		{Text: "The way you worked through that step by step really paid off.", Weight: 2},
+		{Text: "You stuck with it when it got tricky. That's the part that counts.", Weight: 2},
+	}
+}
+
+// translateStepToResponse converts intervention step/description to actual response
+func translateStepToResponse(step string) string {
+	if step == "" {
+		return "I'm here to help. What would you like to work on?"
+	}
+
+	stepLower := strings.ToLower(step)
+
+	// Pattern-based matching for common intervention phrases
+	if strings.Contains(stepLower, "find") || strings.Contains(stepLower, "recognize") {
+		return "I can see you're working on this. Let's find something small you're doing well!"
+	}
+	if strings.Contains(stepLower, "lower") || strings.Contains(stepLower, "easy") {
+		return "How about we try something super easy first? Just to get warmed up."
+	}
+	if strings.Contains(stepLower, "show interest") || strings.Contains(stepLower, "chat") {
+		return "Before we dive in, how are you feeling today? Anything on your mind?"
+	}
+	if strings.Contains(stepLower, "guide") || strings.Contains(stepLower, "help") {
+		return "Let me help you out here. Can you tell me just one thing you think about this topic?"
+	}
+	if strings.Contains(stepLower, "celebrate") || strings.Contains(stepLower, "praise") {
+		return "That's a great start! You're doing really well just by being here and trying."
+	}
+	if strings.Contains(stepLower, "game") || strings.Contains(stepLower, "reward") {
+		return "Complete this and you'll get a reward. Let's see what you can do!"
+	}
+	if strings.Contains(stepLower, "micro") || strings.Contains(stepLower, "tiny") {
+		return "Let's start with something really easy - just to warm up. You've got this!"
+	}
+	if strings.Contains(stepLower, "shoulder") || strings.Contains(stepLower, "right here") {
+		return "I'm right here with you. Let's start with something tiny - what's one thing you could try?"
+	}
+
+	// Fallback: use step text directly with supportive framing
+	return "Let's work through this together. " + step
+}
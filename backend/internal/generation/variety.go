@@ -0,0 +1,141 @@
+package generation
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Variety tuning
+const (
+	varietyRecentLimit   = 10   // Phrasings remembered per student across sessions
+	varietyRecentPenalty = 0.25 // Weight multiplier for a phrasing heard recently
+)
+
+// Phrase is one interchangeable wording with its relative weight
+type Phrase struct {
+	Text   string
+	Weight float64 // Zero counts as 1
+}
+
+// Variety picks between interchangeable phrasings so a student does not
+// hear the same line twice in a session, and lines heard in recent
+// sessions come up less often
+type Variety struct {
+	mu       sync.Mutex
+	rng      *rand.Rand
+	students map[string]*heardPhrases
+}
+
+// heardPhrases is what one student has been told
+type heardPhrases struct {
+	sessionID string
+	session   map[string]bool // Heard in the current session
+	recent    []string        // Most recent last, across sessions
+}
+
+// NewVariety creates a variety engine; the same seed gives the same choices
+func NewVariety(seed int64) *Variety {
+	return &Variety{
+		rng:      rand.New(rand.NewSource(seed)),
+		students: make(map[string]*heardPhrases),
+	}
+}
+
+// NewRandomVariety seeds from the clock
+func NewRandomVariety() *Variety {
+	return NewVariety(time.Now().UnixNano())
+}
+
+// Pick chooses a phrasing for the student and remembers it
+// Phrasings already heard this session are skipped until every one has
+// been used, then the rotation starts again without repeating the last line
+func (v *Variety) Pick(studentID, sessionID string, phrases []Phrase) string {
+	if len(phrases) == 0 {
+		return ""
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	heard := v.students[studentID]
+	if heard == nil {
+		heard = &heardPhrases{}
+		v.students[studentID] = heard
+	}
+	if heard.sessionID != sessionID || heard.session == nil {
+		heard.sessionID = sessionID
+		heard.session = map[string]bool{}
+	}
+
+	candidates := unheard(phrases, heard.session)
+	if len(candidates) == 0 {
+		heard.session = map[string]bool{}
+		last := ""
+		if len(heard.recent) > 0 {
+			last = heard.recent[len(heard.recent)-1]
+		}
+		for _, phrase := range phrases {
+			if phrase.Text != last {
+				candidates = append(candidates, phrase)
+			}
+		}
+		if len(candidates) == 0 {
+			candidates = phrases
+		}
+	}
+
+	choice := v.weightedChoice(candidates, heard.recent)
+	heard.session[choice] = true
+	heard.recent = append(heard.recent, choice)
+	if len(heard.recent) > varietyRecentLimit {
+		heard.recent = heard.recent[len(heard.recent)-varietyRecentLimit:]
+	}
+	return choice
+}
+
+// Forget drops everything remembered for a student
+func (v *Variety) Forget(studentID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.students, studentID)
+}
+
+// weightedChoice draws one phrase, damping those heard in recent sessions
+func (v *Variety) weightedChoice(candidates []Phrase, recent []string) string {
+	weights := make([]float64, len(candidates))
+	total := 0.0
+	for i, phrase := range candidates {
+		weight := phrase.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		for _, text := range recent {
+			if text == phrase.Text {
+				weight *= varietyRecentPenalty
+				break
+			}
+		}
+		weights[i] = weight
+		total += weight
+	}
+
+	draw := v.rng.Float64() * total
+	for i, weight := range weights {
+		if draw < weight {
+			return candidates[i].Text
+		}
+		draw -= weight
+	}
+	return candidates[len(candidates)-1].Text
+}
+
+func unheard(phrases []Phrase, heard map[string]bool) []Phrase {
+	out := []Phrase{}
+	for _, phrase := range phrases {
+		if !heard[phrase.Text] {
+			out = append(out, phrase)
+		}
+	}
+	return out
+}
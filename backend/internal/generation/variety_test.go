@@ -0,0 +1,63 @@
+package generation
+
+import (
+	"fmt"
+	"testing"
+)
+
+var testPhrases = []Phrase{
+	{Text: "Nice work!", Weight: 10},
+	{Text: "Great thinking!", Weight: 1},
+	{Text: "You've got it!", Weight: 1},
+	{Text: "That's the one!"},
+}
+
+func TestVarietyNoRepeatsWithinSession(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		v := NewVariety(seed)
+		heard := map[string]bool{}
+		for i := range testPhrases {
+			choice := v.Pick("student-1", "session-1", testPhrases)
+			if heard[choice] {
+				t.Fatalf("seed %d: %q repeated at pick %d before every phrasing was used", seed, choice, i+1)
+			}
+			heard[choice] = true
+		}
+		if len(heard) != len(testPhrases) {
+			t.Fatalf("seed %d: heard %d phrasings, want all %d", seed, len(heard), len(testPhrases))
+		}
+	}
+}
+
+func TestVarietyRotationReachesEveryPhrasing(t *testing.T) {
+	v := NewVariety(42)
+	counts := map[string]int{}
+	for session := 0; session < 50; session++ {
+		sessionID := fmt.Sprintf("session-%d", session)
+		last := ""
+		for i := 0; i < 2*len(testPhrases); i++ {
+			choice := v.Pick("student-1", sessionID, testPhrases)
+			if choice == last {
+				t.Fatalf("%s: %q picked twice in a row when the rotation restarted", sessionID, choice)
+			}
+			counts[choice]++
+			last = choice
+		}
+	}
+
+	for _, phrase := range testPhrases {
+		if counts[phrase.Text] == 0 {
+			t.Errorf("%q was never picked", phrase.Text)
+		}
+	}
+}
+
+func TestVarietySeedIsDeterministic(t *testing.T) {
+	a, b := NewVariety(7), NewVariety(7)
+	for i := 0; i < 20; i++ {
+		sessionID := fmt.Sprintf("session-%d", i/3)
+		if x, y := a.Pick("s", sessionID, testPhrases), b.Pick("s", sessionID, testPhrases); x != y {
+			t.Fatalf("pick %d: %q and %q differ for the same seed", i, x, y)
+		}
+	}
+}
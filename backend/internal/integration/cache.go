@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheMaxEntries bounds memory; expired entries go first, then the oldest
+const cacheMaxEntries = 512
+
+// ttlCache keeps successful CHISG answers for a fixed time
+type ttlCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value    any
+	storedAt time.Time
+}
+
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// cacheKey normalises the topic and buckets the level to two decimals,
+// so 0.5 and 0.501 share an entry
+func cacheKey(kind, topic string, studentLevel float64) string {
+	return fmt.Sprintf("%s|%s|%.2f", kind, strings.ToLower(strings.TrimSpace(topic)), studentLevel)
+}
+
+func (c *ttlCache) get(key string) (any, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().Sub(entry.storedAt) >= c.ttl {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *ttlCache) set(key string, value any) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= cacheMaxEntries {
+		c.evictLocked(now)
+	}
+	c.entries[key] = cacheEntry{value: value, storedAt: now}
+}
+
+func (c *ttlCache) evictLocked(now time.Time) {
+	oldestKey := ""
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if now.Sub(entry.storedAt) >= c.ttl {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.storedAt.Before(oldest) {
+			oldestKey, oldest = key, entry.storedAt
+		}
+	}
+	if len(c.entries) >= cacheMaxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
@@ -2,29 +2,123 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Errors a CHISG call can wrap; match them with errors.Is
+var (
+	ErrUnavailable     = errors.New("chisg unavailable")      // Network failure or 5xx
+	ErrTimeout         = errors.New("chisg timed out")        // The call's deadline passed
+	ErrCircuitOpen     = errors.New("chisg circuit open")     // Failing fast after repeated failures
+	ErrTopicNotFound   = errors.New("chisg topic not found")  // 404: CHISG has no graph for the topic
+	ErrRejected        = errors.New("chisg rejected request") // Any other 4xx
+	ErrInvalidResponse = errors.New("chisg invalid response") // Body was not the expected JSON
+)
+
+// Error is a failed CHISG call
+// Kind is one of the Err* values above; Err is the underlying cause, if any
+type Error struct {
+	Op         string // "analyze" or "prerequisites"
+	Kind       error
+	StatusCode int // Zero when no response arrived
+	Err        error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Op, e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (HTTP %d)", e.StatusCode)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is/As
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// CHISGConfig tunes the CHISG client
+type CHISGConfig struct {
+	BaseURL          string
+	Timeout          time.Duration // Per call, unless the caller's deadline is sooner
+	CacheTTL         time.Duration // Zero disables caching
+	FailureThreshold int           // Consecutive failures that open the circuit
+	Cooldown         time.Duration // How long the circuit stays open
+}
+
+// DefaultCHISGConfig returns a local CHISG with short timeouts
+func DefaultCHISGConfig() CHISGConfig {
+	return CHISGConfig{
+		BaseURL:          "http://localhost:8082",
+		Timeout:          2 * time.Second,
+		CacheTTL:         5 * time.Minute,
+		FailureThreshold: 3,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// CHISGConfigFromEnv overlays CHISG_* settings on the defaults
+func CHISGConfigFromEnv() CHISGConfig {
+	cfg := DefaultCHISGConfig()
+	if v := os.Getenv("CHISG_API_URL"); v != "" {
+		cfg.BaseURL = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("CHISG_TIMEOUT_MS")); err == nil && v > 0 {
+		cfg.Timeout = time.Duration(v) * time.Millisecond
+	}
+	if v, err := strconv.Atoi(os.Getenv("CHISG_CACHE_TTL_SECONDS")); err == nil && v >= 0 {
+		cfg.CacheTTL = time.Duration(v) * time.Second
+	}
+	if v, err := strconv.Atoi(os.Getenv("CHISG_BREAKER_FAILURES")); err == nil && v > 0 {
+		cfg.FailureThreshold = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("CHISG_BREAKER_COOLDOWN_SECONDS")); err == nil && v > 0 {
+		cfg.Cooldown = time.Duration(v) * time.Second
+	}
+	return cfg
+}
+
+// CHISGClient queries the CHISG knowledge graph
+// Calls carry a deadline, successful answers are cached, and repeated
+// failures open a circuit so a sick CHISG cannot stall coaching
 type CHISGClient struct {
 	baseURL string
+	timeout time.Duration
 	client  *http.Client
+	breaker *Breaker
+	cache   *ttlCache
 }
 
-func NewCHISGClient() *CHISGClient {
-	baseURL := os.Getenv("CHISG_API_URL")
-	if baseURL == "" {
-		baseURL = "http://localhost:8082"
-	}
-
+func NewCHISGClient(cfg CHISGConfig) *CHISGClient {
 	return &CHISGClient{
-		baseURL: baseURL,
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		timeout: cfg.Timeout,
 		client:  &http.Client{},
+		breaker: NewBreaker(cfg.FailureThreshold, cfg.Cooldown),
+		cache:   newTTLCache(cfg.CacheTTL),
 	}
 }
 
+// CircuitState reports the breaker state, for health checks
+func (c *CHISGClient) CircuitState() string {
+	return c.breaker.State()
+}
+
 // SemanticLink represents a connection in CHISG knowledge graph
 type SemanticLink struct {
 	FromConcept  string  `json:"from_concept"`
@@ -55,63 +149,121 @@ type KnowledgeContext struct {
 }
 
 // GetKnowledgeContext queries CHISG for semantic understanding of a topic
-func (c *CHISGClient) GetKnowledgeContext(topic string, studentLevel float64) (*KnowledgeContext, error) {
+// Answers are cached per topic and student level; treat them as read-only
+func (c *CHISGClient) GetKnowledgeContext(ctx context.Context, topic string, studentLevel float64) (*KnowledgeContext, error) {
+	key := cacheKey("analyze", topic, studentLevel)
+	if cached, ok := c.cache.get(key); ok {
+		return cached.(*KnowledgeContext), nil
+	}
+
 	reqBody := map[string]interface{}{
 		"topic":         topic,
 		"student_level": studentLevel,
 	}
-
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
+	var knowledge KnowledgeContext
+	if err := c.post(ctx, "analyze", "/api/knowledge/analyze", reqBody, &knowledge); err != nil {
 		return nil, err
 	}
 
-	resp, err := c.client.Post(
-		c.baseURL+"/api/knowledge/analyze",
-		"application/json",
-		bytes.NewBuffer(jsonBody),
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	c.cache.set(key, &knowledge)
+	return &knowledge, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("CHISG API error: %d", resp.StatusCode)
+// IdentifyPrerequisites asks CHISG what student needs to know first
+func (c *CHISGClient) IdentifyPrerequisites(ctx context.Context, topic string) ([]string, error) {
+	key := cacheKey("prerequisites", topic, 0)
+	if cached, ok := c.cache.get(key); ok {
+		return append([]string(nil), cached.([]string)...), nil
 	}
 
-	var context KnowledgeContext
-	if err := json.NewDecoder(resp.Body).Decode(&context); err != nil {
+	reqBody := map[string]string{"topic": topic}
+	var result struct {
+		Prerequisites []string `json:"prerequisites"`
+	}
+	if err := c.post(ctx, "prerequisites", "/api/knowledge/prerequisites", reqBody, &result); err != nil {
 		return nil, err
 	}
 
-	return &context, nil
+	c.cache.set(key, result.Prerequisites)
+	return append([]string(nil), result.Prerequisites...), nil
 }
 
-// IdentifyPrerequisites asks CHISG what student needs to know first
-func (c *CHISGClient) IdentifyPrerequisites(topic string) ([]string, error) {
-	reqBody := map[string]string{"topic": topic}
-	jsonBody, err := json.Marshal(reqBody)
+// post sends one JSON request through the breaker and decodes a 200 reply into out
+func (c *CHISGClient) post(ctx context.Context, op, path string, body, out any) error {
+	if !c.breaker.Allow() {
+		return &Error{Op: op, Kind: ErrCircuitOpen}
+	}
+
+	err := c.do(ctx, op, path, body, out)
+	switch {
+	case err == nil:
+		c.breaker.Success()
+	case errors.Is(err, ErrUnavailable), errors.Is(err, ErrTimeout), errors.Is(err, ErrInvalidResponse):
+		// The caller giving up says nothing about CHISG's health
+		if ctx.Err() != nil {
+			c.breaker.Release()
+		} else {
+			c.breaker.Failure()
+		}
+	default:
+		// CHISG answered sensibly, just not with a graph
+		c.breaker.Success()
+	}
+	return err
+}
+
+func (c *CHISGClient) do(ctx context.Context, op, path string, body, out any) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	jsonBody, err := json.Marshal(body)
 	if err != nil {
-		return nil, err
+		return &Error{Op: op, Kind: ErrRejected, Err: err}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(jsonBody))
+	if err != nil {
+		return &Error{Op: op, Kind: ErrRejected, Err: err}
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Post(
-		c.baseURL+"/api/knowledge/prerequisites",
-		"application/json",
-		bytes.NewBuffer(jsonBody),
-	)
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &Error{Op: op, Kind: ErrTimeout, Err: err}
+		}
+		return &Error{Op: op, Kind: ErrUnavailable, Err: err}
 	}
 	defer resp.Body.Close()
 
-	var result struct {
-		Prerequisites []string `json:"prerequisites"`
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &Error{Op: op, Kind: statusKind(resp.StatusCode), StatusCode: resp.StatusCode}
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if text := strings.TrimSpace(string(detail)); text != "" {
+			apiErr.Err = errors.New(text)
+		}
+		return apiErr
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &Error{Op: op, Kind: ErrTimeout, Err: err}
+		}
+		return &Error{Op: op, Kind: ErrInvalidResponse, StatusCode: resp.StatusCode, Err: err}
 	}
+	return nil
+}
 
-	return result.Prerequisites, nil
+// statusKind classifies a non-200 status
+func statusKind(status int) error {
+	switch {
+	case status == http.StatusNotFound:
+		return ErrTopicNotFound
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests, status >= 500:
+		return ErrUnavailable
+	default:
+		return ErrRejected
+	}
 }
@@ -0,0 +1,272 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testClock is a settable clock for the breaker and cache
+type testClock struct{ t time.Time }
+
+func (c *testClock) now() time.Time          { return c.t }
+func (c *testClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// startClient serves the default graph and points a client at it
+func startClient(t *testing.T, cfg CHISGConfig) (*CHISGClient, *FakeServer, *testClock) {
+	t.Helper()
+	fake, server := StartFakeServer(DefaultFakeGraph()...)
+	t.Cleanup(server.Close)
+
+	cfg.BaseURL = server.URL
+	client := NewCHISGClient(cfg)
+	clock := &testClock{t: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)}
+	client.breaker.now = clock.now
+	client.cache.now = clock.now
+	return client, fake, clock
+}
+
+func testConfig() CHISGConfig {
+	return CHISGConfig{
+		Timeout:          time.Second,
+		FailureThreshold: 2,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+func TestCHISGClientAnalyzesFromFake(t *testing.T) {
+	client, _, _ := startClient(t, testConfig())
+
+	knowledge, err := client.GetKnowledgeContext(context.Background(), "Algebra", 0.35)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"fractions", "negative numbers"}
+	if len(knowledge.PrerequisiteGaps) != len(want) {
+		t.Fatalf("gaps = %v, want %v", knowledge.PrerequisiteGaps, want)
+	}
+	for i, gap := range want {
+		if knowledge.PrerequisiteGaps[i] != gap {
+			t.Fatalf("gaps = %v, want %v", knowledge.PrerequisiteGaps, want)
+		}
+	}
+	if last := knowledge.SuggestedPath.Nodes[len(knowledge.SuggestedPath.Nodes)-1]; last.Concept != "algebra" {
+		t.Errorf("path ends at %q, want algebra", last.Concept)
+	}
+}
+
+func TestCHISGClientDeadline(t *testing.T) {
+	cfg := testConfig()
+	cfg.Timeout = 20 * time.Millisecond
+	client, fake, _ := startClient(t, cfg)
+	fake.SetDelay(time.Second)
+
+	start := time.Now()
+	_, err := client.GetKnowledgeContext(context.Background(), "fractions", 0.5)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("err = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("call took %s; the deadline should have cut it short", elapsed)
+	}
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Op != "analyze" {
+		t.Errorf("err = %#v, want *Error for analyze", err)
+	}
+}
+
+func TestCHISGClientBreakerCycle(t *testing.T) {
+	client, fake, clock := startClient(t, testConfig())
+	ctx := context.Background()
+
+	fake.FailWith(http.StatusServiceUnavailable)
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetKnowledgeContext(ctx, "fractions", 0.5); !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("call %d: err = %v, want ErrUnavailable", i+1, err)
+		}
+	}
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Fatalf("after threshold failures state = %s, want open", state)
+	}
+
+	// Open: fail fast without reaching CHISG
+	before := fake.Requests()
+	if _, err := client.GetKnowledgeContext(ctx, "fractions", 0.5); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("open circuit: err = %v, want ErrCircuitOpen", err)
+	}
+	if fake.Requests() != before {
+		t.Fatal("open circuit still called CHISG")
+	}
+
+	// Half-open: a failed trial reopens for another cooldown
+	clock.advance(31 * time.Second)
+	if state := client.CircuitState(); state != CircuitHalfOpen {
+		t.Fatalf("after cooldown state = %s, want half_open", state)
+	}
+	if _, err := client.GetKnowledgeContext(ctx, "fractions", 0.5); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("failed trial: err = %v, want ErrUnavailable", err)
+	}
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Fatalf("after a failed trial state = %s, want open", state)
+	}
+
+	// A successful trial closes it
+	clock.advance(31 * time.Second)
+	fake.FailWith(0)
+	if _, err := client.GetKnowledgeContext(ctx, "fractions", 0.5); err != nil {
+		t.Fatalf("trial: %v", err)
+	}
+	if state := client.CircuitState(); state != CircuitClosed {
+		t.Fatalf("after a good trial state = %s, want closed", state)
+	}
+}
+
+func TestCHISGClientCancellationReleasesTrial(t *testing.T) {
+	client, fake, clock := startClient(t, testConfig())
+
+	fake.FailWith(http.StatusInternalServerError)
+	for i := 0; i < 2; i++ {
+		client.IdentifyPrerequisites(context.Background(), "fractions")
+	}
+	clock.advance(31 * time.Second)
+
+	// The caller gives up during the half-open trial
+	fake.FailWith(0)
+	fake.SetDelay(time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := client.IdentifyPrerequisites(ctx, "fractions"); err == nil {
+		t.Fatal("cancelled call succeeded")
+	}
+	if state := client.CircuitState(); state != CircuitHalfOpen {
+		t.Fatalf("after a cancelled trial state = %s, want half_open", state)
+	}
+
+	// The slot was released, so the next call is the trial
+	fake.SetDelay(0)
+	prereqs, err := client.IdentifyPrerequisites(context.Background(), "fractions")
+	if err != nil {
+		t.Fatalf("next trial: %v", err)
+	}
+	if len(prereqs) != 1 || prereqs[0] != "division" {
+		t.Errorf("prerequisites = %v, want [division]", prereqs)
+	}
+	if state := client.CircuitState(); state != CircuitClosed {
+		t.Errorf("state = %s, want closed", state)
+	}
+}
+
+func TestCHISGClientCancellationIsNotAFailure(t *testing.T) {
+	cfg := testConfig()
+	cfg.FailureThreshold = 1
+	client, fake, _ := startClient(t, cfg)
+	fake.SetDelay(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetKnowledgeContext(ctx, "fractions", 0.5); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("err = %v, want ErrTimeout", err)
+	}
+	if state := client.CircuitState(); state != CircuitClosed {
+		t.Errorf("caller's deadline opened the circuit: state = %s", state)
+	}
+}
+
+func TestCHISGClientCache(t *testing.T) {
+	cfg := testConfig()
+	cfg.CacheTTL = time.Minute
+	client, fake, clock := startClient(t, cfg)
+	ctx := context.Background()
+
+	call := func(topic string, level float64) {
+		t.Helper()
+		if _, err := client.GetKnowledgeContext(ctx, topic, level); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	call("fractions", 0.5)
+	call(" Fractions ", 0.501) // Same topic once normalised, same level bucket
+	if got := fake.Requests(); got != 1 {
+		t.Fatalf("requests = %d, want 1: topic and level should share an entry", got)
+	}
+
+	call("fractions", 0.6)
+	if got := fake.Requests(); got != 2 {
+		t.Fatalf("requests = %d, want 2: a different level bucket needs its own answer", got)
+	}
+
+	clock.advance(59 * time.Second)
+	call("fractions", 0.5)
+	if got := fake.Requests(); got != 2 {
+		t.Fatalf("requests = %d, want 2 before the TTL", got)
+	}
+
+	clock.advance(time.Second)
+	call("fractions", 0.5)
+	if got := fake.Requests(); got != 3 {
+		t.Fatalf("requests = %d, want 3 once the entry expired", got)
+	}
+}
+
+func TestCHISGClientTypedErrors(t *testing.T) {
+	cases := []struct {
+		status int
+		kind   error
+	}{
+		{http.StatusNotFound, ErrTopicNotFound},
+		{http.StatusBadRequest, ErrRejected},
+		{http.StatusUnauthorized, ErrRejected},
+		{http.StatusTooManyRequests, ErrUnavailable},
+		{http.StatusInternalServerError, ErrUnavailable},
+		{http.StatusBadGateway, ErrUnavailable},
+	}
+
+	for _, tc := range cases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			client, fake, _ := startClient(t, testConfig())
+			fake.FailWith(tc.status)
+
+			_, err := client.GetKnowledgeContext(context.Background(), "fractions", 0.5)
+			if !errors.Is(err, tc.kind) {
+				t.Fatalf("err = %v, want %v", err, tc.kind)
+			}
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.status {
+				t.Fatalf("err = %#v, want *Error with status %d", err, tc.status)
+			}
+		})
+	}
+
+	// An unknown topic is an answer, not an outage: the breaker stays closed
+	cfg := testConfig()
+	cfg.FailureThreshold = 1
+	client, _, _ := startClient(t, cfg)
+	if _, err := client.GetKnowledgeContext(context.Background(), "astrophysics", 0.5); !errors.Is(err, ErrTopicNotFound) {
+		t.Fatalf("unknown topic: err = %v, want ErrTopicNotFound", err)
+	}
+	if state := client.CircuitState(); state != CircuitClosed {
+		t.Errorf("unknown topic opened the circuit: state = %s", state)
+	}
+}
+
+func TestCHISGClientInvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>not json</html>"))
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.BaseURL = server.URL
+	_, err := NewCHISGClient(cfg).GetKnowledgeContext(context.Background(), "fractions", 0.5)
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("err = %v, want ErrInvalidResponse", err)
+	}
+}
@@ -0,0 +1,229 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FakeConcept is one node of a scripted knowledge graph
+type FakeConcept struct {
+	Name          string
+	Description   string
+	Difficulty    float64  // 0-1; a prerequisite above the student's level is a gap
+	Prerequisites []string // Concepts this one builds on directly
+	Resources     []string
+}
+
+// FakeServer is an in-process stand-in for the CHISG API
+// It answers analyze and prerequisites requests from a scripted graph, so
+// the knowledge stage and the client's timeouts, breaker and cache can be
+// exercised without a real CHISG
+type FakeServer struct {
+	mu       sync.RWMutex
+	concepts map[string]FakeConcept // Keyed by lower-case name
+
+	delay    atomic.Int64 // Nanoseconds added before every reply
+	failWith atomic.Int64 // Non-zero: answer every request with this status
+	requests atomic.Int64
+}
+
+// NewFakeServer creates a fake serving the given graph
+func NewFakeServer(concepts ...FakeConcept) *FakeServer {
+	f := &FakeServer{concepts: make(map[string]FakeConcept)}
+	f.Script(concepts...)
+	return f
+}
+
+// StartFakeServer serves a fake on a local httptest server; Close it when done
+// Point a client at it with CHISGConfig{BaseURL: server.URL}
+func StartFakeServer(concepts ...FakeConcept) (*FakeServer, *httptest.Server) {
+	fake := NewFakeServer(concepts...)
+	return fake, httptest.NewServer(fake)
+}
+
+// Script adds or replaces concepts in the graph
+func (f *FakeServer) Script(concepts ...FakeConcept) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, concept := range concepts {
+		f.concepts[strings.ToLower(concept.Name)] = concept
+	}
+}
+
+// SetDelay holds every reply back, to exercise timeouts
+func (f *FakeServer) SetDelay(d time.Duration) {
+	f.delay.Store(int64(d))
+}
+
+// FailWith answers every request with status; zero restores normal replies
+func (f *FakeServer) FailWith(status int) {
+	f.failWith.Store(int64(status))
+}
+
+// Requests is how many requests the fake has received
+func (f *FakeServer) Requests() int64 {
+	return f.requests.Load()
+}
+
+// ServeHTTP answers POST /api/knowledge/analyze and /api/knowledge/prerequisites
+func (f *FakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+	f.requests.Add(1)
+
+	// Read the body first so the server notices a client that hangs up mid-delay
+	var req struct {
+		Topic        string  `json:"topic"`
+		StudentLevel float64 `json:"student_level"`
+	}
+	decodeErr := json.NewDecoder(r.Body).Decode(&req)
+
+	if d := time.Duration(f.delay.Load()); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if status := int(f.failWith.Load()); status != 0 {
+		http.Error(w, "fake failure", status)
+		return
+	}
+
+	if decodeErr != nil || req.Topic == "" {
+		http.Error(w, "topic is required", http.StatusBadRequest)
+		return
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	concept, ok := f.concepts[strings.ToLower(req.Topic)]
+	if !ok {
+		http.Error(w, "unknown topic", http.StatusNotFound)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/api/knowledge/analyze":
+		writeFakeJSON(w, f.analyzeLocked(concept, req.StudentLevel))
+	case "/api/knowledge/prerequisites":
+		writeFakeJSON(w, map[string][]string{"prerequisites": nonNil(concept.Prerequisites)})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// analyzeLocked walks everything the concept builds on, foundations first
+// Prerequisites harder than the student's level are gaps, and the
+// suggested path covers those gaps before the topic itself
+func (f *FakeServer) analyzeLocked(concept FakeConcept, studentLevel float64) KnowledgeContext {
+	knowledge := KnowledgeContext{
+		Topic:            concept.Name,
+		SemanticLinks:    []SemanticLink{},
+		PrerequisiteGaps: []string{},
+		SuggestedPath: LearningPath{
+			Nodes:         []LearningNode{},
+			Prerequisites: nonNil(concept.Prerequisites),
+			Difficulty:    concept.Difficulty,
+		},
+	}
+
+	for _, prereq := range f.foundationsLocked(concept) {
+		for _, next := range prereq.Prerequisites {
+			knowledge.SemanticLinks = append(knowledge.SemanticLinks, SemanticLink{
+				FromConcept:  next,
+				ToConcept:    prereq.Name,
+				Relationship: "prerequisite_of",
+				Strength:     1,
+			})
+		}
+		if prereq.Name == concept.Name || prereq.Difficulty <= studentLevel {
+			continue
+		}
+		knowledge.PrerequisiteGaps = append(knowledge.PrerequisiteGaps, prereq.Name)
+		knowledge.SuggestedPath.Nodes = append(knowledge.SuggestedPath.Nodes, fakeNode(prereq))
+	}
+	knowledge.SuggestedPath.Nodes = append(knowledge.SuggestedPath.Nodes, fakeNode(concept))
+	return knowledge
+}
+
+// foundationsLocked orders the concept's transitive prerequisites depth-first,
+// ending with the concept; unknown names are kept as bare concepts
+func (f *FakeServer) foundationsLocked(concept FakeConcept) []FakeConcept {
+	order := []FakeConcept{}
+	seen := map[string]bool{}
+	var visit func(c FakeConcept)
+	visit = func(c FakeConcept) {
+		key := strings.ToLower(c.Name)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		for _, name := range c.Prerequisites {
+			prereq, ok := f.concepts[strings.ToLower(name)]
+			if !ok {
+				prereq = FakeConcept{Name: name}
+			}
+			visit(prereq)
+		}
+		order = append(order, c)
+	}
+	visit(concept)
+	return order
+}
+
+// DefaultFakeGraph is a small maths and science graph covering the topics
+// the coach recognises
+func DefaultFakeGraph() []FakeConcept {
+	return []FakeConcept{
+		{Name: "number bonds", Description: "Pairs of numbers that make 10 and 100", Difficulty: 0.1},
+		{Name: "times tables", Description: "Multiplication facts up to 12 × 12", Difficulty: 0.2,
+			Prerequisites: []string{"number bonds"}},
+		{Name: "division", Description: "Sharing and grouping", Difficulty: 0.3,
+			Prerequisites: []string{"times tables"}},
+		{Name: "fractions", Description: "Parts of a whole", Difficulty: 0.4,
+			Prerequisites: []string{"division"}},
+		{Name: "negative numbers", Description: "Numbers below zero", Difficulty: 0.4,
+			Prerequisites: []string{"number bonds"}},
+		{Name: "algebra", Description: "Using letters for unknown numbers", Difficulty: 0.55,
+			Prerequisites: []string{"fractions", "negative numbers"}},
+		{Name: "expanding brackets", Description: "Multiplying out brackets", Difficulty: 0.6,
+			Prerequisites: []string{"algebra"}},
+		{Name: "quadratic equations", Description: "Solving ax² + bx + c = 0", Difficulty: 0.75,
+			Prerequisites: []string{"expanding brackets"}, Resources: []string{"factorising worksheet"}},
+		{Name: "paragraphs", Description: "Grouping sentences around one idea", Difficulty: 0.3},
+		{Name: "essay writing", Description: "Building an argument across paragraphs", Difficulty: 0.6,
+			Prerequisites: []string{"paragraphs"}},
+		{Name: "cell biology", Description: "What cells are made of and do", Difficulty: 0.5},
+		{Name: "DNA structure", Description: "The double helix and base pairs", Difficulty: 0.7,
+			Prerequisites: []string{"cell biology"}},
+	}
+}
+
+func fakeNode(concept FakeConcept) LearningNode {
+	return LearningNode{
+		Concept:     concept.Name,
+		Description: concept.Description,
+		Resources:   nonNil(concept.Resources),
+	}
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func writeFakeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,144 @@
+package mastery
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// Config tunes the Elo-style estimator
+// Levels and question difficulties share one 0-1 scale: a student whose
+// level equals a question's difficulty is expected to manage it half the time
+type Config struct {
+	InitialLevel float64       // Level assumed for a topic with no history
+	Spread       float64       // Logistic scale; smaller makes expectations sharper
+	InitialK     float64       // Largest step, used while a topic is new
+	MinK         float64       // Smallest step, reached as observations build up
+	KDecay       float64       // Step multiplier per observation
+	StaleAfter   time.Duration // Unseen this long, a topic is re-estimated at InitialK
+	Stretch      float64       // Questions are pitched this far below the level
+	MaxTopics    int           // Least recently seen topics are dropped beyond this
+}
+
+// DefaultConfig starts everyone mid-scale and settles after about ten answers
+func DefaultConfig() Config {
+	return Config{
+		InitialLevel: 0.5,
+		Spread:       0.15,
+		InitialK:     0.12,
+		MinK:         0.03,
+		KDecay:       0.85,
+		StaleAfter:   30 * 24 * time.Hour,
+		Stretch:      0.1,
+		MaxTopics:    100,
+	}
+}
+
+// Level bounds keep one bad or brilliant answer from pinning a topic
+const (
+	minLevel = 0.05
+	maxLevel = 0.95
+)
+
+// Observation is one answer on a topic
+type Observation struct {
+	Topic      string
+	Difficulty float64   // Difficulty of the question being answered, 0-1
+	Score      float64   // 1 engaged attempt, 0 struggling; partial credit in between
+	At         time.Time // When the answer was given
+}
+
+// Estimator rates per-topic mastery from answer quality against question difficulty
+type Estimator struct {
+	cfg Config
+}
+
+// NewEstimator creates an estimator
+func NewEstimator(cfg Config) *Estimator {
+	return &Estimator{cfg: cfg}
+}
+
+// InitialLevel is the level assumed before any evidence
+func (e *Estimator) InitialLevel() float64 {
+	return e.cfg.InitialLevel
+}
+
+// Level returns the student's level for a topic, and whether it rests on
+// any answers; unseen topics get InitialLevel
+func (e *Estimator) Level(profile etp.MasteryProfile, topic string) (float64, bool) {
+	if state, ok := profile.Topics[TopicKey(topic)]; ok && state.Observations > 0 {
+		return state.Level, true
+	}
+	return e.cfg.InitialLevel, false
+}
+
+// Expected is the chance a student at level manages a question of difficulty
+func (e *Estimator) Expected(level, difficulty float64) float64 {
+	return 1 / (1 + math.Exp(-(level-difficulty)/e.cfg.Spread))
+}
+
+// TargetDifficulty pitches the next question a little below the level, so
+// the student is likely to succeed and the challenge rises as they do
+func (e *Estimator) TargetDifficulty(level float64) float64 {
+	return math.Max(0.1, math.Min(level-e.cfg.Stretch, 0.9))
+}
+
+// Record folds an answer into the profile and returns the topic's new state
+// The level moves by K × (score − expected): surprising answers move it
+// most, and K shrinks as evidence accumulates
+func (e *Estimator) Record(profile *etp.MasteryProfile, obs Observation) etp.TopicMastery {
+	if profile.Topics == nil {
+		profile.Topics = make(map[string]etp.TopicMastery)
+	}
+
+	key := TopicKey(obs.Topic)
+	state, ok := profile.Topics[key]
+	if !ok {
+		state = etp.TopicMastery{Topic: obs.Topic, Level: e.cfg.InitialLevel}
+	}
+
+	score := math.Max(0, math.Min(obs.Score, 1))
+	state.Level += e.stepSize(state, obs.At) * (score - e.Expected(state.Level, obs.Difficulty))
+	state.Level = math.Max(minLevel, math.Min(state.Level, maxLevel))
+	state.Observations++
+	if score >= 0.5 {
+		state.Successes++
+	}
+	state.LastSeen = obs.At
+
+	profile.Topics[key] = state
+	e.prune(profile)
+	return state
+}
+
+// stepSize is K for the next update
+func (e *Estimator) stepSize(state etp.TopicMastery, at time.Time) float64 {
+	if state.Observations == 0 {
+		return e.cfg.InitialK
+	}
+	if e.cfg.StaleAfter > 0 && at.Sub(state.LastSeen) > e.cfg.StaleAfter {
+		return e.cfg.InitialK // Long gap: the old estimate may no longer hold
+	}
+	return math.Max(e.cfg.MinK, e.cfg.InitialK*math.Pow(e.cfg.KDecay, float64(state.Observations)))
+}
+
+// prune drops the least recently seen topics beyond MaxTopics
+func (e *Estimator) prune(profile *etp.MasteryProfile) {
+	for e.cfg.MaxTopics > 0 && len(profile.Topics) > e.cfg.MaxTopics {
+		oldestKey := ""
+		var oldest time.Time
+		for key, state := range profile.Topics {
+			if oldestKey == "" || state.LastSeen.Before(oldest) {
+				oldestKey, oldest = key, state.LastSeen
+			}
+		}
+		delete(profile.Topics, oldestKey)
+	}
+}
+
+// TopicKey normalises a topic name for lookup
+func TopicKey(topic string) string {
+	return strings.ToLower(strings.TrimSpace(topic))
+}
@@ -0,0 +1,158 @@
+package nlp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Exemplar is a labelled example text, e.g. a barrier's verbal manifestation
+type Exemplar struct {
+	ID    string   `json:"id"`
+	Text  string   `json:"text"`
+	Label string   `json:"label"`          // What the text is an example of, e.g. a barrier ID
+	Tags  []string `json:"tags,omitempty"` // Secondary labels it carries, e.g. ETP names
+}
+
+// Score is the classifier's confidence in one label or tag
+type Score struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"` // 0-1
+	Similarity float64 `json:"similarity"` // Of the closest exemplar
+	Nearest    string  `json:"nearest"`    // That exemplar's text
+	Label      string  `json:"label"`      // And its label; for tags, the label the tag came with
+}
+
+// Classification is what the nearest exemplars say about a text
+type Classification struct {
+	Labels     []Score     `json:"labels"` // Most confident first
+	Tags       []Score     `json:"tags"`
+	Neighbours []Neighbour `json:"neighbours"`
+}
+
+// Label finds the score for one label
+func (c *Classification) Label(name string) (Score, bool) {
+	for _, score := range c.Labels {
+		if score.Name == name {
+			return score, true
+		}
+	}
+	return Score{}, false
+}
+
+// Classifier labels text by its k nearest exemplars
+type Classifier struct {
+	embedder Embedder
+	index    VectorIndex
+	config   Config
+}
+
+// NewClassifier combines an embedder and a vector index
+func NewClassifier(embedder Embedder, index VectorIndex, cfg Config) *Classifier {
+	return &Classifier{embedder: embedder, index: index, config: cfg}
+}
+
+// Name describes the embedder and index, e.g. "hashing-tfidf/memory"
+func (c *Classifier) Name() string {
+	return c.embedder.Name() + "/" + c.index.Name()
+}
+
+// Index fits the embedder to the exemplars, then stores their vectors
+func (c *Classifier) Index(ctx context.Context, exemplars []Exemplar) error {
+	if f, ok := c.embedder.(Fitter); ok {
+		corpus := make([]string, len(exemplars))
+		for i, exemplar := range exemplars {
+			corpus[i] = exemplar.Text
+		}
+		f.Fit(corpus)
+	}
+
+	items := make([]Item, 0, len(exemplars))
+	for _, exemplar := range exemplars {
+		vector, err := c.embedder.Embed(ctx, exemplar.Text)
+		if err != nil {
+			return fmt.Errorf("failed to embed exemplar %s: %w", exemplar.ID, err)
+		}
+		items = append(items, Item{Exemplar: exemplar, Vector: vector})
+	}
+	if err := c.index.Add(ctx, items); err != nil {
+		return fmt.Errorf("failed to index exemplars in %s: %w", c.index.Name(), err)
+	}
+	return nil
+}
+
+// Classify scores every label and tag among the text's nearest exemplars
+// A label's confidence follows its closest exemplar: nothing below
+// MinSimilarity, rising to 1 at FullSimilarity
+func (c *Classifier) Classify(ctx context.Context, text string) (*Classification, error) {
+	vector, err := c.embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed message: %w", err)
+	}
+	neighbours, err := c.index.Search(ctx, vector, c.config.K)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", c.index.Name(), err)
+	}
+
+	result := &Classification{Labels: []Score{}, Tags: []Score{}, Neighbours: []Neighbour{}}
+	labels := map[string]Score{}
+	tags := map[string]Score{}
+	for _, n := range neighbours {
+		if n.Similarity < c.config.MinSimilarity {
+			continue
+		}
+		result.Neighbours = append(result.Neighbours, n)
+		c.keepBest(labels, n.Label, n)
+		for _, tag := range n.Tags {
+			c.keepBest(tags, tag, n)
+		}
+	}
+	result.Labels = sortScores(labels)
+	result.Tags = sortScores(tags)
+	return result, nil
+}
+
+// keepBest records n as the evidence for name unless a closer exemplar already is
+func (c *Classifier) keepBest(scores map[string]Score, name string, n Neighbour) {
+	if existing, ok := scores[name]; ok && existing.Similarity >= n.Similarity {
+		return
+	}
+	scores[name] = Score{
+		Name:       name,
+		Confidence: c.confidence(n.Similarity),
+		Similarity: round2(n.Similarity),
+		Nearest:    n.Text,
+		Label:      n.Label,
+	}
+}
+
+// confidence ramps similarity linearly between MinSimilarity and FullSimilarity
+func (c *Classifier) confidence(similarity float64) float64 {
+	span := c.config.FullSimilarity - c.config.MinSimilarity
+	if span <= 0 {
+		return 1
+	}
+	return round2(math.Max(0, math.Min(1, (similarity-c.config.MinSimilarity)/span)))
+}
+
+func sortScores(scores map[string]Score) []Score {
+	sorted := make([]Score, 0, len(scores))
+	for _, score := range scores {
+		sorted = append(sorted, score)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Confidence != sorted[j].Confidence {
+			return sorted[i].Confidence > sorted[j].Confidence
+		}
+		if sorted[i].Similarity != sorted[j].Similarity {
+			return sorted[i].Similarity > sorted[j].Similarity
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
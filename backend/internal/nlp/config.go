@@ -0,0 +1,100 @@
+package nlp
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Vector backends selectable with EMBEDDING_BACKEND
+const (
+	BackendOff      = "off"      // Regex detection only
+	BackendLocal    = "local"    // In-process index
+	BackendWeaviate = "weaviate" // Weaviate REST API
+)
+
+// Config selects the vector backend and tunes nearest-neighbour matching
+type Config struct {
+	Backend        string
+	Dimensions     int     // Hashed embedding size
+	K              int     // Neighbours consulted per message
+	MinSimilarity  float64 // Closer exemplars than this count as evidence
+	FullSimilarity float64 // At or above this a label is fully confident
+
+	WeaviateURL    string
+	WeaviateClass  string
+	WeaviateAPIKey string
+	Timeout        time.Duration // Per Weaviate request
+}
+
+// DefaultConfig returns the local backend
+func DefaultConfig() Config {
+	return Config{
+		Backend:        BackendLocal,
+		Dimensions:     1024,
+		K:              5,
+		MinSimilarity:  0.3,
+		FullSimilarity: 0.6,
+		WeaviateURL:    "http://localhost:8081",
+		WeaviateClass:  "BarrierExemplar",
+		Timeout:        500 * time.Millisecond,
+	}
+}
+
+// ConfigFromEnv overlays EMBEDDING_* and WEAVIATE_* settings on the defaults
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	if v := os.Getenv("EMBEDDING_BACKEND"); v != "" {
+		cfg.Backend = strings.ToLower(v)
+	}
+	if v, err := strconv.Atoi(os.Getenv("EMBEDDING_DIMENSIONS")); err == nil && v > 0 {
+		cfg.Dimensions = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("EMBEDDING_NEIGHBOURS")); err == nil && v > 0 {
+		cfg.K = v
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("EMBEDDING_MIN_SIMILARITY"), 64); err == nil && v >= 0 && v < 1 {
+		cfg.MinSimilarity = v
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("EMBEDDING_FULL_SIMILARITY"), 64); err == nil && v > 0 && v <= 1 {
+		cfg.FullSimilarity = v
+	}
+	if v := os.Getenv("WEAVIATE_URL"); v != "" {
+		cfg.WeaviateURL = v
+	}
+	if v := os.Getenv("WEAVIATE_CLASS"); v != "" {
+		cfg.WeaviateClass = v
+	}
+	cfg.WeaviateAPIKey = os.Getenv("WEAVIATE_API_KEY")
+	if ms, err := strconv.Atoi(os.Getenv("WEAVIATE_TIMEOUT_MS")); err == nil && ms > 0 {
+		cfg.Timeout = time.Duration(ms) * time.Millisecond
+	}
+	return cfg
+}
+
+// New builds the configured classifier; nil when the backend is off
+// Exemplars still have to be indexed before it can classify
+func New(cfg Config) (*Classifier, error) {
+	if cfg.MinSimilarity >= cfg.FullSimilarity {
+		return nil, fmt.Errorf("EMBEDDING_MIN_SIMILARITY (%.2f) must be below EMBEDDING_FULL_SIMILARITY (%.2f)",
+			cfg.MinSimilarity, cfg.FullSimilarity)
+	}
+
+	embedder := NewHashingEmbedder(cfg.Dimensions)
+	switch cfg.Backend {
+	case BackendOff:
+		return nil, nil
+	case "", BackendLocal:
+		return NewClassifier(embedder, NewMemoryIndex(), cfg), nil
+	case BackendWeaviate:
+		if cfg.WeaviateURL == "" || cfg.WeaviateClass == "" {
+			return nil, fmt.Errorf("weaviate backend needs WEAVIATE_URL and WEAVIATE_CLASS")
+		}
+		index := NewWeaviateIndex(cfg.WeaviateURL, cfg.WeaviateClass, cfg.WeaviateAPIKey, cfg.Timeout)
+		return NewClassifier(embedder, index, cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown EMBEDDING_BACKEND %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,54 @@
+package nlp
+
+import (
+	"context"
+	"math"
+)
+
+// Vector is a dense text embedding
+type Vector []float32
+
+// Embedder turns text into vectors that are close when the texts are
+type Embedder interface {
+	Name() string
+	Dimensions() int
+	Embed(ctx context.Context, text string) (Vector, error)
+}
+
+// Fitter is an embedder that learns weights from the texts it will compare,
+// e.g. document frequencies; the classifier fits it before indexing
+type Fitter interface {
+	Fit(corpus []string)
+}
+
+// Cosine is the cosine similarity of two vectors, 0 when either is empty
+func Cosine(a, b Vector) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// normalize scales v to unit length in place
+func normalize(v Vector) {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+}
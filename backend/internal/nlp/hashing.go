@@ -0,0 +1,135 @@
+package nlp
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Feature weights: whole words and word pairs carry the meaning, character
+// trigrams let "starting" land near "start"
+const (
+	wordWeight    = 1.0
+	bigramWeight  = 1.0
+	trigramWeight = 0.5
+)
+
+// HashingEmbedder is a local, dependency-free TF-IDF embedder
+// Word, word-pair and character-trigram features are hashed into a fixed
+// number of signed buckets, so no vocabulary has to be stored
+type HashingEmbedder struct {
+	dimensions int
+
+	mu        sync.RWMutex
+	idf       map[string]float64 // Feature → inverse document frequency, from Fit
+	unseenIDF float64            // For features the corpus never used
+}
+
+// NewHashingEmbedder creates an embedder with the given number of buckets
+// Until Fit is called every feature has the same weight
+func NewHashingEmbedder(dimensions int) *HashingEmbedder {
+	if dimensions <= 0 {
+		dimensions = DefaultConfig().Dimensions
+	}
+	return &HashingEmbedder{dimensions: dimensions, idf: map[string]float64{}, unseenIDF: 1}
+}
+
+// Name identifies the embedder in logs
+func (h *HashingEmbedder) Name() string {
+	return "hashing-tfidf"
+}
+
+// Dimensions is the vector length
+func (h *HashingEmbedder) Dimensions() int {
+	return h.dimensions
+}
+
+// Fit learns inverse document frequencies, so features every text shares
+// ("this", "is") count for less than the distinctive ones
+func (h *HashingEmbedder) Fit(corpus []string) {
+	df := map[string]int{}
+	for _, text := range corpus {
+		for feature := range features(text) {
+			df[feature]++
+		}
+	}
+
+	n := float64(len(corpus))
+	idf := make(map[string]float64, len(df))
+	for feature, count := range df {
+		idf[feature] = math.Log((1+n)/(1+float64(count))) + 1
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.idf = idf
+	h.unseenIDF = math.Log(1+n) + 1
+}
+
+// Embed hashes the text's TF-IDF weighted features into a unit vector
+func (h *HashingEmbedder) Embed(ctx context.Context, text string) (Vector, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	v := make(Vector, h.dimensions)
+	for feature, tf := range features(text) {
+		idf, ok := h.idf[feature]
+		if !ok {
+			idf = h.unseenIDF
+		}
+
+		hash := fnv.New64a()
+		hash.Write([]byte(feature))
+		sum := hash.Sum64()
+		sign := float32(1)
+		if sum>>63 == 1 {
+			sign = -1
+		}
+		v[sum%uint64(h.dimensions)] += sign * float32((1+math.Log(tf.count))*tf.weight*idf)
+	}
+	normalize(v)
+	return v, nil
+}
+
+// featureCount is how often a feature occurs and how much each occurrence weighs
+type featureCount struct {
+	count  float64
+	weight float64
+}
+
+// features extracts the weighted features of a text
+func features(text string) map[string]featureCount {
+	words := words(text)
+	found := map[string]featureCount{}
+	add := func(feature string, weight float64) {
+		f := found[feature]
+		f.count++
+		f.weight = weight
+		found[feature] = f
+	}
+
+	for i, word := range words {
+		add("w:"+word, wordWeight)
+		if i > 0 {
+			add("b:"+words[i-1]+" "+word, bigramWeight)
+		}
+		padded := []rune(" " + word + " ")
+		for j := 0; j+3 <= len(padded); j++ {
+			add("c:"+string(padded[j:j+3]), trigramWeight)
+		}
+	}
+	return found
+}
+
+// words lower-cases and splits on anything but letters and digits
+// Apostrophes are dropped, so "don't" and "dont" are the same word
+func words(text string) []string {
+	text = strings.ToLower(text)
+	text = strings.NewReplacer("'", "", "’", "").Replace(text)
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,79 @@
+package nlp
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Item is one stored exemplar and its vector
+type Item struct {
+	Exemplar
+	Vector Vector `json:"-"`
+}
+
+// Neighbour is an item found near a query
+type Neighbour struct {
+	Exemplar
+	Similarity float64 `json:"similarity"` // Cosine, 1 is identical
+}
+
+// VectorIndex stores exemplar vectors and finds the nearest to a query
+// Add replaces items with the same ID, so re-indexing is safe
+type VectorIndex interface {
+	Name() string
+	Add(ctx context.Context, items []Item) error
+	Search(ctx context.Context, query Vector, k int) ([]Neighbour, error)
+}
+
+// MemoryIndex is an exact, in-process index; fine for a few thousand items
+type MemoryIndex struct {
+	mu    sync.RWMutex
+	items map[string]Item
+}
+
+// NewMemoryIndex creates an empty in-process index
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{items: map[string]Item{}}
+}
+
+// Name identifies the index in logs
+func (m *MemoryIndex) Name() string {
+	return "memory"
+}
+
+// Add stores items, replacing any with the same ID
+func (m *MemoryIndex) Add(ctx context.Context, items []Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, item := range items {
+		m.items[item.ID] = item
+	}
+	return nil
+}
+
+// Search compares the query with every item
+func (m *MemoryIndex) Search(ctx context.Context, query Vector, k int) ([]Neighbour, error) {
+	m.mu.RLock()
+	neighbours := make([]Neighbour, 0, len(m.items))
+	for _, item := range m.items {
+		neighbours = append(neighbours, Neighbour{Exemplar: item.Exemplar, Similarity: Cosine(query, item.Vector)})
+	}
+	m.mu.RUnlock()
+
+	sortNeighbours(neighbours)
+	if k > 0 && len(neighbours) > k {
+		neighbours = neighbours[:k]
+	}
+	return neighbours, nil
+}
+
+// sortNeighbours orders by similarity, then ID so ties are repeatable
+func sortNeighbours(neighbours []Neighbour) {
+	sort.Slice(neighbours, func(i, j int) bool {
+		if neighbours[i].Similarity != neighbours[j].Similarity {
+			return neighbours[i].Similarity > neighbours[j].Similarity
+		}
+		return neighbours[i].ID < neighbours[j].ID
+	})
+}
@@ -0,0 +1,254 @@
+package nlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WeaviateIndex keeps exemplar vectors in a Weaviate class
+// Vectors are computed locally and sent with each object, so the class
+// uses no Weaviate vectorizer module
+type WeaviateIndex struct {
+	baseURL string
+	class   string
+	apiKey  string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewWeaviateIndex connects to a Weaviate REST API, e.g. http://localhost:8080
+func NewWeaviateIndex(baseURL, class, apiKey string, timeout time.Duration) *WeaviateIndex {
+	return &WeaviateIndex{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		class:   class,
+		apiKey:  apiKey,
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+// Name identifies the index in logs
+func (w *WeaviateIndex) Name() string {
+	return "weaviate"
+}
+
+// weaviateObject is one object in a batch import
+type weaviateObject struct {
+	Class      string             `json:"class"`
+	ID         string             `json:"id"`
+	Properties weaviateProperties `json:"properties"`
+	Vector     Vector             `json:"vector"`
+}
+
+type weaviateProperties struct {
+	ExemplarID string   `json:"exemplarId"`
+	Text       string   `json:"text"`
+	Label      string   `json:"label"`
+	Tags       []string `json:"tags"`
+}
+
+// weaviateBatchResult reports per-object failures in a batch import
+type weaviateBatchResult struct {
+	ID     string `json:"id"`
+	Result struct {
+		Errors *struct {
+			Error []struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"errors"`
+	} `json:"result"`
+}
+
+// Add creates the class if needed, then upserts the items in one batch
+// Object IDs derive from exemplar IDs, so re-indexing replaces, not duplicates
+func (w *WeaviateIndex) Add(ctx context.Context, items []Item) error {
+	if err := w.ensureClass(ctx); err != nil {
+		return err
+	}
+
+	objects := make([]weaviateObject, len(items))
+	for i, item := range items {
+		tags := item.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		objects[i] = weaviateObject{
+			Class: w.class,
+			ID:    objectID(w.class, item.ID),
+			Properties: weaviateProperties{
+				ExemplarID: item.ID,
+				Text:       item.Text,
+				Label:      item.Label,
+				Tags:       tags,
+			},
+			Vector: item.Vector,
+		}
+	}
+
+	var results []weaviateBatchResult
+	if err := w.do(ctx, http.MethodPost, "/v1/batch/objects", map[string]any{"objects": objects}, &results); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Result.Errors != nil && len(result.Result.Errors.Error) > 0 {
+			return fmt.Errorf("weaviate rejected object %s: %s", result.ID, result.Result.Errors.Error[0].Message)
+		}
+	}
+	return nil
+}
+
+// Search runs a nearVector query; Weaviate reports cosine distance
+func (w *WeaviateIndex) Search(ctx context.Context, query Vector, k int) ([]Neighbour, error) {
+	values := make([]string, len(query))
+	for i, x := range query {
+		values[i] = strconv.FormatFloat(float64(x), 'g', -1, 32)
+	}
+	graphql := fmt.Sprintf(
+		"{ Get { %s(nearVector: {vector: [%s]}, limit: %d) { exemplarId text label tags _additional { distance } } } }",
+		w.class, strings.Join(values, ","), k)
+
+	var response struct {
+		Data struct {
+			Get map[string][]struct {
+				weaviateProperties
+				Additional struct {
+					Distance float64 `json:"distance"`
+				} `json:"_additional"`
+			} `json:"Get"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := w.do(ctx, http.MethodPost, "/v1/graphql", map[string]string{"query": graphql}, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("weaviate query failed: %s", response.Errors[0].Message)
+	}
+
+	neighbours := []Neighbour{}
+	for _, object := range response.Data.Get[w.class] {
+		neighbours = append(neighbours, Neighbour{
+			Exemplar: Exemplar{
+				ID:    object.ExemplarID,
+				Text:  object.Text,
+				Label: object.Label,
+				Tags:  object.Tags,
+			},
+			Similarity: 1 - object.Additional.Distance,
+		})
+	}
+	sortNeighbours(neighbours)
+	return neighbours, nil
+}
+
+// ensureClass creates the exemplar class when Weaviate does not have it
+func (w *WeaviateIndex) ensureClass(ctx context.Context) error {
+	err := w.do(ctx, http.MethodGet, "/v1/schema/"+w.class, nil, nil)
+	if err == nil {
+		return nil
+	}
+	if !isNotFound(err) {
+		return err
+	}
+
+	property := func(name, dataType string) map[string]any {
+		return map[string]any{"name": name, "dataType": []string{dataType}}
+	}
+	class := map[string]any{
+		"class":             w.class,
+		"description":       "Labelled example messages for nearest-neighbour classification",
+		"vectorizer":        "none",
+		"vectorIndexConfig": map[string]any{"distance": "cosine"},
+		"properties": []map[string]any{
+			property("exemplarId", "text"),
+			property("text", "text"),
+			property("label", "text"),
+			property("tags", "text[]"),
+		},
+	}
+	if err := w.do(ctx, http.MethodPost, "/v1/schema", class, nil); err != nil {
+		return fmt.Errorf("failed to create weaviate class %s: %w", w.class, err)
+	}
+	return nil
+}
+
+// statusError is a non-2xx Weaviate response
+type statusError struct {
+	method, path string
+	status       int
+	body         string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("weaviate %s %s: status %d: %s", e.method, e.path, e.status, e.body)
+}
+
+func isNotFound(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.status == http.StatusNotFound
+}
+
+// do sends one request under the configured deadline and decodes the reply into out
+func (w *WeaviateIndex) do(ctx context.Context, method, path string, in, out any) error {
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if w.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.apiKey)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("weaviate %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{method: method, path: path, status: resp.StatusCode, body: strings.TrimSpace(string(data))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("weaviate %s %s: invalid response: %w", method, path, err)
+	}
+	return nil
+}
+
+// objectID derives a stable, name-based (version 5 style) UUID for an exemplar
+func objectID(class, id string) string {
+	sum := sha1.Sum([]byte(class + "/" + id))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,216 @@
+package nlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// nearVectorQuery matches the Get query WeaviateIndex.Search sends
+var nearVectorQuery = regexp.MustCompile(`Get \{ (\w+)\(nearVector: \{vector: \[([^\]]*)\]\}, limit: (\d+)\)`)
+
+// WeaviateStub is an in-process stand-in for the parts of the Weaviate REST
+// API that WeaviateIndex uses: class schema, batch import and nearVector
+// queries, with exact cosine search over what was imported
+type WeaviateStub struct {
+	mu      sync.RWMutex
+	classes map[string]bool
+	objects map[string]map[string]weaviateObject // Class → object ID → object
+
+	failWith atomic.Int64 // Non-zero: answer every request with this status
+	requests atomic.Int64
+}
+
+// NewWeaviateStub creates an empty stub
+func NewWeaviateStub() *WeaviateStub {
+	return &WeaviateStub{classes: map[string]bool{}, objects: map[string]map[string]weaviateObject{}}
+}
+
+// StartWeaviateStub serves a stub on a local httptest server; Close it when done
+// Point an index at it with NewWeaviateIndex(server.URL, ...)
+func StartWeaviateStub() (*WeaviateStub, *httptest.Server) {
+	stub := NewWeaviateStub()
+	return stub, httptest.NewServer(stub)
+}
+
+// FailWith answers every request with status; zero restores normal replies
+func (s *WeaviateStub) FailWith(status int) {
+	s.failWith.Store(int64(status))
+}
+
+// Requests is how many requests the stub has received
+func (s *WeaviateStub) Requests() int64 {
+	return s.requests.Load()
+}
+
+// Objects is how many objects a class holds
+func (s *WeaviateStub) Objects(class string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.objects[class])
+}
+
+// ServeHTTP answers GET/POST /v1/schema, POST /v1/batch/objects and POST /v1/graphql
+func (s *WeaviateStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	if status := int(s.failWith.Load()); status != 0 {
+		http.Error(w, "stub failure", status)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/schema/"):
+		s.getClass(w, strings.TrimPrefix(r.URL.Path, "/v1/schema/"))
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/schema":
+		s.createClass(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/batch/objects":
+		s.importObjects(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/graphql":
+		s.query(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *WeaviateStub) getClass(w http.ResponseWriter, class string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.classes[class] {
+		http.Error(w, "class not found", http.StatusNotFound)
+		return
+	}
+	writeStubJSON(w, map[string]string{"class": class, "vectorizer": "none"})
+}
+
+func (s *WeaviateStub) createClass(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Class string `json:"class"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Class == "" {
+		http.Error(w, "class is required", http.StatusUnprocessableEntity)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.classes[req.Class] {
+		http.Error(w, "class already exists", http.StatusUnprocessableEntity)
+		return
+	}
+	s.classes[req.Class] = true
+	s.objects[req.Class] = map[string]weaviateObject{}
+	writeStubJSON(w, req)
+}
+
+// importObjects stores each object, reporting per-object errors as Weaviate does
+func (s *WeaviateStub) importObjects(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Objects []weaviateObject `json:"objects"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid batch", http.StatusUnprocessableEntity)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]map[string]any, len(req.Objects))
+	for i, object := range req.Objects {
+		result := map[string]any{}
+		switch {
+		case !s.classes[object.Class]:
+			result["errors"] = stubErrors(fmt.Sprintf("class %q not found", object.Class))
+		case len(object.Vector) == 0:
+			result["errors"] = stubErrors("vector is required: class has no vectorizer")
+		default:
+			s.objects[object.Class][object.ID] = object
+		}
+		results[i] = map[string]any{"id": object.ID, "result": result}
+	}
+	writeStubJSON(w, results)
+}
+
+// query answers a nearVector Get with the closest objects by cosine distance
+func (s *WeaviateStub) query(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid query", http.StatusUnprocessableEntity)
+		return
+	}
+	parts := nearVectorQuery.FindStringSubmatch(req.Query)
+	if parts == nil {
+		writeStubJSON(w, map[string]any{"errors": queryErrors("stub only supports Get with nearVector and limit")})
+		return
+	}
+
+	class := parts[1]
+	query := Vector{}
+	for _, value := range strings.Split(parts[2], ",") {
+		x, err := strconv.ParseFloat(strings.TrimSpace(value), 32)
+		if err != nil {
+			writeStubJSON(w, map[string]any{"errors": queryErrors("invalid vector: " + err.Error())})
+			return
+		}
+		query = append(query, float32(x))
+	}
+	limit, _ := strconv.Atoi(parts[3])
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.classes[class] {
+		writeStubJSON(w, map[string]any{"errors": queryErrors(fmt.Sprintf("class %q not found", class))})
+		return
+	}
+
+	neighbours := []Neighbour{}
+	for _, object := range s.objects[class] {
+		neighbours = append(neighbours, Neighbour{
+			Exemplar: Exemplar{
+				ID:    object.Properties.ExemplarID,
+				Text:  object.Properties.Text,
+				Label: object.Properties.Label,
+				Tags:  object.Properties.Tags,
+			},
+			Similarity: Cosine(query, object.Vector),
+		})
+	}
+	sortNeighbours(neighbours)
+	if limit > 0 && len(neighbours) > limit {
+		neighbours = neighbours[:limit]
+	}
+
+	found := make([]map[string]any, len(neighbours))
+	for i, n := range neighbours {
+		found[i] = map[string]any{
+			"exemplarId":  n.ID,
+			"text":        n.Text,
+			"label":       n.Label,
+			"tags":        n.Tags,
+			"_additional": map[string]float64{"distance": 1 - n.Similarity},
+		}
+	}
+	writeStubJSON(w, map[string]any{"data": map[string]any{"Get": map[string]any{class: found}}})
+}
+
+// stubErrors is a batch object's error list
+func stubErrors(message string) map[string]any {
+	return map[string]any{"error": []map[string]string{{"message": message}}}
+}
+
+// queryErrors is a GraphQL error list
+func queryErrors(message string) []map[string]string {
+	return []map[string]string{{"message": message}}
+}
+
+func writeStubJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,189 @@
+package nlp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testExemplars = []Exemplar{
+	{ID: "motivation/0", Text: "Can I do this later?", Label: "lack_of_motivation", Tags: []string{"threat_avoidance"}},
+	{ID: "motivation/1", Text: "This is boring", Label: "lack_of_motivation"},
+	{ID: "confront/0", Text: "Why should I?", Label: "confrontational_showoff", Tags: []string{"control_need"}},
+	{ID: "confront/1", Text: "Make me", Label: "confrontational_showoff", Tags: []string{"control_need"}},
+	{ID: "easy/0", Text: "This is too easy", Label: "high_achiever_underengaged"},
+}
+
+// embedAll fits an embedder to the exemplars and returns their items
+func embedAll(t *testing.T, embedder *HashingEmbedder) []Item {
+	t.Helper()
+	corpus := make([]string, len(testExemplars))
+	for i, exemplar := range testExemplars {
+		corpus[i] = exemplar.Text
+	}
+	embedder.Fit(corpus)
+
+	items := make([]Item, len(testExemplars))
+	for i, exemplar := range testExemplars {
+		vector, err := embedder.Embed(context.Background(), exemplar.Text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		items[i] = Item{Exemplar: exemplar, Vector: vector}
+	}
+	return items
+}
+
+func TestWeaviateIndexMatchesMemoryIndex(t *testing.T) {
+	stub, server := StartWeaviateStub()
+	defer server.Close()
+	ctx := context.Background()
+
+	embedder := NewHashingEmbedder(256)
+	items := embedAll(t, embedder)
+	weaviate := NewWeaviateIndex(server.URL, "BarrierExemplar", "", time.Second)
+	memory := NewMemoryIndex()
+	for _, index := range []VectorIndex{weaviate, memory} {
+		if err := index.Add(ctx, items); err != nil {
+			t.Fatalf("%s: %v", index.Name(), err)
+		}
+	}
+	if got := stub.Objects("BarrierExemplar"); got != len(items) {
+		t.Fatalf("stub holds %d objects, want %d", got, len(items))
+	}
+
+	for _, text := range []string{"can I maybe do it later", "why should I even", "way too easy"} {
+		query, err := embedder.Embed(ctx, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := memory.Search(ctx, query, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := weaviate.Search(ctx, query, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%q: %d neighbours from Weaviate, %d from memory", text, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].ID != want[i].ID || got[i].Label != want[i].Label || got[i].Text != want[i].Text ||
+				math.Abs(got[i].Similarity-want[i].Similarity) > 1e-4 {
+				t.Errorf("%q neighbour %d: Weaviate %+v, memory %+v", text, i, got[i], want[i])
+			}
+		}
+		if len(got[0].Tags) != len(want[0].Tags) {
+			t.Errorf("%q: tags %v from Weaviate, %v from memory", text, got[0].Tags, want[0].Tags)
+		}
+	}
+}
+
+func TestWeaviateIndexReindexIsIdempotent(t *testing.T) {
+	stub, server := StartWeaviateStub()
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.Backend = BackendWeaviate
+	cfg.WeaviateURL = server.URL
+	classifier, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := classifier.Index(context.Background(), testExemplars); err != nil {
+			t.Fatalf("index %d: %v", i+1, err)
+		}
+	}
+	if got := stub.Objects(cfg.WeaviateClass); got != len(testExemplars) {
+		t.Fatalf("after re-indexing the stub holds %d objects, want %d", got, len(testExemplars))
+	}
+
+	classification, err := classifier.Classify(context.Background(), "can I maybe do this later on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score, ok := classification.Label("lack_of_motivation"); !ok || score.Confidence == 0 {
+		t.Errorf("labels = %+v, want lack_of_motivation", classification.Labels)
+	}
+}
+
+func TestWeaviateIndexSendsAPIKey(t *testing.T) {
+	stub := NewWeaviateStub()
+	var mu sync.Mutex
+	auth := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auth = append(auth, r.Header.Get("Authorization"))
+		mu.Unlock()
+		stub.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	index := NewWeaviateIndex(server.URL, "BarrierExemplar", "secret-key", time.Second)
+	if err := index.Add(context.Background(), embedAll(t, NewHashingEmbedder(64))); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(auth) == 0 {
+		t.Fatal("no requests reached the stub")
+	}
+	for i, header := range auth {
+		if header != "Bearer secret-key" {
+			t.Errorf("request %d: Authorization = %q, want bearer key", i+1, header)
+		}
+	}
+}
+
+func TestWeaviateIndexErrors(t *testing.T) {
+	stub, server := StartWeaviateStub()
+	defer server.Close()
+	ctx := context.Background()
+
+	index := NewWeaviateIndex(server.URL, "BarrierExemplar", "", time.Second)
+	items := embedAll(t, NewHashingEmbedder(64))
+	if err := index.Add(ctx, items); err != nil {
+		t.Fatal(err)
+	}
+
+	stub.FailWith(http.StatusServiceUnavailable)
+	_, err := index.Search(ctx, items[0].Vector, 3)
+	var se *statusError
+	if !errors.As(err, &se) || se.status != http.StatusServiceUnavailable {
+		t.Fatalf("search with Weaviate failing: err = %v, want status 503", err)
+	}
+	stub.FailWith(0)
+
+	// A query against a class Weaviate does not have comes back as a GraphQL error
+	missing := NewWeaviateIndex(server.URL, "Missing", "", time.Second)
+	if _, err := missing.Search(ctx, items[0].Vector, 3); err == nil {
+		t.Fatal("search of a missing class succeeded")
+	}
+
+	// A slow Weaviate is cut off by the per-request timeout
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body) // So the server notices the client hanging up
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	start := time.Now()
+	if _, err := NewWeaviateIndex(slow.URL, "BarrierExemplar", "", 20*time.Millisecond).Search(ctx, items[0].Vector, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("slow search: err = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("slow search took %s; the timeout should have cut it short", elapsed)
+	}
+}
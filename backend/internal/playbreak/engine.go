@@ -0,0 +1,236 @@
+package playbreak
+
+import (
+	"math"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// Window and confirmation sizes
+const (
+	outcomeWindow = 20  // Recent outcomes kept for SuccessRate
+	minOutcomes   = 10  // Outcomes needed before advancing or regressing
+	confirmReady  = 3   // Consecutive ready evaluations before advancing
+	workSmoothing = 0.3 // EWMA weight for each new work-duration sample
+	hoursPerDay   = 24
+)
+
+// defaultRequirements follow transitionCriteria in the play break graduation
+// system (mongo_personalized.json): typical timelines are 3-6 months for
+// stage 1→2 and 6-12 months for 2→3; stage 1 students graduate once they
+// sustain 15+ minutes, stage 2 tasks run 10-30 minutes, stage 3 sessions
+// 45-60 minutes. Praise-only is the goal state and never advances.
+var defaultRequirements = map[etp.PlayBreakStage]etp.StageRequirements{
+	etp.ConcentrationStage: {MinDaysInStage: 90, MinSuccessRate: 0.7, MinWorkDuration: 15},
+	etp.RewardsStage:       {MinDaysInStage: 180, MinSuccessRate: 0.75, MinWorkDuration: 30, RegressBelow: 0.35},
+	etp.ExamPeriodStage:    {MinDaysInStage: 180, MinSuccessRate: 0.8, MinWorkDuration: 45, RegressBelow: 0.4},
+	etp.PraiseStage:        {RegressBelow: 0.4},
+}
+
+// Outcome is one coached interaction as the engine sees it
+type Outcome struct {
+	Success     bool      // Engaged or rewarded, rather than avoiding
+	Rewarded    bool      // A play break was earned
+	WorkMinutes float64   // Focused time before this reward (0 if unknown)
+	BarrierIDs  []string  // Barriers detected in this interaction
+	At          time.Time // When the interaction happened
+}
+
+// Transition reports a stage change
+type Transition struct {
+	From      etp.PlayBreakStage `json:"from"`
+	To        etp.PlayBreakStage `json:"to"`
+	Regressed bool               `json:"regressed"`
+	Message   string             `json:"message"`
+}
+
+// Engine advances and regresses students through the play break stages
+type Engine struct {
+	requirements map[etp.PlayBreakStage]etp.StageRequirements
+	guidance     map[string]Guidance // Barrier ID → timeline pacing
+}
+
+// NewEngine creates an engine with the graduation-system thresholds,
+// paced by the timelineExpectation of each barrier profile
+func NewEngine(profiles []etp.BarrierStudentProfile) *Engine {
+	guidance := make(map[string]Guidance, len(profiles))
+	for i := range profiles {
+		guidance[profiles[i].ID] = GuidanceFor(&profiles[i])
+	}
+
+	return &Engine{
+		requirements: defaultRequirements,
+		guidance:     guidance,
+	}
+}
+
+// NewProfile starts a student at the first stage
+func (e *Engine) NewProfile(studentID string, now time.Time) etp.PlayBreakProfile {
+	profile := etp.PlayBreakProfile{
+		StudentID:       studentID,
+		CurrentStage:    etp.ConcentrationStage,
+		LastProgression: now,
+		RecentOutcomes:  []bool{},
+		PacingBarriers:  []string{},
+	}
+	profile.Requirements = e.Requirements(profile.CurrentStage, nil)
+	return profile
+}
+
+// Requirements returns the thresholds for stage, paced by the slowest
+// timeline among the given barriers
+func (e *Engine) Requirements(stage etp.PlayBreakStage, barrierIDs []string) etp.StageRequirements {
+	req := e.requirements[stage]
+	if stage == etp.PraiseStage {
+		return req
+	}
+
+	base := req.MinDaysInStage
+	paced := false
+	for _, id := range barrierIDs {
+		g, ok := e.guidance[id]
+		if !ok {
+			continue
+		}
+		if days := g.minDays(stage, base); !paced || days > req.MinDaysInStage {
+			req.MinDaysInStage = days
+			req.PacedBy = g.BarrierID
+			paced = true
+		}
+	}
+	return req
+}
+
+// Record folds an interaction into the profile and moves stage when warranted
+// It returns the transition, or nil if the stage is unchanged
+func (e *Engine) Record(profile *etp.PlayBreakProfile, outcome Outcome) *Transition {
+	if outcome.At.IsZero() {
+		outcome.At = time.Now().UTC()
+	}
+	if profile.LastProgression.IsZero() {
+		profile.LastProgression = outcome.At
+	}
+
+	profile.RecentOutcomes = append(profile.RecentOutcomes, outcome.Success)
+	if len(profile.RecentOutcomes) > outcomeWindow {
+		profile.RecentOutcomes = profile.RecentOutcomes[len(profile.RecentOutcomes)-outcomeWindow:]
+	}
+
+	for _, id := range outcome.BarrierIDs {
+		profile.PacingBarriers = appendUnique(profile.PacingBarriers, id)
+	}
+
+	if outcome.Rewarded {
+		profile.RewardsEarned++
+		if outcome.WorkMinutes > 0 {
+			profile.WorkDuration = smoothWork(profile.WorkDuration, outcome.WorkMinutes)
+		}
+	}
+
+	e.evaluate(profile, outcome.At)
+
+	req := profile.Requirements
+	enough := len(profile.RecentOutcomes) >= minOutcomes
+
+	// Setbacks are normal: step back one stage, rebuild, progress again
+	if enough && profile.CurrentStage > etp.ConcentrationStage && profile.SuccessRate < req.RegressBelow {
+		return e.move(profile, profile.CurrentStage-1, outcome.At)
+	}
+
+	if profile.ReadyStreak >= confirmReady && profile.CurrentStage < etp.PraiseStage {
+		return e.move(profile, profile.CurrentStage+1, outcome.At)
+	}
+
+	return nil
+}
+
+// Refresh recomputes time-based fields without recording an interaction
+func (e *Engine) Refresh(profile *etp.PlayBreakProfile, now time.Time) {
+	streak := profile.ReadyStreak
+	e.evaluate(profile, now)
+	profile.ReadyStreak = streak // Only real interactions count towards confirmation
+}
+
+// evaluate recomputes SuccessRate, TimeInStage, Requirements and ReadyToAdvance
+func (e *Engine) evaluate(profile *etp.PlayBreakProfile, now time.Time) {
+	profile.SuccessRate = successRate(profile.RecentOutcomes)
+	profile.TimeInStage = daysBetween(profile.LastProgression, now)
+	profile.Requirements = e.Requirements(profile.CurrentStage, profile.PacingBarriers)
+
+	req := profile.Requirements
+	profile.ReadyToAdvance = profile.CurrentStage < etp.PraiseStage &&
+		len(profile.RecentOutcomes) >= minOutcomes &&
+		profile.TimeInStage >= req.MinDaysInStage &&
+		profile.SuccessRate >= req.MinSuccessRate &&
+		profile.WorkDuration >= req.MinWorkDuration
+
+	if profile.ReadyToAdvance {
+		profile.ReadyStreak++
+	} else {
+		profile.ReadyStreak = 0
+	}
+}
+
+func (e *Engine) move(
+	profile *etp.PlayBreakProfile,
+	to etp.PlayBreakStage,
+	now time.Time,
+) *Transition {
+	transition := &Transition{
+		From:      profile.CurrentStage,
+		To:        to,
+		Regressed: to < profile.CurrentStage,
+	}
+	if transition.Regressed {
+		profile.Regressions++
+		transition.Message = "Let's go back to shorter stretches for a bit - that's completely normal."
+	} else {
+		transition.Message = "You're getting really good at focusing! Let's try a slightly longer challenge."
+	}
+
+	profile.CurrentStage = to
+	profile.LastProgression = now
+	profile.RecentOutcomes = []bool{}
+	profile.PacingBarriers = []string{} // The new stage is paced by what shows up in it
+	profile.ReadyStreak = 0
+	e.evaluate(profile, now)
+
+	return transition
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func successRate(outcomes []bool) float64 {
+	if len(outcomes) == 0 {
+		return 0
+	}
+	successes := 0
+	for _, ok := range outcomes {
+		if ok {
+			successes++
+		}
+	}
+	return float64(successes) / float64(len(outcomes))
+}
+
+func smoothWork(current int, sample float64) int {
+	if current == 0 {
+		return int(math.Round(sample))
+	}
+	return int(math.Round(float64(current)*(1-workSmoothing) + sample*workSmoothing))
+}
+
+func daysBetween(from, to time.Time) int {
+	if from.IsZero() || to.Before(from) {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / hoursPerDay)
+}
@@ -0,0 +1,116 @@
+package playbreak
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// referenceHorizon is the longest timeline (in days) treated as normal pace;
+// lack_of_motivation expects intrinsic motivation within 2-6 months
+const referenceHorizon = 180
+
+// Pace is clamped so no barrier halves or more than doubles the defaults
+const (
+	minPace = 0.5
+	maxPace = 2.0
+)
+
+var (
+	stageKeyRegex = regexp.MustCompile(`^stage(\d)$`)
+	spanRegex     = regexp.MustCompile(`(?i)(\d+)\s*(?:-\s*(\d+))?\s*\+?\s*(day|week|month|year)s?`)
+	unitRegex     = regexp.MustCompile(`(?i)\b(day|week|month|year)s?\b`)
+)
+
+var unitDays = map[string]int{
+	"day":   1,
+	"week":  7,
+	"month": 30,
+	"year":  365,
+}
+
+// Guidance is how a barrier's timelineExpectation paces the play break stages
+type Guidance struct {
+	BarrierID string
+	StageDays map[etp.PlayBreakStage]int // Explicit "stageN" entries, lower bound in days
+	Pace      float64                    // Multiplier on default MinDaysInStage
+}
+
+// GuidanceFor reads a barrier's timelineExpectation
+// Barriers with stageN entries (quiet_playful_avoider) set stage minimums
+// directly; others scale the defaults by how long their timeline runs
+func GuidanceFor(profile *etp.BarrierStudentProfile) Guidance {
+	guidance := Guidance{
+		BarrierID: profile.ID,
+		StageDays: map[etp.PlayBreakStage]int{},
+		Pace:      1.0,
+	}
+
+	longest := 0
+	for _, entry := range profile.TimelineExpectation {
+		low, high, ok := parseSpan(entry.Expectation)
+		if !ok {
+			continue
+		}
+		if m := stageKeyRegex.FindStringSubmatch(entry.Key); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			if n >= 1 && n <= int(etp.PraiseStage)+1 {
+				guidance.StageDays[etp.PlayBreakStage(n-1)] = low
+			}
+		}
+		if high > longest {
+			longest = high
+		}
+	}
+
+	if longest > 0 {
+		pace := float64(longest) / referenceHorizon
+		guidance.Pace = math.Max(minPace, math.Min(maxPace, pace))
+	}
+
+	return guidance
+}
+
+func (g Guidance) minDays(stage etp.PlayBreakStage, base int) int {
+	if days, ok := g.StageDays[stage]; ok {
+		return days
+	}
+	return int(math.Round(float64(base) * g.Pace))
+}
+
+// parseSpan reads durations like "2-4 weeks", "3-6 months" or "year+"
+// and returns the shortest and longest in days
+func parseSpan(text string) (low, high int, ok bool) {
+	for _, m := range spanRegex.FindAllStringSubmatch(text, -1) {
+		unit := unitDays[strings.ToLower(m[3])]
+		from, _ := strconv.Atoi(m[1])
+		to := from
+		if m[2] != "" {
+			to, _ = strconv.Atoi(m[2])
+		}
+		low, high, ok = widen(low, high, ok, from*unit, to*unit)
+	}
+	if ok {
+		return low, high, true
+	}
+
+	// "Months of micro-victories", "Many months to year+": one of each unit
+	for _, m := range unitRegex.FindAllStringSubmatch(text, -1) {
+		days := unitDays[strings.ToLower(m[1])]
+		low, high, ok = widen(low, high, ok, days, days)
+	}
+	return low, high, ok
+}
+
+func widen(low, high int, ok bool, from, to int) (int, int, bool) {
+	if !ok || from < low {
+		low = from
+	}
+	if !ok || to > high {
+		high = to
+	}
+	return low, high, true
+}
@@ -0,0 +1,89 @@
+package readability
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mike5tew/humanos/internal/age"
+)
+
+var wordPattern = regexp.MustCompile(`[A-Za-z][A-Za-z'’]*`)
+
+// Metrics are readability measurements of one piece of text
+type Metrics struct {
+	Words              int      `json:"words"`
+	Sentences          int      `json:"sentences"`
+	Syllables          int      `json:"syllables"`
+	WordsPerSentence   float64  `json:"words_per_sentence"`
+	SyllablesPerWord   float64  `json:"syllables_per_word"`
+	FleschKincaidGrade float64  `json:"flesch_kincaid_grade"`
+	FleschReadingEase  float64  `json:"flesch_reading_ease"`
+	RareWords          []string `json:"rare_words"`
+	RareWordRatio      float64  `json:"rare_word_ratio"`
+}
+
+// Analyze measures text for a reader who knows words up to knownGrade
+// Names (capitalised mid-sentence) and numbers are never counted as rare
+func Analyze(text string, list *WordList, knownGrade int) Metrics {
+	m := Metrics{RareWords: []string{}}
+	seen := map[string]bool{}
+	rare := 0
+
+	for _, sentence := range age.SplitSentences(text) {
+		words := wordPattern.FindAllStringIndex(sentence.Text, -1)
+		if len(words) == 0 {
+			continue
+		}
+		m.Sentences++
+
+		for i, span := range words {
+			word := sentence.Text[span[0]:span[1]]
+			m.Words++
+			m.Syllables += age.CountSyllables(word)
+
+			if i > 0 && isName(word) {
+				continue
+			}
+			if grade, ok := list.Grade(word); ok && grade <= knownGrade {
+				continue
+			}
+			rare++
+			if lower := strings.ToLower(word); !seen[lower] {
+				seen[lower] = true
+				m.RareWords = append(m.RareWords, lower)
+			}
+		}
+	}
+
+	if m.Words == 0 {
+		return m
+	}
+
+	m.WordsPerSentence = round(float64(m.Words)/float64(m.Sentences), 2)
+	m.SyllablesPerWord = round(float64(m.Syllables)/float64(m.Words), 2)
+	m.RareWordRatio = round(float64(rare)/float64(m.Words), 3)
+
+	wps := float64(m.Words) / float64(m.Sentences)
+	spw := float64(m.Syllables) / float64(m.Words)
+	m.FleschKincaidGrade = round(0.39*wps+11.8*spw-15.59, 1)
+	m.FleschReadingEase = round(206.835-1.015*wps-84.6*spw, 1)
+	return m
+}
+
+// isName treats a capitalised word other than "I" as a proper noun
+func isName(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	if !unicode.IsUpper(r) {
+		return false
+	}
+	base := strings.ToLower(word)
+	return base != "i" && !strings.HasPrefix(base, "i'") && !strings.HasPrefix(base, "i’")
+}
+
+func round(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
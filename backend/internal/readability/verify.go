@@ -0,0 +1,82 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mike5tew/humanos/internal/age"
+)
+
+// Below this many words the grade level and syllable density swing too far
+// to judge on; they are still reported
+const minWordsForAverages = 10
+
+// Verdict is a reply's readability judged against an age band
+type Verdict struct {
+	Group   string          `json:"group"`
+	Metrics Metrics         `json:"metrics"`
+	Targets age.Readability `json:"targets"`
+	Pass    bool            `json:"pass"`
+	Issues  []string        `json:"issues"`
+}
+
+// Checker verifies replies against each age band's readability targets
+type Checker struct {
+	words *WordList
+}
+
+// NewChecker scores rare words against list; nil uses the embedded list
+func NewChecker(list *WordList) *Checker {
+	if list == nil {
+		list = DefaultWordList()
+	}
+	return &Checker{words: list}
+}
+
+// Verify measures text and compares it with the group's targets
+func (c *Checker) Verify(text string, group *age.Group) *Verdict {
+	targets := group.Readability
+	metrics := Analyze(text, c.words, targets.WordListGrade)
+	verdict := &Verdict{
+		Group:   group.Name,
+		Metrics: metrics,
+		Targets: targets,
+		Issues:  []string{},
+	}
+
+	averaged := metrics.Words >= minWordsForAverages
+	if averaged && metrics.FleschKincaidGrade > targets.MaxGradeLevel {
+		verdict.Issues = append(verdict.Issues,
+			fmt.Sprintf("grade level %.1f above %.1f", metrics.FleschKincaidGrade, targets.MaxGradeLevel))
+	}
+	if averaged && metrics.SyllablesPerWord > targets.MaxSyllablesPerWord {
+		verdict.Issues = append(verdict.Issues,
+			fmt.Sprintf("%.2f syllables per word above %.2f", metrics.SyllablesPerWord, targets.MaxSyllablesPerWord))
+	}
+	if metrics.RareWordRatio > targets.MaxRareWordRatio {
+		verdict.Issues = append(verdict.Issues,
+			fmt.Sprintf("%.0f%% rare words above %.0f%% (%s)", metrics.RareWordRatio*100, targets.MaxRareWordRatio*100,
+				strings.Join(metrics.RareWords, ", ")))
+	}
+
+	verdict.Pass = len(verdict.Issues) == 0
+	return verdict
+}
+
+// Summary is the reasoning line QA uses to track readability drift
+func (v *Verdict) Summary() string {
+	status := "✅"
+	if !v.Pass {
+		status = "⚠️"
+	}
+	summary := fmt.Sprintf("📖 Readability %s: FK grade %.1f (max %.1f), %.2f syllables/word (max %.2f), %.0f%% rare words (max %.0f%%), %d words in %d sentence(s)",
+		status,
+		v.Metrics.FleschKincaidGrade, v.Targets.MaxGradeLevel,
+		v.Metrics.SyllablesPerWord, v.Targets.MaxSyllablesPerWord,
+		v.Metrics.RareWordRatio*100, v.Targets.MaxRareWordRatio*100,
+		v.Metrics.Words, v.Metrics.Sentences)
+	if v.Metrics.Words < minWordsForAverages {
+		summary += fmt.Sprintf("; grade and density not judged under %d words", minWordsForAverages)
+	}
+	return summary
+}
@@ -0,0 +1,128 @@
+package readability
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//go:embed graded_words.txt
+var gradedWords string
+
+var defaultWordList = mustParseWordList(gradedWords)
+
+// Contraction endings stripped before lookup: "you're" → "you", "don't" → "do"
+var contractions = []string{"n't", "'re", "'ll", "'ve", "'s", "'d", "'m"}
+
+// WordList maps base word forms to the grade by which pupils know them
+type WordList struct {
+	grades   map[string]int
+	maxGrade int
+}
+
+// DefaultWordList is the graded list embedded in the binary
+func DefaultWordList() *WordList {
+	return defaultWordList
+}
+
+// ParseWordList reads "[grade]" sections of whitespace-separated words
+// Lines starting with # are comments; a word listed twice keeps its lowest grade
+func ParseWordList(data string) (*WordList, error) {
+	list := &WordList{grades: map[string]int{}}
+	grade := 0
+
+	for n, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			g, err := strconv.Atoi(strings.Trim(line, "[]"))
+			if err != nil || g <= 0 {
+				return nil, fmt.Errorf("word list line %d: bad grade %q", n+1, line)
+			}
+			grade = g
+			list.maxGrade = max(list.maxGrade, g)
+			continue
+		}
+		if grade == 0 {
+			return nil, fmt.Errorf("word list line %d: words before the first [grade]", n+1)
+		}
+		for _, word := range strings.Fields(strings.ToLower(line)) {
+			if known, ok := list.grades[word]; !ok || grade < known {
+				list.grades[word] = grade
+			}
+		}
+	}
+
+	if len(list.grades) == 0 {
+		return nil, fmt.Errorf("word list is empty")
+	}
+	return list, nil
+}
+
+func mustParseWordList(data string) *WordList {
+	list, err := ParseWordList(data)
+	if err != nil {
+		panic(err)
+	}
+	return list
+}
+
+// Grade returns the grade a word is known by, trying its base forms
+func (w *WordList) Grade(word string) (int, bool) {
+	best, found := 0, false
+	for _, form := range baseForms(word) {
+		if grade, ok := w.grades[form]; ok && (!found || grade < best) {
+			best, found = grade, true
+		}
+	}
+	return best, found
+}
+
+// Len is the number of distinct words listed
+func (w *WordList) Len() int {
+	return len(w.grades)
+}
+
+// baseForms lists the word and the stems it could be an inflection of
+func baseForms(word string) []string {
+	w := strings.ToLower(strings.ReplaceAll(word, "’", "'"))
+	for _, ending := range contractions {
+		if strings.HasSuffix(w, ending) && len(w) > len(ending) {
+			w = strings.TrimSuffix(w, ending)
+			if ending == "n't" && w == "wo" {
+				w = "will" // "won't"
+			}
+			if ending == "n't" && w == "ca" {
+				w = "can" // "can't"
+			}
+			break
+		}
+	}
+	w = strings.Trim(w, "'")
+
+	forms := []string{w}
+	add := func(stem string) {
+		if len(stem) >= 2 {
+			forms = append(forms, stem)
+		}
+	}
+
+	for _, suffix := range []string{"s", "es", "ed", "ing", "er", "est", "ly", "ness", "ment", "ful", "less"} {
+		if !strings.HasSuffix(w, suffix) {
+			continue
+		}
+		stem := strings.TrimSuffix(w, suffix)
+		add(stem)
+		add(stem + "e") // "making" → "make", "liked" → "like"
+		if n := len(stem); n >= 3 && stem[n-1] == stem[n-2] {
+			add(stem[:n-1]) // "running" → "run", "stopped" → "stop"
+		}
+		if strings.HasSuffix(stem, "i") {
+			add(strings.TrimSuffix(stem, "i") + "y") // "tries" → "try", "happily" → "happy"
+		}
+	}
+	return forms
+}
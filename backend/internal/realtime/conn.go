@@ -0,0 +1,234 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Largest client frame accepted; student messages are short
+const maxMessageSize = 8 * 1024
+
+// client is one WebSocket connection; only writePump writes to conn
+type client struct {
+	conn      *websocket.Conn
+	send      chan Event
+	closeOnce sync.Once
+
+	// ctx bounds coach turns and ends when the connection is dropped
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// enqueue queues an event without blocking; false means the client is too slow
+func (c *client) enqueue(event Event) bool {
+	select {
+	case c.send <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops writePump, which closes the socket, and cancels any turn in flight
+// Callers hold the owning channel's lock, so no enqueue races the close
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		close(c.send)
+	})
+}
+
+// ServeWS upgrades the request and streams events for studentID
+// A client may pass ?last_seq=N or send {"type":"resume"} to replay missed events
+func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request, studentID string) {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     h.checkOrigin,
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade failed for %s: %v", studentID, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	c := &client{
+		conn:   conn,
+		send:   make(chan Event, h.cfg.ReplayBuffer+16),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	go c.writePump(h.cfg)
+
+	ch, existed := h.channelFor(studentID)
+
+	ch.mu.Lock()
+	if ch.client != nil {
+		ch.dropClient() // Newest tab wins
+	}
+	ch.client = c
+	ch.touch()
+	ch.sendDirect(newEvent(EventConnected, ConnectedPayload{
+		StudentID: studentID,
+		LastSeq:   ch.seq,
+		Resumed:   existed,
+	}))
+	if raw := r.URL.Query().Get("last_seq"); raw != "" {
+		if lastSeq, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			ch.sendDirect(newEvent(EventResumed, ch.replay(lastSeq)))
+		}
+	}
+	ch.mu.Unlock()
+
+	h.readPump(ch, c)
+
+	ch.mu.Lock()
+	if ch.client == c {
+		ch.dropClient()
+	}
+	ch.mu.Unlock()
+}
+
+func (h *Hub) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || len(h.cfg.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range h.cfg.AllowedOrigins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// readPump handles client messages until the connection fails or goes quiet
+func (h *Hub) readPump(ch *channel, c *client) {
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(h.cfg.PongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(h.cfg.PongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("WebSocket read error for %s: %v", ch.studentID, err)
+			}
+			return
+		}
+		c.conn.SetReadDeadline(time.Now().Add(h.cfg.PongWait))
+
+		var in inbound
+		if err := json.Unmarshal(data, &in); err != nil {
+			h.reject(ch, "Invalid message")
+			continue
+		}
+		h.handle(ch, c, in)
+	}
+}
+
+func (h *Hub) handle(ch *channel, c *client, in inbound) {
+	switch in.Type {
+	case ClientPing:
+		ch.mu.Lock()
+		ch.sendDirect(newEvent(EventPong, nil))
+		ch.mu.Unlock()
+
+	case ClientTyping:
+		ch.mu.Lock()
+		ch.touch()
+		ch.mu.Unlock()
+
+	case ClientIdle:
+		ch.mu.Lock()
+		if ch.playBreakEnds.IsZero() {
+			ch.nudgeInactive(h.cfg)
+		}
+		ch.mu.Unlock()
+
+	case ClientResume:
+		ch.mu.Lock()
+		ch.sendDirect(newEvent(EventResumed, ch.replay(in.LastSeq)))
+		ch.mu.Unlock()
+
+	case ClientMessage:
+		h.handleMessage(ch, c, in)
+
+	default:
+		h.reject(ch, "Unknown message type: "+in.Type)
+	}
+}
+
+func (h *Hub) handleMessage(ch *channel, c *client, in inbound) {
+	if strings.TrimSpace(in.Message) == "" {
+		h.reject(ch, "Message is empty")
+		return
+	}
+
+	ch.mu.Lock()
+	ch.touch()
+	if in.Context != nil {
+		ch.context = *in.Context
+		ch.context.StudentID = ch.studentID
+	}
+	studentContext := ch.context
+	ch.mu.Unlock()
+
+	// Run the coach outside the lock so nudges and pongs keep flowing
+	response, err := h.process(c.ctx, ch.studentID, in.Message, studentContext)
+	if err != nil {
+		log.Printf("Error processing WebSocket message for %s: %v", ch.studentID, err)
+		h.reject(ch, "Could not process message")
+		return
+	}
+
+	h.Publish(ch.studentID, response)
+}
+
+func (h *Hub) reject(ch *channel, message string) {
+	ch.mu.Lock()
+	ch.sendDirect(newEvent(EventError, ErrorPayload{Message: message}))
+	ch.mu.Unlock()
+}
+
+// writePump serialises writes and sends heartbeat pings
+func (c *client) writePump(cfg Config) {
+	ticker := time.NewTicker(cfg.PingInterval)
+	defer func() {
+		ticker.Stop()
+		c.cancel() // A failed write means the peer has gone
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case event, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(cfg.WriteWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteJSON(event); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(cfg.WriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
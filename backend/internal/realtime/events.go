@@ -0,0 +1,81 @@
+package realtime
+
+import (
+	"time"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// Server → client event types
+const (
+	EventConnected     = "connected"      // Sent once per connection, not sequenced
+	EventResumed       = "resumed"        // Replay finished after a resume request
+	EventCoachResponse = "coach_response" // Data is a coach.CoachResponse
+	EventNudge         = "nudge"          // Server-initiated prompt, Data is a Nudge
+	EventPong          = "pong"           // Reply to a client ping
+	EventError         = "error"          // Data is an ErrorPayload
+)
+
+// Client → server message types
+const (
+	ClientMessage = "message" // Student message for the coach
+	ClientTyping  = "typing"  // Student is typing
+	ClientIdle    = "idle"    // Client noticed the student went quiet
+	ClientResume  = "resume"  // Replay events after LastSeq
+	ClientPing    = "ping"    // Application-level heartbeat
+)
+
+// Nudge kinds
+const (
+	NudgeInactivity       = "inactivity_prompt"
+	NudgePlayBreakExpired = "play_break_expired"
+)
+
+// Event is the envelope for everything the server sends
+// Seq is set on replayable events so a reconnecting client can resume
+type Event struct {
+	Type      string      `json:"type"`
+	Seq       uint64      `json:"seq,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp string      `json:"timestamp"`
+}
+
+// ConnectedPayload tells the client where the stream stands
+type ConnectedPayload struct {
+	StudentID string `json:"student_id"`
+	LastSeq   uint64 `json:"last_seq"`
+	Resumed   bool   `json:"resumed"` // Server still held state from a previous connection
+}
+
+// ResumedPayload reports how a replay went
+type ResumedPayload struct {
+	Replayed int  `json:"replayed"`
+	Complete bool `json:"complete"` // False if some events had already been dropped
+}
+
+// Nudge is a prompt the server sends without the student asking
+type Nudge struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// ErrorPayload describes a rejected client message
+type ErrorPayload struct {
+	Message string `json:"message"`
+}
+
+// inbound is a message from the client
+type inbound struct {
+	Type    string              `json:"type"`
+	Message string              `json:"message,omitempty"`
+	Context *etp.StudentContext `json:"context,omitempty"`
+	LastSeq uint64              `json:"last_seq,omitempty"`
+}
+
+func newEvent(eventType string, data interface{}) Event {
+	return Event{
+		Type:      eventType,
+		Data:      data,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
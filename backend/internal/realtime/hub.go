@@ -0,0 +1,350 @@
+package realtime
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/coach"
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// Processor runs a student message through the coach
+// cmd/api supplies one that also persists the profile
+type Processor func(
+	ctx context.Context,
+	studentID string,
+	message string,
+	context etp.StudentContext,
+) (*coach.CoachResponse, error)
+
+// Config tunes heartbeat, resume and nudge timing
+type Config struct {
+	PingInterval       time.Duration // How often the server pings
+	PongWait           time.Duration // Connection is dead after this long without a frame
+	WriteWait          time.Duration // Deadline for a single write
+	ResumeWindow       time.Duration // How long stream state outlives a disconnect
+	ReplayBuffer       int           // Events kept for resuming clients
+	InactivityAfter    time.Duration // Silence before an inactivity prompt
+	InactivityBarriers []string      // Barrier IDs that get inactivity prompts
+	ScanInterval       time.Duration // How often nudges are checked
+	AllowedOrigins     []string      // Browser origins allowed to connect; empty allows all
+}
+
+// DefaultConfig returns production defaults
+func DefaultConfig() Config {
+	return Config{
+		PingInterval:       25 * time.Second,
+		PongWait:           60 * time.Second,
+		WriteWait:          10 * time.Second,
+		ResumeWindow:       2 * time.Minute,
+		ReplayBuffer:       50,
+		InactivityAfter:    45 * time.Second,
+		InactivityBarriers: []string{"silent_avoider"},
+		ScanInterval:       5 * time.Second,
+		AllowedOrigins: []string{
+			"http://localhost",      // nginx gateway
+			"http://localhost:3000", // frontend container
+			"http://localhost:5173", // vite dev server
+		},
+	}
+}
+
+// ConfigFromEnv overlays WS_ALLOWED_ORIGINS and WS_INACTIVITY_SECONDS on the defaults
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+
+	if origins := os.Getenv("WS_ALLOWED_ORIGINS"); origins != "" {
+		cfg.AllowedOrigins = nil
+		for _, origin := range strings.Split(origins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" && origin != "*" {
+				cfg.AllowedOrigins = append(cfg.AllowedOrigins, origin)
+			}
+		}
+	}
+	if secs, err := strconv.Atoi(os.Getenv("WS_INACTIVITY_SECONDS")); err == nil && secs > 0 {
+		cfg.InactivityAfter = time.Duration(secs) * time.Second
+	}
+
+	return cfg
+}
+
+// Hub owns one stream per student and survives reconnects within ResumeWindow
+type Hub struct {
+	process  Processor
+	cfg      Config
+	channels map[string]*channel
+	mu       sync.Mutex
+}
+
+// NewHub creates a hub; call Run to start nudges and cleanup
+func NewHub(process Processor, cfg Config) *Hub {
+	return &Hub{
+		process:  process,
+		cfg:      cfg,
+		channels: make(map[string]*channel),
+	}
+}
+
+// Run checks nudges and expires idle streams until ctx is cancelled
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.cfg.ScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.closeAll()
+			return
+		case now := <-ticker.C:
+			h.scan(now)
+		}
+	}
+}
+
+// Publish streams a coach response to the student if they have a live or resumable stream
+func (h *Hub) Publish(studentID string, response *coach.CoachResponse) {
+	h.mu.Lock()
+	ch, ok := h.channels[studentID]
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.recordResponse(response)
+	ch.emit(EventCoachResponse, response, h.cfg.ReplayBuffer)
+}
+
+// StartPlayBreak schedules the play-break expiry nudge for a redeemed unlock
+func (h *Hub) StartPlayBreak(studentID string, until time.Time) {
+	ch, _ := h.channelFor(studentID)
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.playBreakEnds = until
+	if ch.client == nil && ch.disconnectedAt.IsZero() {
+		ch.disconnectedAt = time.Now() // Keep it resumable until the break ends
+	}
+}
+
+func (h *Hub) channelFor(studentID string) (*channel, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if ch, ok := h.channels[studentID]; ok {
+		return ch, true
+	}
+	ch := newChannel(studentID)
+	h.channels[studentID] = ch
+	return ch, false
+}
+
+func (h *Hub) scan(now time.Time) {
+	h.mu.Lock()
+	channels := make([]*channel, 0, len(h.channels))
+	for _, ch := range h.channels {
+		channels = append(channels, ch)
+	}
+	h.mu.Unlock()
+
+	for _, ch := range channels {
+		ch.mu.Lock()
+		ch.checkNudges(now, h.cfg)
+		expired := ch.client == nil &&
+			ch.playBreakEnds.IsZero() &&
+			now.Sub(ch.disconnectedAt) > h.cfg.ResumeWindow
+		ch.mu.Unlock()
+
+		if expired {
+			h.mu.Lock()
+			// Re-check: the student may have reconnected since we unlocked
+			ch.mu.Lock()
+			if ch.client == nil && h.channels[ch.studentID] == ch {
+				delete(h.channels, ch.studentID)
+			}
+			ch.mu.Unlock()
+			h.mu.Unlock()
+		}
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, ch := range h.channels {
+		ch.mu.Lock()
+		if ch.client != nil {
+			ch.client.close()
+			ch.client = nil
+		}
+		ch.mu.Unlock()
+	}
+}
+
+// channel is the per-student stream state
+type channel struct {
+	studentID string
+	mu        sync.Mutex
+
+	seq    uint64
+	buffer []Event // Last ReplayBuffer sequenced events
+
+	client         *client // nil while disconnected
+	disconnectedAt time.Time
+
+	context       etp.StudentContext
+	barrierIDs    []string
+	lastActivity  time.Time
+	nudged        bool // Inactivity prompt already sent for this silence
+	playBreakEnds time.Time
+}
+
+func newChannel(studentID string) *channel {
+	return &channel{
+		studentID:    studentID,
+		context:      etp.StudentContext{StudentID: studentID},
+		lastActivity: time.Now(),
+	}
+}
+
+// emit sequences an event, buffers it for resume and sends it if connected
+// Callers hold ch.mu
+func (ch *channel) emit(eventType string, data interface{}, limit int) {
+	ch.seq++
+	event := newEvent(eventType, data)
+	event.Seq = ch.seq
+
+	ch.buffer = append(ch.buffer, event)
+	if len(ch.buffer) > limit {
+		ch.buffer = ch.buffer[len(ch.buffer)-limit:]
+	}
+
+	if ch.client != nil && !ch.client.enqueue(event) {
+		ch.dropClient()
+	}
+}
+
+// sendDirect sends an unsequenced event to the current connection only
+// Callers hold ch.mu
+func (ch *channel) sendDirect(event Event) {
+	if ch.client != nil && !ch.client.enqueue(event) {
+		ch.dropClient()
+	}
+}
+
+// replay resends buffered events after lastSeq
+// Callers hold ch.mu
+func (ch *channel) replay(lastSeq uint64) ResumedPayload {
+	result := ResumedPayload{Complete: true}
+	if len(ch.buffer) > 0 && ch.buffer[0].Seq > lastSeq+1 {
+		result.Complete = false
+	}
+
+	for _, event := range ch.buffer {
+		if event.Seq <= lastSeq {
+			continue
+		}
+		if ch.client == nil || !ch.client.enqueue(event) {
+			ch.dropClient()
+			result.Complete = false
+			break
+		}
+		result.Replayed++
+	}
+
+	return result
+}
+
+// recordResponse keeps what the nudge rules need from a coach response
+// Callers hold ch.mu
+func (ch *channel) recordResponse(response *coach.CoachResponse) {
+	ch.barrierIDs = append([]string{}, response.DetectedBarrierIDs...)
+	ch.lastActivity = time.Now()
+	ch.nudged = false
+}
+
+// touch records student activity
+// Callers hold ch.mu
+func (ch *channel) touch() {
+	ch.lastActivity = time.Now()
+	ch.nudged = false
+}
+
+// checkNudges emits any server-initiated prompts that are due
+// Callers hold ch.mu
+func (ch *channel) checkNudges(now time.Time, cfg Config) {
+	// Play-break expiry is buffered even while disconnected so it replays on resume
+	if !ch.playBreakEnds.IsZero() && !now.Before(ch.playBreakEnds) {
+		ch.playBreakEnds = time.Time{}
+		ch.touch()
+		ch.emit(EventNudge, Nudge{
+			Kind:    NudgePlayBreakExpired,
+			Message: playBreakOverMessage(ch.context.Age),
+		}, cfg.ReplayBuffer)
+		return
+	}
+
+	// Don't prompt while the student is on a break or nobody is watching
+	if ch.client == nil || !ch.playBreakEnds.IsZero() {
+		return
+	}
+	if now.Sub(ch.lastActivity) >= cfg.InactivityAfter {
+		ch.nudgeInactive(cfg)
+	}
+}
+
+// nudgeInactive sends one inactivity prompt per silence to barriers that need it
+// Callers hold ch.mu
+func (ch *channel) nudgeInactive(cfg Config) {
+	if ch.nudged || !ch.hasAnyBarrier(cfg.InactivityBarriers) {
+		return
+	}
+	ch.nudged = true
+	ch.emit(EventNudge, Nudge{
+		Kind:    NudgeInactivity,
+		Message: inactivityMessage(ch.context.Age),
+	}, cfg.ReplayBuffer)
+}
+
+func (ch *channel) hasAnyBarrier(ids []string) bool {
+	for _, active := range ch.barrierIDs {
+		for _, id := range ids {
+			if active == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dropClient closes the current connection; it can resume later
+// Callers hold ch.mu
+func (ch *channel) dropClient() {
+	if ch.client == nil {
+		return
+	}
+	ch.client.close()
+	ch.client = nil
+	ch.disconnectedAt = time.Now()
+}
+
+// Nudge wording stays low-pressure: silent avoiders shut down further if pushed
+func inactivityMessage(age int) string {
+	if age > 0 && age < 10 {
+		return "Still there? No rush! Can you try just one tiny thing - even one word?"
+	}
+	return "No pressure - I'm still here. Want to try just one word to get us going?"
+}
+
+func playBreakOverMessage(age int) string {
+	if age > 0 && age < 10 {
+		return "Play time's up - great job earning it! Ready for the next bit?"
+	}
+	return "Break's over - you earned that one. Ready to pick up where we left off?"
+}
@@ -0,0 +1,200 @@
+package rewards
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Ledger errors
+var (
+	ErrInvalidCode     = errors.New("invalid unlock code")
+	ErrUnknownCode     = errors.New("unknown unlock code")
+	ErrExpired         = errors.New("unlock code expired")
+	ErrAlreadyRedeemed = errors.New("unlock code already redeemed")
+	ErrDailyCap        = errors.New("daily play break limit reached")
+)
+
+// Config sets reward sizes and limits
+type Config struct {
+	Secret       []byte        // HMAC key shared with the game launcher
+	PlayDuration time.Duration // Game access per unlock ("5 minutes game access")
+	RedeemWithin time.Duration // Unredeemed codes lapse after this
+	DailyCap     int           // Unlocks a student can earn per day
+	Location     *time.Location
+}
+
+// DefaultConfig returns the barriers.json reward defaults
+// "Use minimally, just enough to generate initial engagement"
+func DefaultConfig() Config {
+	return Config{
+		PlayDuration: 5 * time.Minute,
+		RedeemWithin: 30 * time.Minute,
+		DailyCap:     6,
+		Location:     time.Local,
+	}
+}
+
+// ConfigFromEnv reads REWARD_SECRET and REWARD_DAILY_CAP
+// Without a secret a random one is used, so codes die with the process
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+
+	if secret := os.Getenv("REWARD_SECRET"); secret != "" {
+		cfg.Secret = []byte(secret)
+	} else {
+		log.Printf("REWARD_SECRET not set - using a random key; unlock codes will not survive a restart")
+		cfg.Secret = randomBytes(32)
+	}
+	if limit, err := strconv.Atoi(os.Getenv("REWARD_DAILY_CAP")); err == nil && limit > 0 {
+		cfg.DailyCap = limit
+	}
+
+	return cfg
+}
+
+// Ledger mints, validates and redeems unlock codes
+type Ledger struct {
+	cfg   Config
+	store Store
+	mu    sync.Mutex // Makes cap checks and single-use redemption atomic
+	now   func() time.Time
+}
+
+// NewLedger creates a ledger over store
+func NewLedger(store Store, cfg Config) (*Ledger, error) {
+	if len(cfg.Secret) < 16 {
+		return nil, fmt.Errorf("reward secret must be at least 16 bytes")
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+
+	return &Ledger{
+		cfg:   cfg,
+		store: store,
+		now:   time.Now,
+	}, nil
+}
+
+// Mint issues a new unlock for studentID, enforcing the daily cap
+func (l *Ledger) Mint(studentID, earnedThrough string) (*Unlock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now().UTC()
+	issued, err := l.store.CountIssuedSince(studentID, l.startOfDay(now))
+	if err != nil {
+		return nil, err
+	}
+	if issued >= l.cfg.DailyCap {
+		return nil, ErrDailyCap
+	}
+
+	unlock := &Unlock{
+		ID:            hex.EncodeToString(randomBytes(12)),
+		StudentID:     studentID,
+		EarnedThrough: earnedThrough,
+		PlayMinutes:   int(l.cfg.PlayDuration / time.Minute),
+		IssuedAt:      now,
+		ValidUntil:    now.Add(l.cfg.RedeemWithin),
+	}
+	unlock.Code, err = signCode(l.cfg.Secret, claims{
+		ID:          unlock.ID,
+		StudentID:   studentID,
+		IssuedAt:    now.Unix(),
+		ValidUntil:  unlock.ValidUntil.Unix(),
+		PlayMinutes: unlock.PlayMinutes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.store.Put(unlock); err != nil {
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// Validate checks a code and returns its current record
+func (l *Ledger) Validate(code string) (*Unlock, error) {
+	c, err := parseCode(l.cfg.Secret, code)
+	if err != nil {
+		return nil, err
+	}
+	return l.store.Get(c.ID)
+}
+
+// Redeem starts the play window for a code; each code works once
+func (l *Ledger) Redeem(code string) (*Unlock, error) {
+	c, err := parseCode(l.cfg.Secret, code)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	unlock, err := l.store.Get(c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := l.now().UTC()
+	switch unlock.Status(now) {
+	case StatusActive, StatusUsed:
+		return unlock, ErrAlreadyRedeemed
+	case StatusExpired:
+		return unlock, ErrExpired
+	}
+
+	playUntil := now.Add(time.Duration(unlock.PlayMinutes) * time.Minute)
+	unlock.RedeemedAt = &now
+	unlock.PlayUntil = &playUntil
+
+	if err := l.store.Put(unlock); err != nil {
+		return nil, err
+	}
+	return unlock, nil
+}
+
+// RemainingToday reports how many more unlocks studentID can earn today
+func (l *Ledger) RemainingToday(studentID string) (int, error) {
+	issued, err := l.store.CountIssuedSince(studentID, l.startOfDay(l.now().UTC()))
+	if err != nil {
+		return 0, err
+	}
+	if issued >= l.cfg.DailyCap {
+		return 0, nil
+	}
+	return l.cfg.DailyCap - issued, nil
+}
+
+// Now is the ledger's clock, for reporting status consistently
+func (l *Ledger) Now() time.Time {
+	return l.now().UTC()
+}
+
+// Close releases the store
+func (l *Ledger) Close() error {
+	return l.store.Close()
+}
+
+func (l *Ledger) startOfDay(now time.Time) time.Time {
+	local := now.In(l.cfg.Location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, l.cfg.Location)
+}
+
+func randomBytes(n int) []byte {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return buf
+}
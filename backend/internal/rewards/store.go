@@ -0,0 +1,153 @@
+package rewards
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var unlocksBucket = []byte("unlocks")
+
+// Store persists unlock records
+type Store interface {
+	Put(unlock *Unlock) error
+
+	// Get returns ErrUnknownCode when no record exists
+	Get(id string) (*Unlock, error)
+
+	// CountIssuedSince counts a student's unlocks issued at or after since
+	CountIssuedSince(studentID string, since time.Time) (int, error)
+
+	Close() error
+}
+
+// MemoryStore keeps unlocks in process memory (tests and demos)
+type MemoryStore struct {
+	unlocks map[string]Unlock
+	mu      sync.Mutex
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{unlocks: make(map[string]Unlock)}
+}
+
+// Put inserts or replaces an unlock
+func (s *MemoryStore) Put(unlock *Unlock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unlocks[unlock.ID] = *unlock
+	return nil
+}
+
+// Get loads an unlock by ID
+func (s *MemoryStore) Get(id string) (*Unlock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, ok := s.unlocks[id]
+	if !ok {
+		return nil, ErrUnknownCode
+	}
+	return &unlock, nil
+}
+
+// CountIssuedSince counts a student's unlocks issued at or after since
+func (s *MemoryStore) CountIssuedSince(studentID string, since time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, unlock := range s.unlocks {
+		if unlock.StudentID == studentID && !unlock.IssuedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Close releases the store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// BoltStore keeps unlocks in their own BoltDB file
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (or creates) the reward database
+func NewBoltStore(path string) (*BoltStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create reward store directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reward store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(unlocksBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Put inserts or replaces an unlock
+func (s *BoltStore) Put(unlock *Unlock) error {
+	data, err := json.Marshal(unlock)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(unlocksBucket).Put([]byte(unlock.ID), data)
+	})
+}
+
+// Get loads an unlock by ID
+func (s *BoltStore) Get(id string) (*Unlock, error) {
+	var unlock *Unlock
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(unlocksBucket).Get([]byte(id))
+		if raw == nil {
+			return ErrUnknownCode
+		}
+		unlock = &Unlock{}
+		return json.Unmarshal(raw, unlock)
+	})
+	return unlock, err
+}
+
+// CountIssuedSince scans every unlock; volumes are a handful per student per day
+func (s *BoltStore) CountIssuedSince(studentID string, since time.Time) (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(unlocksBucket).ForEach(func(_, value []byte) error {
+			var unlock Unlock
+			if err := json.Unmarshal(value, &unlock); err != nil {
+				return err
+			}
+			if unlock.StudentID == studentID && !unlock.IssuedAt.Before(since) {
+				count++
+			}
+			return nil
+		})
+	})
+	return count, err
+}
+
+// Close releases the store
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
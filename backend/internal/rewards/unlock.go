@@ -0,0 +1,93 @@
+package rewards
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// codePrefix marks game-access codes, matching the frontend RewardSystem
+const codePrefix = "GAME-"
+
+// Unlock statuses
+const (
+	StatusUnredeemed = "unredeemed" // Minted, waiting to be used
+	StatusActive     = "active"     // Redeemed, play window still open
+	StatusUsed       = "used"       // Play window finished
+	StatusExpired    = "expired"    // Never redeemed in time
+)
+
+// Unlock is one earned play break ("Generate time-limited unlock code")
+type Unlock struct {
+	ID            string     `json:"id"`
+	StudentID     string     `json:"student_id"`
+	Code          string     `json:"code"`
+	EarnedThrough string     `json:"earned_through"`
+	PlayMinutes   int        `json:"play_minutes"`
+	IssuedAt      time.Time  `json:"issued_at"`
+	ValidUntil    time.Time  `json:"valid_until"` // Must be redeemed before this
+	RedeemedAt    *time.Time `json:"redeemed_at,omitempty"`
+	PlayUntil     *time.Time `json:"play_until,omitempty"`
+}
+
+// Status reports where the unlock is in its lifecycle at now
+func (u *Unlock) Status(now time.Time) string {
+	switch {
+	case u.PlayUntil != nil && now.Before(*u.PlayUntil):
+		return StatusActive
+	case u.RedeemedAt != nil:
+		return StatusUsed
+	case !now.Before(u.ValidUntil):
+		return StatusExpired
+	default:
+		return StatusUnredeemed
+	}
+}
+
+// claims are the signed contents of a code, so a launcher holding the
+// secret can check a code offline; redemption still goes through the ledger
+type claims struct {
+	ID          string `json:"jti"`
+	StudentID   string `json:"sub"`
+	IssuedAt    int64  `json:"iat"`
+	ValidUntil  int64  `json:"exp"`
+	PlayMinutes int    `json:"min"`
+}
+
+// signCode produces GAME-<payload>.<signature>
+func signCode(secret []byte, c claims) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return codePrefix + body + "." + signature(secret, body), nil
+}
+
+// parseCode verifies the signature and returns the claims
+func parseCode(secret []byte, code string) (claims, error) {
+	var c claims
+
+	body, sig, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(code), codePrefix), ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(signature(secret, body))) {
+		return c, ErrInvalidCode
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return c, ErrInvalidCode
+	}
+	if err := json.Unmarshal(payload, &c); err != nil || c.ID == "" {
+		return c, ErrInvalidCode
+	}
+	return c, nil
+}
+
+func signature(secret []byte, body string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,193 @@
+package safeguarding
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Audit events: detection → escalation → outcome
+// ("auditTrail": "Complete record of detection → escalation → outcome")
+const (
+	AuditDetected     = "detected"
+	AuditQueued       = "alert_queued"
+	AuditAttemptFail  = "delivery_failed"
+	AuditDelivered    = "delivered"
+	AuditDeadLettered = "dead_lettered"
+	AuditCaseOpened   = "case_opened"
+	AuditCaseAssigned = "case_assigned"
+	AuditCaseResolved = "case_resolved"
+	AuditCaseReopened = "case_reopened"
+)
+
+// AuditEntry is one immutable line of the safeguarding audit trail
+// Message content is deliberately absent: it lives with the alert, not the log
+type AuditEntry struct {
+	Seq       uint64    `json:"seq"`
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"`
+	AlertID   string    `json:"alert_id,omitempty"`
+	StudentID string    `json:"student_id"`
+	Severity  int       `json:"severity,omitempty"`
+	Category  string    `json:"category,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	Actor     string    `json:"actor,omitempty"` // Who acted: "system" or a staff ID
+	PrevHash  string    `json:"prev_hash"`
+	Hash      string    `json:"hash"`
+}
+
+// AuditLog is an append-only, hash-chained JSON-lines file
+// Each entry commits to the previous one, so edits or deletions break the chain
+type AuditLog struct {
+	path     string
+	file     *os.File
+	seq      uint64
+	lastHash string
+	mu       sync.Mutex
+}
+
+// OpenAuditLog opens (or creates) the log and picks up the chain where it ended
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+		}
+	}
+
+	audit := &AuditLog{path: path}
+	err := audit.scan(func(entry AuditEntry) bool {
+		audit.seq = entry.Seq
+		audit.lastHash = entry.Hash
+		return true
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	audit.file = file
+
+	return audit, nil
+}
+
+// Append writes an entry, assigning its sequence number, time and hashes
+func (l *AuditLog) Append(entry AuditEntry) (AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Seq = l.seq + 1
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	if entry.Actor == "" {
+		entry.Actor = "system"
+	}
+	entry.PrevHash = l.lastHash
+	entry.Hash = ""
+
+	hash, err := hashEntry(entry)
+	if err != nil {
+		return entry, err
+	}
+	entry.Hash = hash
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return entry, fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return entry, fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	l.seq = entry.Seq
+	l.lastHash = entry.Hash
+	return entry, nil
+}
+
+// Entries returns every entry for an alert, oldest first ("" returns all)
+func (l *AuditLog) Entries(alertID string) ([]AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := []AuditEntry{}
+	err := l.scan(func(entry AuditEntry) bool {
+		if alertID == "" || entry.AlertID == alertID {
+			entries = append(entries, entry)
+		}
+		return true
+	})
+	return entries, err
+}
+
+// Verify walks the chain and reports the first broken link
+func (l *AuditLog) Verify() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	prev := ""
+	var verr error
+	err := l.scan(func(entry AuditEntry) bool {
+		want := entry.Hash
+		entry.Hash = ""
+		got, err := hashEntry(entry)
+		if err != nil || got != want || entry.PrevHash != prev {
+			verr = fmt.Errorf("audit log chain broken at seq %d", entry.Seq)
+			return false
+		}
+		prev = want
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return verr
+}
+
+// Close flushes and closes the file
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+func (l *AuditLog) scan(fn func(AuditEntry) bool) error {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("corrupt audit log line: %w", err)
+		}
+		if !fn(entry) {
+			break
+		}
+	}
+	return scanner.Err()
+}
+
+func hashEntry(entry AuditEntry) (string, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
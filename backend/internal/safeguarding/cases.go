@@ -0,0 +1,389 @@
+package safeguarding
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Case statuses: detection → notification → review → action → feedback
+const (
+	CaseOpen     = "open"     // Notified, awaiting a reviewer
+	CaseAssigned = "assigned" // A trained professional is assessing
+	CaseResolved = "resolved" // Action taken and outcome logged
+)
+
+// Outcome codes a reviewer records when resolving a case
+const (
+	OutcomeReferred      = "referred_external"  // Referred to social care, police or health services
+	OutcomeConfirmed     = "confirmed_internal" // Genuine concern handled within school
+	OutcomeMonitor       = "monitor"            // Concern noted, keep watching
+	OutcomeNoAction      = "no_further_action"  // Context understood, nothing to do
+	OutcomeFalsePositive = "false_positive"     // Detection misread the message
+)
+
+var outcomeCodes = map[string]bool{
+	OutcomeReferred:      true,
+	OutcomeConfirmed:     true,
+	OutcomeMonitor:       true,
+	OutcomeNoAction:      true,
+	OutcomeFalsePositive: true,
+}
+
+// Case errors
+var (
+	ErrCaseNotFound    = errors.New("safeguarding case not found")
+	ErrInvalidOutcome  = errors.New("unknown outcome code")
+	ErrCaseResolved    = errors.New("case is already resolved")
+	ErrCaseNotResolved = errors.New("case is not resolved")
+)
+
+// Tuning thresholds: a pattern needs this many reviewed cases before
+// outcomes change its severity, and this false-positive rate to step down;
+// a confirmed concern steps it back up
+const (
+	tuningMinReviews        = 5
+	tuningFalsePositiveRate = 0.8
+)
+
+// CaseTurn is one exchange from the session that raised the case
+type CaseTurn struct {
+	StudentMessage string    `json:"student_message"`
+	CoachMessage   string    `json:"coach_message"`
+	Barriers       []string  `json:"barriers,omitempty"`
+	Flagged        bool      `json:"flagged"` // The turn that triggered the alert
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// CaseEvent is a reviewer action kept on the case for quick reading
+// The audit log remains the authoritative record
+type CaseEvent struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Actor  string    `json:"actor"`
+	Note   string    `json:"note,omitempty"`
+}
+
+// Case is a safeguarding alert under human review
+type Case struct {
+	ID         string            `json:"id"` // Same as the alert ID
+	Alert      SafeguardingAlert `json:"alert"`
+	SessionID  string            `json:"session_id,omitempty"`
+	Context    []CaseTurn        `json:"context"`
+	Status     string            `json:"status"`
+	AssignedTo string            `json:"assigned_to,omitempty"`
+	Outcome    string            `json:"outcome,omitempty"`
+	Notes      string            `json:"notes,omitempty"`
+	History    []CaseEvent       `json:"history"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	ResolvedAt *time.Time        `json:"resolved_at,omitempty"`
+}
+
+// PatternStats summarises reviewer outcomes for one trauma pattern
+type PatternStats struct {
+	PatternID      string         `json:"pattern_id"`
+	Reviewed       int            `json:"reviewed"`
+	Concerns       int            `json:"concerns"` // Referred, confirmed or monitored
+	FalsePositives int            `json:"false_positives"`
+	Outcomes       map[string]int `json:"outcomes"`
+	Adjustment     int            `json:"severity_adjustment"`
+}
+
+// SeverityTuner adjusts a pattern's severity from reviewer feedback
+type SeverityTuner interface {
+	Adjustment(patternID string) int
+}
+
+// CaseManager runs the human review workflow and feeds outcomes back into detection
+type CaseManager struct {
+	store CaseStore
+	audit *AuditLog
+	stats map[string]*PatternStats
+	mu    sync.Mutex
+	now   func() time.Time
+}
+
+// NewCaseManager loads existing cases and derives tuning from their outcomes
+// audit may be nil, in which case reviewer actions live only on the case
+func NewCaseManager(store CaseStore, audit *AuditLog) (*CaseManager, error) {
+	cm := &CaseManager{
+		store: store,
+		audit: audit,
+		now:   func() time.Time { return time.Now().UTC() },
+	}
+	if err := cm.rebuildStats(); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// Open creates a case for an escalated alert with the conversation that led to it
+func (cm *CaseManager) Open(alert SafeguardingAlert, sessionID string, context []CaseTurn) (*Case, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	now := cm.now()
+	c := &Case{
+		ID:        alert.AlertID,
+		Alert:     alert,
+		SessionID: sessionID,
+		Context:   context,
+		Status:    CaseOpen,
+		History:   []CaseEvent{{Time: now, Action: AuditCaseOpened, Actor: "system"}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := cm.store.Put(c); err != nil {
+		return nil, fmt.Errorf("failed to open case %s: %w", c.ID, err)
+	}
+
+	cm.record(c, AuditCaseOpened, "system", fmt.Sprintf("%d turns of context", len(context)))
+	return c, nil
+}
+
+// Get loads a case by ID
+func (cm *CaseManager) Get(id string) (*Case, error) {
+	return cm.store.Get(id)
+}
+
+// List returns cases by status, newest first
+// "active" returns everything not yet resolved; "" returns all
+func (cm *CaseManager) List(status string) ([]*Case, error) {
+	cases, err := cm.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []*Case{}
+	for _, c := range cases {
+		switch {
+		case status == "":
+		case status == "active" && c.Status != CaseResolved:
+		case c.Status == status:
+		default:
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+	})
+	return filtered, nil
+}
+
+// Trail returns the audit entries for a case, oldest first
+func (cm *CaseManager) Trail(id string) ([]AuditEntry, error) {
+	if cm.audit == nil {
+		return []AuditEntry{}, nil
+	}
+	return cm.audit.Entries(id)
+}
+
+// Assign hands a case to a reviewer; reassigning is allowed until it is resolved
+func (cm *CaseManager) Assign(id, assignee, actor string) (*Case, error) {
+	return cm.update(id, func(c *Case) (string, string, error) {
+		if c.Status == CaseResolved {
+			return "", "", ErrCaseResolved
+		}
+		c.Status = CaseAssigned
+		c.AssignedTo = assignee
+		return AuditCaseAssigned, assignee, nil
+	}, actor)
+}
+
+// Resolve records the reviewer's outcome and retunes detection
+func (cm *CaseManager) Resolve(id, outcome, notes, actor string) (*Case, error) {
+	if !outcomeCodes[outcome] {
+		return nil, ErrInvalidOutcome
+	}
+
+	return cm.update(id, func(c *Case) (string, string, error) {
+		if c.Status == CaseResolved {
+			return "", "", ErrCaseResolved
+		}
+		now := cm.now()
+		c.Status = CaseResolved
+		c.Outcome = outcome
+		c.Notes = notes
+		c.ResolvedAt = &now
+		return AuditCaseResolved, outcome, nil
+	}, actor)
+}
+
+// Reopen returns a resolved case to review; its outcome stops counting towards tuning
+func (cm *CaseManager) Reopen(id, reason, actor string) (*Case, error) {
+	return cm.update(id, func(c *Case) (string, string, error) {
+		if c.Status != CaseResolved {
+			return "", "", ErrCaseNotResolved
+		}
+		c.Status = CaseOpen
+		if c.AssignedTo != "" {
+			c.Status = CaseAssigned
+		}
+		c.Outcome = ""
+		c.ResolvedAt = nil
+		return AuditCaseReopened, reason, nil
+	}, actor)
+}
+
+// Stats returns reviewer outcomes per pattern, most reviewed first
+func (cm *CaseManager) Stats() []PatternStats {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	stats := make([]PatternStats, 0, len(cm.stats))
+	for _, s := range cm.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Reviewed != stats[j].Reviewed {
+			return stats[i].Reviewed > stats[j].Reviewed
+		}
+		return stats[i].PatternID < stats[j].PatternID
+	})
+	return stats
+}
+
+// Adjustment implements SeverityTuner
+func (cm *CaseManager) Adjustment(patternID string) int {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if s, ok := cm.stats[patternID]; ok {
+		return s.Adjustment
+	}
+	return 0
+}
+
+// update applies a reviewer action, persists it and writes the audit entry
+func (cm *CaseManager) update(id string, apply func(*Case) (event, detail string, err error), actor string) (*Case, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	c, err := cm.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := c.Status
+	event, detail, err := apply(c)
+	if err != nil {
+		return c, err
+	}
+
+	now := cm.now()
+	c.UpdatedAt = now
+	c.History = append(c.History, CaseEvent{Time: now, Action: event, Actor: actor, Note: detail})
+	if err := cm.store.Put(c); err != nil {
+		return nil, err
+	}
+
+	cm.record(c, event, actor, detail)
+	if previous == CaseResolved || c.Status == CaseResolved {
+		if err := cm.rebuildStatsLocked(); err != nil {
+			log.Printf("Failed to retune safeguarding patterns: %v", err)
+		}
+	}
+	return c, nil
+}
+
+func (cm *CaseManager) record(c *Case, event, actor, detail string) {
+	if cm.audit == nil {
+		return
+	}
+	entry := AuditEntry{
+		Event:     event,
+		AlertID:   c.ID,
+		StudentID: c.Alert.StudentID,
+		Severity:  c.Alert.Severity,
+		Category:  c.Alert.Category,
+		Detail:    detail,
+		Actor:     actor,
+	}
+	if _, err := cm.audit.Append(entry); err != nil {
+		// The case itself is saved; surface the gap loudly for follow-up
+		log.Printf("🚨 AUDIT WRITE FAILED (%s %s): %v", event, c.ID, err)
+	}
+}
+
+func (cm *CaseManager) rebuildStats() error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.rebuildStatsLocked()
+}
+
+// rebuildStatsLocked recomputes tuning from every resolved case
+// Volumes are small, and recomputing keeps reopened cases honest
+// Outcomes are replayed in the order they were resolved: a run of false
+// positives steps a pattern down, and a concern found after that restores it
+// and starts the count again
+func (cm *CaseManager) rebuildStatsLocked() error {
+	cases, err := cm.store.List()
+	if err != nil {
+		return err
+	}
+
+	resolved := []*Case{}
+	for _, c := range cases {
+		if c.Status == CaseResolved && c.Alert.PatternID != "" {
+			resolved = append(resolved, c)
+		}
+	}
+	sort.SliceStable(resolved, func(i, j int) bool {
+		return resolvedAt(resolved[i]).Before(resolvedAt(resolved[j]))
+	})
+
+	stats := map[string]*PatternStats{}
+	windows := map[string]*tuningWindow{}
+	for _, c := range resolved {
+		s, ok := stats[c.Alert.PatternID]
+		if !ok {
+			s = &PatternStats{PatternID: c.Alert.PatternID, Outcomes: map[string]int{}}
+			stats[c.Alert.PatternID] = s
+			windows[c.Alert.PatternID] = &tuningWindow{}
+		}
+		window := windows[c.Alert.PatternID]
+
+		s.Reviewed++
+		s.Outcomes[c.Outcome]++
+		window.reviewed++
+		switch c.Outcome {
+		case OutcomeReferred, OutcomeConfirmed, OutcomeMonitor:
+			s.Concerns++
+			if s.Adjustment < 0 {
+				s.Adjustment++
+				*window = tuningWindow{}
+			}
+		case OutcomeFalsePositive:
+			s.FalsePositives++
+			window.falsePositives++
+		}
+
+		if s.Adjustment == 0 && window.reviewed >= tuningMinReviews &&
+			float64(window.falsePositives)/float64(window.reviewed) >= tuningFalsePositiveRate {
+			s.Adjustment = -1
+		}
+	}
+
+	cm.stats = stats
+	return nil
+}
+
+// tuningWindow counts outcomes since a pattern's sensitivity was last restored
+type tuningWindow struct {
+	reviewed       int
+	falsePositives int
+}
+
+// resolvedAt orders outcomes, falling back to the last update for older records
+func resolvedAt(c *Case) time.Time {
+	if c.ResolvedAt != nil {
+		return *c.ResolvedAt
+	}
+	return c.UpdatedAt
+}
@@ -0,0 +1,156 @@
+package safeguarding
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var casesBucket = []byte("cases")
+
+// CaseStore persists safeguarding cases
+type CaseStore interface {
+	Put(c *Case) error
+
+	// Get returns ErrCaseNotFound when no case exists
+	Get(id string) (*Case, error)
+
+	List() ([]*Case, error)
+	Close() error
+}
+
+// MemoryCaseStore keeps cases in process memory (tests and demos)
+type MemoryCaseStore struct {
+	cases map[string][]byte // JSON, so callers never share slices with the store
+	mu    sync.Mutex
+}
+
+// NewMemoryCaseStore creates an empty in-memory store
+func NewMemoryCaseStore() *MemoryCaseStore {
+	return &MemoryCaseStore{cases: make(map[string][]byte)}
+}
+
+// Put inserts or replaces a case
+func (s *MemoryCaseStore) Put(c *Case) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cases[c.ID] = data
+	return nil
+}
+
+// Get loads a case by ID
+func (s *MemoryCaseStore) Get(id string) (*Case, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	raw, ok := s.cases[id]
+	if !ok {
+		return nil, ErrCaseNotFound
+	}
+	c := &Case{}
+	return c, json.Unmarshal(raw, c)
+}
+
+// List returns every case
+func (s *MemoryCaseStore) List() ([]*Case, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cases := make([]*Case, 0, len(s.cases))
+	for _, raw := range s.cases {
+		c := &Case{}
+		if err := json.Unmarshal(raw, c); err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// Close releases the store
+func (s *MemoryCaseStore) Close() error {
+	return nil
+}
+
+// BoltCaseStore keeps cases in their own BoltDB file
+type BoltCaseStore struct {
+	db *bolt.DB
+}
+
+// NewBoltCaseStore opens (or creates) the case database
+func NewBoltCaseStore(path string) (*BoltCaseStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create case store directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open case store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(casesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltCaseStore{db: db}, nil
+}
+
+// Put inserts or replaces a case
+func (s *BoltCaseStore) Put(c *Case) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(casesBucket).Put([]byte(c.ID), data)
+	})
+}
+
+// Get loads a case by ID
+func (s *BoltCaseStore) Get(id string) (*Case, error) {
+	var c *Case
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(casesBucket).Get([]byte(id))
+		if raw == nil {
+			return ErrCaseNotFound
+		}
+		c = &Case{}
+		return json.Unmarshal(raw, c)
+	})
+	return c, err
+}
+
+// List returns every case
+func (s *BoltCaseStore) List() ([]*Case, error) {
+	cases := []*Case{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(casesBucket).ForEach(func(key, value []byte) error {
+			c := &Case{}
+			if err := json.Unmarshal(value, c); err != nil {
+				return fmt.Errorf("corrupt case %s: %w", key, err)
+			}
+			cases = append(cases, c)
+			return nil
+		})
+	})
+	return cases, err
+}
+
+// Close releases the database file lock
+func (s *BoltCaseStore) Close() error {
+	return s.db.Close()
+}
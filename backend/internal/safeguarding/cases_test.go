@@ -0,0 +1,56 @@
+package safeguarding
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTuningStepsDownAndIsRestoredByConcerns(t *testing.T) {
+	cm, err := NewCaseManager(NewMemoryCaseStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	cm.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+
+	n := 0
+	resolve := func(outcome string) {
+		t.Helper()
+		n++
+		alert := SafeguardingAlert{AlertID: fmt.Sprintf("alert-%d", n), PatternID: "normalized_violence", Severity: 2}
+		if _, err := cm.Open(alert, "", nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cm.Resolve(alert.AlertID, outcome, "", "reviewer"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < tuningMinReviews; i++ {
+		resolve(OutcomeFalsePositive)
+	}
+	if got := cm.Adjustment("normalized_violence"); got != -1 {
+		t.Fatalf("after %d false positives adjustment = %d, want -1", tuningMinReviews, got)
+	}
+
+	resolve(OutcomeConfirmed)
+	if got := cm.Adjustment("normalized_violence"); got != 0 {
+		t.Fatalf("after a confirmed concern adjustment = %d, want 0", got)
+	}
+
+	// The count starts again: it takes a fresh run of false positives to step down
+	for i := 0; i < tuningMinReviews-1; i++ {
+		resolve(OutcomeFalsePositive)
+	}
+	if got := cm.Adjustment("normalized_violence"); got != 0 {
+		t.Fatalf("before a fresh run completes adjustment = %d, want 0", got)
+	}
+	resolve(OutcomeFalsePositive)
+	if got := cm.Adjustment("normalized_violence"); got != -1 {
+		t.Fatalf("after a fresh run adjustment = %d, want -1", got)
+	}
+}
@@ -0,0 +1,157 @@
+package safeguarding
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Context discounts: how many levels a framing lowers a match.
+// A discounted match never drops below level 1, so it is still logged and monitored.
+const (
+	negationDiscount = 2 // "I'm not going to hurt anyone" still deserves a look
+	negatedFloor     = 3 // Negation alone never takes an imminent-risk match below this
+	framingDiscount  = 3 // Quoted or fictional text is monitoring only
+)
+
+var (
+	// Words that negate what follows within the same clause
+	negators = wordSet("not no never nobody nothing dont didnt doesnt wont isnt wasnt arent werent cant couldnt wouldnt shouldnt havent hasnt hadnt")
+
+	// Negators followed by these deny the stopping, telling or wanting, not
+	// what follows: "can't stop wanting to...", "can't tell anyone he...",
+	// "never said he...", "don't want to..."
+	negationBreakers = wordSet("stop help tell told say said want wanna know knows")
+
+	fictionCues  = regexp.MustCompile(`(?i)\b(story|stories|character|characters|novel|poem|chapter|author|narrator|protagonist|villain|heroine|plot|scene|essay|book|film|movie|episode|fiction|fairy ?tale|myth|legend|extract|passage|the text|the writer|macbeth|romeo|juliet)\b`)
+	fictionFrame = regexp.MustCompile(`(?i)\b((my|our|the|this) (story|essay|poem|book|novel|play|script)|once upon a time|write about|writing about)\b`)
+	gamingCues   = regexp.MustCompile(`(?i)\b(game|games|gaming|fortnite|minecraft|roblox|call of duty|cod|valorant|apex|among us|gta|overwatch|boss|respawn|spawn|server|pvp|npc|zombies?|creepers?|mobs?|loot|squad|noob|gg|headshot|lobby|xbox|playstation|ps5)\b`)
+	sportCues    = regexp.MustCompile(`(?i)\b(football|soccer|rugby|netball|hockey|basketball|cricket|tennis|badminton|dodgeball|match|tournament|league)\b`)
+	jokingCues   = regexp.MustCompile(`(?i)(\b(lol|lmao|jk|just kidding|only joking|joking|haha+)\b|😂|🤣)`)
+
+	// Real life is anchored by people and places; times ("tomorrow") are as
+	// common in game talk as in disclosures
+	firstPerson     = regexp.MustCompile(`(?i)\b(i|i'm|im|i've|me|my|myself)\b`)
+	realWorldAnchor = regexp.MustCompile(`(?i)\b(home|house|mum|mom|dad|stepdad|stepmum|stepmom|parents?|uncle|aunt|brother|sister|grandad|grandpa|grandma|nan|teacher|coach|neighbou?r|boyfriend|girlfriend|at school)\b`)
+
+	contextWordPattern = regexp.MustCompile(`[\p{L}']+`)
+	quotedText         = regexp.MustCompile(`"[^"]*"|“[^”]*”|‘[^’]*’`)
+)
+
+// ContextAnalysis describes how a matched phrase sits in the message
+type ContextAnalysis struct {
+	Negated    bool
+	Quoted     bool
+	Fiction    bool
+	Gaming     bool // A video game or sport
+	Joking     bool
+	Disclosure bool // First person about real life: only a game or sport is discounted
+}
+
+// Framed reports whether the match is about something other than the student's own life
+func (c ContextAnalysis) Framed() bool {
+	return !c.Disclosure && (c.Quoted || c.Fiction || c.Gaming)
+}
+
+// Notes explains the analysis for reasoning and the audit trail
+func (c ContextAnalysis) Notes() []string {
+	notes := []string{}
+	if c.Disclosure {
+		notes = append(notes, "first-person disclosure")
+	}
+	if c.Negated {
+		notes = append(notes, "negated")
+	}
+	if c.Quoted {
+		notes = append(notes, "inside quotation")
+	}
+	if c.Fiction {
+		notes = append(notes, "fiction/essay framing")
+	}
+	if c.Gaming {
+		notes = append(notes, "game or sport reference")
+	}
+	if c.Joking {
+		notes = append(notes, "joking tone")
+	}
+	return notes
+}
+
+// analyzeMatch looks at the sentence around message[start:end]
+func analyzeMatch(message string, start, end int) ContextAnalysis {
+	sentence, from := sentenceAround(message, start, end)
+	before := message[from:start]
+
+	analysis := ContextAnalysis{
+		Negated: negatedBefore(before),
+		Quoted:  insideQuotes(message, start, end),
+		Fiction: fictionCues.MatchString(sentence) || fictionFrame.MatchString(message),
+		Gaming:  gamingCues.MatchString(message) || sportCues.MatchString(message),
+		Joking:  jokingCues.MatchString(message),
+	}
+
+	// Quoted words don't make a disclosure: "he said 'I hate my dad'" is reported
+	unquoted := sentence
+	if analysis.Quoted {
+		unquoted = stripQuoted(sentence)
+	}
+	analysis.Disclosure = firstPerson.MatchString(unquoted) && realWorldAnchor.MatchString(unquoted)
+
+	return analysis
+}
+
+// sentenceAround returns the sentence containing the span and its start offset
+func sentenceAround(message string, start, end int) (string, int) {
+	from := strings.LastIndexAny(message[:start], ".!?\n") + 1
+	to := len(message)
+	if i := strings.IndexAny(message[end:], ".!?\n"); i >= 0 {
+		to = end + i
+	}
+	return message[from:to], from
+}
+
+// negatedBefore checks the last few words of the clause leading into a match
+func negatedBefore(before string) bool {
+	clause := strings.ToLower(before)
+	for _, boundary := range []string{",", ";", " but ", " and "} {
+		if i := strings.LastIndex(clause, boundary); i >= 0 {
+			clause = clause[i+len(boundary):]
+		}
+	}
+
+	words := contextWordPattern.FindAllString(clause, -1)
+	if len(words) > 4 {
+		words = words[len(words)-4:]
+	}
+	for i, word := range words {
+		if !negators[strings.ReplaceAll(word, "'", "")] {
+			continue
+		}
+		if i+1 < len(words) && negationBreakers[words[i+1]] {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// insideQuotes reports whether the span sits between an opening and closing quote
+func insideQuotes(message string, start, end int) bool {
+	for _, pair := range [][2]string{{`"`, `"`}, {"“", "”"}, {"‘", "’"}} {
+		opening, closing := pair[0], pair[1]
+		before := message[:start]
+		if opening == closing {
+			if strings.Count(before, opening)%2 == 1 && strings.Contains(message[end:], closing) {
+				return true
+			}
+			continue
+		}
+		if strings.LastIndex(before, opening) > strings.LastIndex(before, closing) && strings.Contains(message[end:], closing) {
+			return true
+		}
+	}
+	return false
+}
+
+func stripQuoted(s string) string {
+	return quotedText.ReplaceAllString(s, " ")
+}
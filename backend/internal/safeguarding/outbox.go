@@ -0,0 +1,342 @@
+package safeguarding
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	mrand "math/rand"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Outbox entry statuses
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead_letter"
+)
+
+// Alert destinations
+const (
+	DestinationSafeguarding = "safeguarding_team"
+	DestinationEmergency    = "emergency_services"
+)
+
+// OutboxEntry is one alert delivery to one destination
+type OutboxEntry struct {
+	ID            string            `json:"id"`
+	Alert         SafeguardingAlert `json:"alert"`
+	Destination   string            `json:"destination"`
+	Status        string            `json:"status"`
+	Attempts      int               `json:"attempts"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	LastError     string            `json:"last_error,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	DeliveredAt   *time.Time        `json:"delivered_at,omitempty"`
+}
+
+// Sender delivers an alert to a destination
+type Sender interface {
+	Send(ctx context.Context, destination string, alert SafeguardingAlert) error
+}
+
+// OutboxConfig tunes delivery
+type OutboxConfig struct {
+	SafeguardingURL string
+	EmergencyURL    string
+	MaxAttempts     int           // Attempts before dead-lettering
+	BaseBackoff     time.Duration // First retry delay; doubles per attempt
+	MaxBackoff      time.Duration
+	PollInterval    time.Duration
+	SendTimeout     time.Duration
+	StorePath       string
+	AuditPath       string
+}
+
+// DefaultOutboxConfig returns delivery defaults
+// Ten attempts, backing off from 2s and doubling to the five-minute cap, span about
+// 13½ minutes before an alert is dead-lettered
+func DefaultOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		SafeguardingURL: "http://safeguarding-team/api/alert",
+		EmergencyURL:    "http://emergency-services/api/report",
+		MaxAttempts:     10,
+		BaseBackoff:     2 * time.Second,
+		MaxBackoff:      5 * time.Minute,
+		PollInterval:    time.Second,
+		SendTimeout:     10 * time.Second,
+		StorePath:       "data/safeguarding.db",
+		AuditPath:       "data/safeguarding_audit.log",
+	}
+}
+
+// OutboxConfigFromEnv overlays SAFEGUARDING_* settings on the defaults
+func OutboxConfigFromEnv() OutboxConfig {
+	cfg := DefaultOutboxConfig()
+	if v := os.Getenv("SAFEGUARDING_ALERT_URL"); v != "" {
+		cfg.SafeguardingURL = v
+	}
+	if v := os.Getenv("EMERGENCY_ALERT_URL"); v != "" {
+		cfg.EmergencyURL = v
+	}
+	if v := os.Getenv("SAFEGUARDING_DB_PATH"); v != "" {
+		cfg.StorePath = v
+	}
+	if v := os.Getenv("SAFEGUARDING_AUDIT_PATH"); v != "" {
+		cfg.AuditPath = v
+	}
+	return cfg
+}
+
+// HTTPSender posts alerts as JSON
+type HTTPSender struct {
+	client    *http.Client
+	endpoints map[string]string
+}
+
+// NewHTTPSender creates a sender for the configured endpoints
+func NewHTTPSender(cfg OutboxConfig) *HTTPSender {
+	return &HTTPSender{
+		client: &http.Client{Timeout: cfg.SendTimeout},
+		endpoints: map[string]string{
+			DestinationSafeguarding: cfg.SafeguardingURL,
+			DestinationEmergency:    cfg.EmergencyURL,
+		},
+	}
+}
+
+// Send posts the alert; any non-2xx response counts as a failure
+func (s *HTTPSender) Send(ctx context.Context, destination string, alert SafeguardingAlert) error {
+	endpoint, ok := s.endpoints[destination]
+	if !ok || endpoint == "" {
+		return fmt.Errorf("no endpoint for %s", destination)
+	}
+
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", alert.AlertID+"/"+destination)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", destination, resp.Status)
+	}
+	return nil
+}
+
+// Outbox durably queues alerts and retries delivery until it succeeds or gives up
+type Outbox struct {
+	cfg    OutboxConfig
+	store  OutboxStore
+	audit  *AuditLog
+	sender Sender
+	wake   chan struct{}
+	now    func() time.Time
+}
+
+// NewOutbox wires a store, audit log and sender together
+func NewOutbox(store OutboxStore, audit *AuditLog, sender Sender, cfg OutboxConfig) *Outbox {
+	return &Outbox{
+		cfg:    cfg,
+		store:  store,
+		audit:  audit,
+		sender: sender,
+		wake:   make(chan struct{}, 1),
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// OpenOutbox opens the bolt-backed store and audit log from cfg
+func OpenOutbox(cfg OutboxConfig) (*Outbox, error) {
+	store, err := NewBoltOutboxStore(cfg.StorePath)
+	if err != nil {
+		return nil, err
+	}
+	audit, err := OpenAuditLog(cfg.AuditPath)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	return NewOutbox(store, audit, NewHTTPSender(cfg), cfg), nil
+}
+
+// Audit exposes the audit trail for case management
+func (o *Outbox) Audit() *AuditLog {
+	return o.audit
+}
+
+// Record appends to the audit trail, logging (never dropping silently) on failure
+func (o *Outbox) Record(entry AuditEntry) {
+	if _, err := o.audit.Append(entry); err != nil {
+		log.Printf("🚨 AUDIT WRITE FAILED (%s %s): %v", entry.Event, entry.AlertID, err)
+	}
+}
+
+// Enqueue persists an alert for a destination and wakes the worker
+func (o *Outbox) Enqueue(alert SafeguardingAlert, destination string) error {
+	now := o.now()
+	entry := &OutboxEntry{
+		ID:            newID(),
+		Alert:         alert,
+		Destination:   destination,
+		Status:        StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := o.store.Put(entry); err != nil {
+		return fmt.Errorf("failed to queue alert %s: %w", alert.AlertID, err)
+	}
+
+	o.Record(AuditEntry{
+		Event:     AuditQueued,
+		AlertID:   alert.AlertID,
+		StudentID: alert.StudentID,
+		Severity:  alert.Severity,
+		Category:  alert.Category,
+		Detail:    destination,
+	})
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Entries lists outbox entries by status ("" for all)
+func (o *Outbox) Entries(status string) ([]*OutboxEntry, error) {
+	return o.store.List(status)
+}
+
+// Deliveries lists the outbox entries for one alert
+func (o *Outbox) Deliveries(alertID string) ([]*OutboxEntry, error) {
+	entries, err := o.store.List("")
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []*OutboxEntry{}
+	for _, entry := range entries {
+		if entry.Alert.AlertID == alertID {
+			deliveries = append(deliveries, entry)
+		}
+	}
+	return deliveries, nil
+}
+
+// Run delivers due entries until ctx is cancelled
+// Entries left pending by a crash are picked up on the next start
+func (o *Outbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(o.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		o.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// Close releases the store and audit log
+func (o *Outbox) Close() error {
+	err := o.store.Close()
+	if aerr := o.audit.Close(); err == nil {
+		err = aerr
+	}
+	return err
+}
+
+func (o *Outbox) deliverDue(ctx context.Context) {
+	due, err := o.store.Due(o.now(), 20)
+	if err != nil {
+		log.Printf("Failed to read safeguarding outbox: %v", err)
+		return
+	}
+
+	for _, entry := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		o.attempt(ctx, entry)
+	}
+}
+
+func (o *Outbox) attempt(ctx context.Context, entry *OutboxEntry) {
+	sendCtx, cancel := context.WithTimeout(ctx, o.cfg.SendTimeout)
+	err := o.sender.Send(sendCtx, entry.Destination, entry.Alert)
+	cancel()
+
+	entry.Attempts++
+	now := o.now()
+	audit := AuditEntry{
+		AlertID:   entry.Alert.AlertID,
+		StudentID: entry.Alert.StudentID,
+		Severity:  entry.Alert.Severity,
+		Category:  entry.Alert.Category,
+	}
+
+	switch {
+	case err == nil:
+		entry.Status = StatusDelivered
+		entry.DeliveredAt = &now
+		entry.LastError = ""
+		audit.Event = AuditDelivered
+		audit.Detail = fmt.Sprintf("%s after %d attempt(s)", entry.Destination, entry.Attempts)
+
+	case entry.Attempts >= o.cfg.MaxAttempts:
+		entry.Status = StatusDead
+		entry.LastError = err.Error()
+		audit.Event = AuditDeadLettered
+		audit.Detail = fmt.Sprintf("%s: %v", entry.Destination, err)
+		log.Printf("🚨 SAFEGUARDING ALERT UNDELIVERED after %d attempts - manual follow-up required (alert %s, %s): %v",
+			entry.Attempts, entry.Alert.AlertID, entry.Destination, err)
+
+	default:
+		entry.LastError = err.Error()
+		entry.NextAttemptAt = now.Add(o.backoff(entry.Attempts))
+		audit.Event = AuditAttemptFail
+		audit.Detail = fmt.Sprintf("%s attempt %d: %v", entry.Destination, entry.Attempts, err)
+	}
+
+	if err := o.store.Put(entry); err != nil {
+		log.Printf("Failed to update outbox entry %s: %v", entry.ID, err)
+	}
+	o.Record(audit)
+}
+
+// backoff doubles per attempt with ±20% jitter, capped at MaxBackoff
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := float64(o.cfg.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	delay = math.Min(delay, float64(o.cfg.MaxBackoff))
+	jitter := 0.8 + 0.4*mrand.Float64()
+	return time.Duration(delay * jitter)
+}
+
+func newID() string {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,201 @@
+package safeguarding
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrEntryNotFound is returned for unknown outbox entry IDs
+var ErrEntryNotFound = errors.New("outbox entry not found")
+
+var outboxBucket = []byte("outbox")
+
+// OutboxStore persists queued alerts until they are delivered or dead-lettered
+type OutboxStore interface {
+	Put(entry *OutboxEntry) error
+	Get(id string) (*OutboxEntry, error)
+
+	// Due returns pending entries whose next attempt is at or before now, oldest first
+	Due(now time.Time, limit int) ([]*OutboxEntry, error)
+
+	// List returns entries with the given status ("" for all), oldest first
+	List(status string) ([]*OutboxEntry, error)
+
+	Close() error
+}
+
+// MemoryOutboxStore keeps entries in process memory (tests and demos)
+type MemoryOutboxStore struct {
+	entries map[string]OutboxEntry
+	mu      sync.Mutex
+}
+
+// NewMemoryOutboxStore creates an empty in-memory store
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{entries: make(map[string]OutboxEntry)}
+}
+
+// Put inserts or replaces an entry
+func (s *MemoryOutboxStore) Put(entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[entry.ID] = *entry
+	return nil
+}
+
+// Get loads an entry by ID
+func (s *MemoryOutboxStore) Get(id string) (*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[id]
+	if !ok {
+		return nil, ErrEntryNotFound
+	}
+	return &entry, nil
+}
+
+// Due returns pending entries ready for another attempt
+func (s *MemoryOutboxStore) Due(now time.Time, limit int) ([]*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := []*OutboxEntry{}
+	for _, entry := range s.entries {
+		if entry.Status == StatusPending && !entry.NextAttemptAt.After(now) {
+			e := entry
+			due = append(due, &e)
+		}
+	}
+	return limitOldest(due, limit), nil
+}
+
+// List returns entries with the given status
+func (s *MemoryOutboxStore) List(status string) ([]*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []*OutboxEntry{}
+	for _, entry := range s.entries {
+		if status == "" || entry.Status == status {
+			e := entry
+			list = append(list, &e)
+		}
+	}
+	return limitOldest(list, 0), nil
+}
+
+// Close releases the store
+func (s *MemoryOutboxStore) Close() error {
+	return nil
+}
+
+// BoltOutboxStore keeps the outbox in its own BoltDB file
+// Every write is committed before Put returns, so queued alerts survive a crash
+type BoltOutboxStore struct {
+	db *bolt.DB
+}
+
+// NewBoltOutboxStore opens (or creates) the outbox database
+func NewBoltOutboxStore(path string) (*BoltOutboxStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open safeguarding outbox: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(outboxBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltOutboxStore{db: db}, nil
+}
+
+// Put inserts or replaces an entry
+func (s *BoltOutboxStore) Put(entry *OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(outboxBucket).Put([]byte(entry.ID), data)
+	})
+}
+
+// Get loads an entry by ID
+func (s *BoltOutboxStore) Get(id string) (*OutboxEntry, error) {
+	var entry *OutboxEntry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(outboxBucket).Get([]byte(id))
+		if raw == nil {
+			return ErrEntryNotFound
+		}
+		entry = &OutboxEntry{}
+		return json.Unmarshal(raw, entry)
+	})
+	return entry, err
+}
+
+// Due returns pending entries ready for another attempt
+func (s *BoltOutboxStore) Due(now time.Time, limit int) ([]*OutboxEntry, error) {
+	due, err := s.filter(func(entry *OutboxEntry) bool {
+		return entry.Status == StatusPending && !entry.NextAttemptAt.After(now)
+	})
+	return limitOldest(due, limit), err
+}
+
+// List returns entries with the given status
+func (s *BoltOutboxStore) List(status string) ([]*OutboxEntry, error) {
+	list, err := s.filter(func(entry *OutboxEntry) bool {
+		return status == "" || entry.Status == status
+	})
+	return limitOldest(list, 0), err
+}
+
+// Close releases the database file lock
+func (s *BoltOutboxStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltOutboxStore) filter(keep func(*OutboxEntry) bool) ([]*OutboxEntry, error) {
+	entries := []*OutboxEntry{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(outboxBucket).ForEach(func(key, value []byte) error {
+			entry := &OutboxEntry{}
+			if err := json.Unmarshal(value, entry); err != nil {
+				return fmt.Errorf("corrupt outbox entry %s: %w", key, err)
+			}
+			if keep(entry) {
+				entries = append(entries, entry)
+			}
+			return nil
+		})
+	})
+	return entries, err
+}
+
+// limitOldest sorts by creation time and keeps at most limit (0 keeps all)
+func limitOldest(entries []*OutboxEntry, limit int) []*OutboxEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
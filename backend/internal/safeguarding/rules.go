@@ -0,0 +1,226 @@
+package safeguarding
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Escalation levels defined by detectionSystem.escalationMatrix
+var requiredEscalationLevels = []string{
+	"level1_monitoring",
+	"level2_concern",
+	"level3_serious",
+	"level4_emergency",
+}
+
+// Response templates defined by aiResponseProtocols.onDetection.responseTemplates
+var requiredResponseTemplates = []string{
+	"level2_3",
+	"level4_emergency",
+}
+
+// defaultSupportPerson fills the [support person] placeholder in templates
+const defaultSupportPerson = "a member of our support team"
+
+// TraumaIndicator is one indicator entry from traumaIndicators
+type TraumaIndicator struct {
+	Type         string   `json:"type"`
+	Examples     []string `json:"examples"`
+	Significance string   `json:"significance"`
+	Category     string   `json:"category,omitempty"`
+	Severity     int      `json:"severity,omitempty"` // 1-4, required when patterns are set
+	Patterns     []string `json:"patterns,omitempty"`
+}
+
+// IndicatorGroup is a top-level traumaIndicators section
+// Sections list entries under either "indicators" or "categories"
+type IndicatorGroup struct {
+	Category   string            `json:"category"`
+	Indicators []TraumaIndicator `json:"indicators"`
+	Categories []TraumaIndicator `json:"categories"`
+}
+
+// EscalationLevel is one row of the escalation matrix
+type EscalationLevel struct {
+	Trigger    string `json:"trigger"`
+	Action     string `json:"action"`
+	Review     string `json:"review,omitempty"`
+	AIResponse string `json:"aiResponse,omitempty"`
+}
+
+// ResponseTemplate is a severity-calibrated safeguarding reply
+type ResponseTemplate struct {
+	Message string `json:"message"`
+	Tone    string `json:"tone"`
+}
+
+// traumaSchema mirrors the parts of trauma_detection.json the detector uses
+type traumaSchema struct {
+	TraumaIndicators map[string]IndicatorGroup `json:"traumaIndicators"`
+	DetectionSystem  struct {
+		EscalationMatrix     map[string]EscalationLevel `json:"escalationMatrix"`
+		LongitudinalTracking *TrackingConfig            `json:"longitudinalTracking"`
+	} `json:"detectionSystem"`
+	AIResponseProtocols struct {
+		OnDetection struct {
+			ResponseTemplates map[string]ResponseTemplate `json:"responseTemplates"`
+		} `json:"onDetection"`
+	} `json:"aiResponseProtocols"`
+}
+
+// RuleSet is the validated, compiled form of the trauma schema
+type RuleSet struct {
+	Patterns          []TraumaPattern
+	EscalationMatrix  map[string]EscalationLevel
+	ResponseTemplates map[string]ResponseTemplate
+	Tracking          TrackingConfig
+}
+
+// LoadRuleSet reads and validates a trauma detection schema
+func LoadRuleSet(traumaPath string) (*RuleSet, error) {
+	data, err := os.ReadFile(traumaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRuleSet(data)
+}
+
+// ParseRuleSet builds a rule set from raw schema JSON
+// Every problem found is reported, not just the first
+func ParseRuleSet(data []byte) (*RuleSet, error) {
+	var schema traumaSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid trauma schema: %w", err)
+	}
+
+	problems := []string{}
+	rules := &RuleSet{
+		EscalationMatrix:  schema.DetectionSystem.EscalationMatrix,
+		ResponseTemplates: schema.AIResponseProtocols.OnDetection.ResponseTemplates,
+		Tracking:          DefaultTrackingConfig(),
+	}
+	if schema.DetectionSystem.LongitudinalTracking != nil {
+		rules.Tracking = *schema.DetectionSystem.LongitudinalTracking
+		problems = append(problems, rules.Tracking.validate()...)
+	}
+
+	// Walk groups in a stable order so rule priority is deterministic
+	groupKeys := make([]string, 0, len(schema.TraumaIndicators))
+	for key := range schema.TraumaIndicators {
+		groupKeys = append(groupKeys, key)
+	}
+	sort.Strings(groupKeys)
+
+	seen := map[string]bool{}
+	for _, groupKey := range groupKeys {
+		group := schema.TraumaIndicators[groupKey]
+		indicators := append(append([]TraumaIndicator{}, group.Indicators...), group.Categories...)
+
+		for _, indicator := range indicators {
+			// Descriptive-only indicators carry no patterns
+			if len(indicator.Patterns) == 0 {
+				continue
+			}
+
+			where := fmt.Sprintf("traumaIndicators.%s.%s", groupKey, indicator.Type)
+
+			if indicator.Type == "" {
+				problems = append(problems, fmt.Sprintf("traumaIndicators.%s: indicator with patterns has no type", groupKey))
+				continue
+			}
+			if seen[indicator.Type] {
+				problems = append(problems, fmt.Sprintf("%s: duplicate indicator type", where))
+				continue
+			}
+			seen[indicator.Type] = true
+
+			if indicator.Severity < 1 || indicator.Severity > 4 {
+				problems = append(problems, fmt.Sprintf("%s: severity %d outside 1-4", where, indicator.Severity))
+			}
+
+			category := indicator.Category
+			if category == "" {
+				category = groupKey
+			}
+
+			pattern := TraumaPattern{
+				ID:          indicator.Type,
+				Category:    category,
+				Patterns:    indicator.Patterns,
+				Severity:    indicator.Severity,
+				Description: indicator.Significance,
+			}
+
+			for _, patternStr := range indicator.Patterns {
+				regex, err := regexp.Compile("(?i)" + patternStr)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: bad pattern %q: %v", where, patternStr, err))
+					continue
+				}
+				pattern.compiled = append(pattern.compiled, regex)
+			}
+
+			rules.Patterns = append(rules.Patterns, pattern)
+		}
+	}
+
+	if len(rules.Patterns) == 0 {
+		problems = append(problems, "traumaIndicators: no indicators define patterns")
+	}
+
+	for _, level := range requiredEscalationLevels {
+		if entry, ok := rules.EscalationMatrix[level]; !ok || entry.Action == "" {
+			problems = append(problems, fmt.Sprintf("detectionSystem.escalationMatrix.%s: missing or has no action", level))
+		}
+	}
+
+	for _, name := range requiredResponseTemplates {
+		if tmpl, ok := rules.ResponseTemplates[name]; !ok || tmpl.Message == "" {
+			problems = append(problems, fmt.Sprintf("aiResponseProtocols.onDetection.responseTemplates.%s: missing or has no message", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid trauma schema:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	// Highest severity first so the most serious match wins
+	sort.SliceStable(rules.Patterns, func(i, j int) bool {
+		return rules.Patterns[i].Severity > rules.Patterns[j].Severity
+	})
+
+	return rules, nil
+}
+
+// EscalationFor returns the escalation matrix row for a severity level
+func (rs *RuleSet) EscalationFor(severity int) (EscalationLevel, bool) {
+	if severity < 1 {
+		return EscalationLevel{}, false
+	}
+	if severity > 4 {
+		severity = 4
+	}
+	entry, ok := rs.EscalationMatrix[requiredEscalationLevels[severity-1]]
+	return entry, ok
+}
+
+// ResponseFor returns the schema response template for a severity level
+func (rs *RuleSet) ResponseFor(severity int) (string, bool) {
+	name := "level2_3"
+	if severity >= 4 {
+		name = "level4_emergency"
+	} else if severity < 2 {
+		return "", false
+	}
+
+	tmpl, ok := rs.ResponseTemplates[name]
+	if !ok {
+		return "", false
+	}
+	return strings.ReplaceAll(tmpl.Message, "[support person]", defaultSupportPerson), true
+}
@@ -0,0 +1,373 @@
+package safeguarding
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Longitudinal escalation identifiers, used where a single pattern ID would be
+const (
+	PatternPersistent    = "persistent_pattern"
+	PatternLanguageShift = "language_shift"
+	CategoryChange       = "change_detection"
+)
+
+// TrackingConfig mirrors detectionSystem.longitudinalTracking
+type TrackingConfig struct {
+	WindowDays            int     `json:"windowDays"`            // Signals older than this are forgotten
+	HalfLifeDays          float64 `json:"halfLifeDays"`          // Decay of a signal's weight
+	PersistentDays        int     `json:"persistentDays"`        // Distinct days with indicators that make a pattern
+	PersistentScore       float64 `json:"persistentScore"`       // Decayed severity sum that makes a pattern
+	ReflagShiftAfterHours int     `json:"reflagShiftAfterHours"` // Quiet period before a shift is flagged again
+	BaselineMessages      int     `json:"baselineMessages"`      // Messages before change detection starts
+	RecentMessages        int     `json:"recentMessages"`        // Span of the short-term average
+	ChangeThreshold       float64 `json:"changeThreshold"`       // Standard deviations that count as a shift
+}
+
+// DefaultTrackingConfig is used when the schema has no longitudinalTracking section
+func DefaultTrackingConfig() TrackingConfig {
+	return TrackingConfig{
+		WindowDays:            14,
+		HalfLifeDays:          4,
+		PersistentDays:        3,
+		PersistentScore:       6,
+		ReflagShiftAfterHours: 72,
+		BaselineMessages:      30,
+		RecentMessages:        5,
+		ChangeThreshold:       2.5,
+	}
+}
+
+func (c TrackingConfig) validate() []string {
+	where := "detectionSystem.longitudinalTracking"
+	problems := []string{}
+	positive := map[string]float64{
+		"windowDays":       float64(c.WindowDays),
+		"halfLifeDays":     c.HalfLifeDays,
+		"persistentDays":   float64(c.PersistentDays),
+		"persistentScore":  c.PersistentScore,
+		"baselineMessages": float64(c.BaselineMessages),
+		"recentMessages":   float64(c.RecentMessages),
+		"changeThreshold":  c.ChangeThreshold,
+	}
+	for _, key := range sortedKeys(positive) {
+		if positive[key] <= 0 {
+			problems = append(problems, fmt.Sprintf("%s.%s: must be positive", where, key))
+		}
+	}
+	if c.RecentMessages >= c.BaselineMessages {
+		problems = append(problems, fmt.Sprintf("%s.recentMessages: must be shorter than baselineMessages", where))
+	}
+	return problems
+}
+
+// Signal is one indicator hit remembered for a student
+type Signal struct {
+	At        time.Time `json:"at"`
+	PatternID string    `json:"pattern_id"`
+	Category  string    `json:"category"`
+	Severity  int       `json:"severity"`
+}
+
+// FeatureStats is an exponentially weighted mean and variance
+type FeatureStats struct {
+	Mean float64 `json:"mean"`
+	Var  float64 `json:"var"`
+}
+
+func (f *FeatureStats) update(x, alpha float64) {
+	diff := x - f.Mean
+	incr := alpha * diff
+	f.Mean += incr
+	f.Var = (1 - alpha) * (f.Var + diff*incr)
+}
+
+// StudentSignals is one student's rolling safeguarding history
+type StudentSignals struct {
+	StudentID       string                  `json:"student_id"`
+	Signals         []Signal                `json:"signals"`
+	Messages        int                     `json:"messages"`
+	Baseline        map[string]FeatureStats `json:"baseline"` // Slow average: the student's usual language
+	Recent          map[string]FeatureStats `json:"recent"`   // Fast average: the last few messages
+	LastEscalatedAt *time.Time              `json:"last_escalated_at,omitempty"`
+	LastShiftAt     *time.Time              `json:"last_shift_at,omitempty"`
+	UpdatedAt       time.Time               `json:"updated_at"`
+}
+
+// LanguageShift is a departure of recent language from the student's baseline
+type LanguageShift struct {
+	Feature string  `json:"feature"`
+	Z       float64 `json:"z"`
+}
+
+// Assessment is the tracker's view after a message
+type Assessment struct {
+	Level    int // Escalation level implied by the history (0 = none)
+	Category string
+	Score    float64 // Decayed severity of sub-threshold signals
+	Signals  int     // Sub-threshold signals since the last escalation
+	Days     int     // Distinct days those signals fall on
+	Shift    *LanguageShift
+	Reasons  []string
+}
+
+// languageFeature is a per-message measure watched for sudden change
+// Direction is +1 when a rise is concerning and -1 when a fall is
+type languageFeature struct {
+	Name      string
+	Direction float64
+	MinSD     float64 // Floor so a very consistent student doesn't flag on noise
+}
+
+var languageFeatures = []languageFeature{
+	{Name: "length", Direction: -1, MinSD: 0.3},          // Withdrawal: replies getting much shorter (log words)
+	{Name: "negative_affect", Direction: 1, MinSD: 0.03}, // Share of distress words
+	{Name: "absolutist", Direction: 1, MinSD: 0.02},      // "always", "never", "nothing"...
+	{Name: "self_focus", Direction: 1, MinSD: 0.04},      // First-person singular
+}
+
+var (
+	wordPattern = regexp.MustCompile(`[a-z']+`)
+
+	negativeAffectWords = wordSet("sad upset cry crying cried hate hurt hurts scared afraid alone lonely tired " +
+		"worthless useless hopeless miserable awful horrible terrible depressed die dead pain ugly fault numb empty")
+	absolutistWords = wordSet("always never nothing everything everyone nobody completely totally entirely " +
+		"constantly forever whole")
+	selfWords = wordSet("i me my myself mine i'm im i've ive")
+)
+
+// Tracker accumulates per-student signals and language baselines
+type Tracker struct {
+	cfg   TrackingConfig
+	store SignalStore
+	mu    sync.Mutex
+	now   func() time.Time
+}
+
+// NewTracker creates a tracker over store
+func NewTracker(store SignalStore, cfg TrackingConfig) *Tracker {
+	return &Tracker{
+		cfg:   cfg,
+		store: store,
+		now:   func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Observe records a message and its indicator hits, and assesses the student's history
+// Sub-threshold hits build towards a persistent pattern; severe hits reset the count
+// because the human team is already involved
+func (t *Tracker) Observe(studentID, message string, hits []Signal) (Assessment, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	record, err := t.store.Get(studentID)
+	if err != nil {
+		return Assessment{}, err
+	}
+
+	record.prune(now.Add(-time.Duration(t.cfg.WindowDays) * 24 * time.Hour))
+
+	severe, mild := false, 0
+	for _, hit := range hits {
+		hit.At = now
+		record.Signals = append(record.Signals, hit)
+		if hit.Severity >= 3 {
+			severe = true
+		} else {
+			mild++
+		}
+	}
+	if severe {
+		record.LastEscalatedAt = &now
+	}
+
+	assessment := t.assessSignals(record, now)
+	if mild == 0 || severe {
+		// Nothing new to escalate on; decay only lowers the score
+		assessment.Level = 0
+		assessment.Reasons = nil
+	}
+	if assessment.Level >= 3 {
+		record.LastEscalatedAt = &now
+	}
+
+	if shift := t.observeLanguage(record, message, now); shift != nil {
+		assessment.Shift = shift
+		if assessment.Level < 2 {
+			assessment.Level = 2
+		}
+		if assessment.Category == "" {
+			assessment.Category = CategoryChange
+		}
+		assessment.Reasons = append(assessment.Reasons,
+			fmt.Sprintf("Sudden shift in language: %s %.1fσ from the student's baseline", shift.Feature, shift.Z))
+	}
+
+	record.UpdatedAt = now
+	if err := t.store.Put(record); err != nil {
+		return assessment, err
+	}
+	return assessment, nil
+}
+
+// History returns a student's tracked signals for review
+func (t *Tracker) History(studentID string) (*StudentSignals, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.store.Get(studentID)
+}
+
+// assessSignals scores sub-threshold signals since the last escalation
+func (t *Tracker) assessSignals(record *StudentSignals, now time.Time) Assessment {
+	assessment := Assessment{}
+	days := map[string]bool{}
+	categories := map[string]float64{}
+
+	for _, signal := range record.Signals {
+		if signal.Severity >= 3 {
+			continue
+		}
+		if record.LastEscalatedAt != nil && !signal.At.After(*record.LastEscalatedAt) {
+			continue
+		}
+		ageDays := now.Sub(signal.At).Hours() / 24
+		weight := float64(signal.Severity) * math.Pow(0.5, ageDays/t.cfg.HalfLifeDays)
+
+		assessment.Score += weight
+		assessment.Signals++
+		days[signal.At.Format("2006-01-02")] = true
+		categories[signal.Category] += weight
+	}
+	assessment.Days = len(days)
+
+	best := 0.0
+	for _, category := range sortedKeys(categories) {
+		if categories[category] > best {
+			best = categories[category]
+			assessment.Category = category
+		}
+	}
+
+	switch {
+	case assessment.Signals == 0:
+	case assessment.Days >= t.cfg.PersistentDays || assessment.Score >= t.cfg.PersistentScore:
+		assessment.Level = 3
+		assessment.Reasons = append(assessment.Reasons,
+			fmt.Sprintf("Persistent pattern: %d mild indicators over %d days (score %.1f)",
+				assessment.Signals, assessment.Days, assessment.Score))
+	case assessment.Signals >= 2:
+		assessment.Level = 2
+		assessment.Reasons = append(assessment.Reasons,
+			fmt.Sprintf("Multiple mild indicators: %d over %d days", assessment.Signals, assessment.Days))
+	}
+
+	return assessment
+}
+
+// observeLanguage updates the baselines and reports a newly detected shift
+func (t *Tracker) observeLanguage(record *StudentSignals, message string, now time.Time) *LanguageShift {
+	features := extractFeatures(message)
+	if record.Baseline == nil {
+		record.Baseline = map[string]FeatureStats{}
+		record.Recent = map[string]FeatureStats{}
+	}
+
+	record.Messages++
+	slow := 2 / float64(t.cfg.BaselineMessages+1)
+	fast := 2 / float64(t.cfg.RecentMessages+1)
+
+	var shift *LanguageShift
+	for _, feature := range languageFeatures {
+		x := features[feature.Name]
+		baseline, recent := record.Baseline[feature.Name], record.Recent[feature.Name]
+		if record.Messages == 1 {
+			baseline, recent = FeatureStats{Mean: x}, FeatureStats{Mean: x}
+		} else {
+			recent.update(x, fast)
+		}
+
+		// Compare before the baseline absorbs the message
+		if record.Messages > t.cfg.BaselineMessages {
+			sd := math.Max(math.Sqrt(baseline.Var), feature.MinSD)
+			z := feature.Direction * (recent.Mean - baseline.Mean) / sd
+			if z >= t.cfg.ChangeThreshold && (shift == nil || z > shift.Z) {
+				shift = &LanguageShift{Feature: feature.Name, Z: z}
+			}
+		}
+
+		if record.Messages > 1 {
+			baseline.update(x, slow)
+		}
+		record.Baseline[feature.Name], record.Recent[feature.Name] = baseline, recent
+	}
+
+	if shift == nil {
+		return nil
+	}
+	quiet := time.Duration(t.cfg.ReflagShiftAfterHours) * time.Hour
+	if record.LastShiftAt != nil && now.Sub(*record.LastShiftAt) < quiet {
+		return nil
+	}
+	record.LastShiftAt = &now
+	return shift
+}
+
+// prune forgets signals from before cutoff
+func (r *StudentSignals) prune(cutoff time.Time) {
+	kept := r.Signals[:0]
+	for _, signal := range r.Signals {
+		if signal.At.After(cutoff) {
+			kept = append(kept, signal)
+		}
+	}
+	r.Signals = kept
+}
+
+// extractFeatures measures the language features of one message
+func extractFeatures(message string) map[string]float64 {
+	words := wordPattern.FindAllString(strings.ToLower(message), -1)
+	features := map[string]float64{"length": math.Log1p(float64(len(words)))}
+	if len(words) == 0 {
+		return features
+	}
+
+	counts := map[string]int{}
+	for _, word := range words {
+		switch {
+		case negativeAffectWords[word]:
+			counts["negative_affect"]++
+		case absolutistWords[word]:
+			counts["absolutist"]++
+		}
+		if selfWords[word] {
+			counts["self_focus"]++
+		}
+	}
+	for name, count := range counts {
+		features[name] = float64(count) / float64(len(words))
+	}
+	return features
+}
+
+func wordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,126 @@
+package safeguarding
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var signalsBucket = []byte("signals")
+
+// SignalStore persists per-student signal histories
+type SignalStore interface {
+	// Get returns an empty history for students not seen before
+	Get(studentID string) (*StudentSignals, error)
+
+	Put(record *StudentSignals) error
+	Close() error
+}
+
+// MemorySignalStore keeps histories in process memory (tests and demos)
+type MemorySignalStore struct {
+	records map[string][]byte // JSON, so callers never share maps with the store
+	mu      sync.Mutex
+}
+
+// NewMemorySignalStore creates an empty in-memory store
+func NewMemorySignalStore() *MemorySignalStore {
+	return &MemorySignalStore{records: make(map[string][]byte)}
+}
+
+// Get loads a student's history
+func (s *MemorySignalStore) Get(studentID string) (*StudentSignals, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return decodeSignals(studentID, s.records[studentID])
+}
+
+// Put replaces a student's history
+func (s *MemorySignalStore) Put(record *StudentSignals) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.StudentID] = data
+	return nil
+}
+
+// Close releases the store
+func (s *MemorySignalStore) Close() error {
+	return nil
+}
+
+// BoltSignalStore keeps histories in their own BoltDB file
+type BoltSignalStore struct {
+	db *bolt.DB
+}
+
+// NewBoltSignalStore opens (or creates) the signal database
+func NewBoltSignalStore(path string) (*BoltSignalStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create signal store directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signal store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(signalsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltSignalStore{db: db}, nil
+}
+
+// Get loads a student's history
+func (s *BoltSignalStore) Get(studentID string) (*StudentSignals, error) {
+	var record *StudentSignals
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		record, err = decodeSignals(studentID, tx.Bucket(signalsBucket).Get([]byte(studentID)))
+		return err
+	})
+	return record, err
+}
+
+// Put replaces a student's history
+func (s *BoltSignalStore) Put(record *StudentSignals) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(signalsBucket).Put([]byte(record.StudentID), data)
+	})
+}
+
+// Close releases the database file lock
+func (s *BoltSignalStore) Close() error {
+	return s.db.Close()
+}
+
+func decodeSignals(studentID string, raw []byte) (*StudentSignals, error) {
+	record := &StudentSignals{StudentID: studentID}
+	if raw == nil {
+		return record, nil
+	}
+	if err := json.Unmarshal(raw, record); err != nil {
+		return nil, fmt.Errorf("corrupt signal history for %s: %w", studentID, err)
+	}
+	return record, nil
+}
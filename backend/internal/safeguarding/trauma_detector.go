@@ -1,20 +1,23 @@
 package safeguarding
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
 	"log"
-	"net/http"
+	"math"
 	"regexp"
 	"strings"
+	"time"
 )
 
+// maxSeverity is the top of the escalation matrix
+const maxSeverity = 4
+
 // TraumaDetector analyzes student input for safeguarding concerns
 type TraumaDetector struct {
-	patterns             []TraumaPattern
-	safeguardingEndpoint string
-	emergencyEndpoint    string
+	patterns []TraumaPattern
+	rules    *RuleSet
+	outbox   *Outbox
+	tuner    SeverityTuner
+	tracker  *Tracker
 }
 
 // TraumaPattern represents concerning content patterns
@@ -24,6 +27,17 @@ type TraumaPattern struct {
 	Patterns    []string `json:"patterns"`
 	Severity    int      `json:"severity"` // 1-4
 	Description string   `json:"description"`
+
+	compiled []*regexp.Regexp
+}
+
+// spans returns every place the pattern matches
+func (p TraumaPattern) spans(message string) [][]int {
+	spans := [][]int{}
+	for _, regex := range p.compiled {
+		spans = append(spans, regex.FindAllStringIndex(message, -1)...)
+	}
+	return spans
 }
 
 // TraumaResult represents detection outcome
@@ -31,176 +45,288 @@ type TraumaResult struct {
 	Detected  bool
 	Severity  int
 	Category  string
+	PatternID string
+	Intensity float64  // 0-1: one mild indicator is low, several or a severe one is high
+	Context   []string // How the match was read: negated, fiction framing...
 	Reasoning string
+	Action    string             // Escalation matrix action for this severity
+	AlertID   string             // Identifies the detection in the audit trail
+	Alert     *SafeguardingAlert // Set when the detection was escalated
 }
 
 // SafeguardingAlert sent to human team
 type SafeguardingAlert struct {
+	AlertID   string `json:"alert_id"`
 	StudentID string `json:"student_id"`
-	Timestamp string `json:"timestamp"`
+	Age       int    `json:"age"`
+	Timestamp string `json:"timestamp"` // RFC 3339, UTC
 	Severity  int    `json:"severity"`
 	Category  string `json:"category"`
-	Content   string `json:"content"`
+	PatternID string `json:"pattern_id"`
+	Content   string `json:"content"` // The student's message as written
 	Urgent    bool   `json:"urgent"`
 	Response  string `json:"response"`
 }
 
 // NewTraumaDetector loads trauma patterns from JSON
 func NewTraumaDetector(traumaPath string) (*TraumaDetector, error) {
-	// Load patterns from JSON file
-	patterns := []TraumaPattern{
-		{
-			ID:       "sexual_abuse",
-			Category: "sexual",
-			Patterns: []string{
-				"sexual act|sexual abuse|touched me|made me",
-				"inappropriate touch|uncomfortable",
-			},
-			Severity:    4,
-			Description: "Indicators of sexual abuse or harm",
-		},
-		{
-			ID:       "violence_threat",
-			Category: "violence",
-			Patterns: []string{
-				"going to hurt|going to kill|have a plan|get a weapon",
-				"tonight|tomorrow.*(hurt|kill|attack)",
-				"want to hurt|want to kill",
-			},
-			Severity:    4,
-			Description: "Immediate violence threat or plan",
-		},
-		{
-			ID:       "neglect",
-			Category: "neglect",
-			Patterns: []string{
-				"no food|haven't eaten|starving",
-				"no one cares|left alone|abandoned",
-			},
-			Severity:    3,
-			Description: "Signs of neglect or abandonment",
-		},
+	rules, err := LoadRuleSet(traumaPath)
+	if err != nil {
+		return nil, err
 	}
 
 	return &TraumaDetector{
-		patterns:             patterns,
-		safeguardingEndpoint: "http://safeguarding-team/api/alert",
-		emergencyEndpoint:    "http://emergency-services/api/report",
+		patterns: rules.Patterns,
+		rules:    rules,
 	}, nil
 }
 
-// Scan checks message for trauma indicators
-func (td *TraumaDetector) Scan(message string, age int) TraumaResult {
-	message = strings.ToLower(message)
+// UseOutbox routes escalations through a durable outbox and audit trail
+func (td *TraumaDetector) UseOutbox(outbox *Outbox) {
+	td.outbox = outbox
+}
+
+// UseTracker turns on frequency tracking and change detection across messages
+func (td *TraumaDetector) UseTracker(tracker *Tracker) {
+	td.tracker = tracker
+}
+
+// Tracking returns the schema's longitudinal tracking settings
+func (td *TraumaDetector) Tracking() TrackingConfig {
+	return td.rules.Tracking
+}
 
-	// Check each pattern
+// UseTuner lets reviewer outcomes adjust pattern severity
+// Imminent-risk (severity 4) patterns are never tuned down
+func (td *TraumaDetector) UseTuner(tuner SeverityTuner) {
+	td.tuner = tuner
+}
+
+// Scan checks a student's message for trauma indicators
+// Each match is read in context before severity is assigned; every match feeds
+// the student's history, and the most severe match, or the history when it is
+// more serious, decides the escalation
+func (td *TraumaDetector) Scan(studentID, message string, age int) TraumaResult {
+	result := TraumaResult{}
+	hits := []Signal{}
 	for _, pattern := range td.patterns {
-		for _, patternStr := range pattern.Patterns {
-			// Compile regex with case-insensitive flag
-			regex, err := regexp.Compile("(?i)" + patternStr)
-			if err != nil {
-				continue
+		severity, analysis, ok := td.assess(pattern, message, age)
+		if !ok {
+			continue
+		}
+
+		// Framed matches (stories, quotes, games) stay out of the longitudinal record
+		if !analysis.Framed() {
+			hits = append(hits, Signal{PatternID: pattern.ID, Category: pattern.Category, Severity: severity})
+		}
+
+		if severity > result.Severity {
+			result = TraumaResult{
+				Detected:  true,
+				Severity:  severity,
+				Category:  pattern.Category,
+				PatternID: pattern.ID,
+				Reasoning: pattern.Description,
+				Context:   analysis.Notes(),
 			}
+		}
+	}
+	result.Intensity = intensity(hits)
 
-			if regex.MatchString(message) {
-				// Age-calibrated severity
-				severity := td.calibrateSeverity(pattern.Severity, age, pattern.Category)
+	td.applyHistory(studentID, message, hits, &result)
+	if !result.Detected {
+		return result
+	}
 
-				result := TraumaResult{
-					Detected:  true,
-					Severity:  severity,
-					Category:  pattern.Category,
-					Reasoning: pattern.Description,
-				}
+	if level, ok := td.rules.EscalationFor(result.Severity); ok {
+		result.Action = level.Action
+	}
+	td.record(studentID, &result)
 
-				// Escalate based on severity
-				if severity >= 3 {
-					td.escalateAlert(message, age, result)
-				}
+	// Level 2 is flagged for review; level 3+ notifies the team
+	if result.Severity >= 2 {
+		td.escalateAlert(studentID, message, age, &result)
+	}
 
-				return result
-			}
+	return result
+}
+
+// Screen checks text the coach is about to send against the trauma patterns
+// Nothing is recorded or escalated; it returns the highest base severity
+// matched and that pattern's ID, or 0 when the text is clean
+func (td *TraumaDetector) Screen(text string) (int, string) {
+	severity, patternID := 0, ""
+	for _, pattern := range td.patterns {
+		if pattern.Severity > severity && len(pattern.spans(text)) > 0 {
+			severity, patternID = pattern.Severity, pattern.ID
+		}
+	}
+	return severity, patternID
+}
+
+// assess finds the most serious reading of a pattern across all its matches
+// Matching runs on the message as written; patterns are case-insensitive
+func (td *TraumaDetector) assess(pattern TraumaPattern, message string, age int) (int, ContextAnalysis, bool) {
+	best, bestAnalysis, found := 0, ContextAnalysis{}, false
+	for _, span := range pattern.spans(message) {
+		analysis := analyzeMatch(message, span[0], span[1])
+		severity := td.tune(pattern, td.calibrateSeverity(pattern.Severity, age, pattern.Category, analysis))
+		if !found || severity > best {
+			best, bestAnalysis, found = severity, analysis, true
 		}
 	}
+	return best, bestAnalysis, found
+}
 
-	return TraumaResult{
-		Detected: false,
-		Severity: 0,
+// applyHistory lets the student's accumulated signals raise the result
+func (td *TraumaDetector) applyHistory(studentID, message string, hits []Signal, result *TraumaResult) {
+	if td.tracker == nil {
+		return
+	}
+
+	assessment, err := td.tracker.Observe(studentID, message, hits)
+	if err != nil {
+		log.Printf("Failed to track safeguarding signals for %s: %v", studentID, err)
+	}
+	if assessment.Level <= result.Severity {
+		return
 	}
+
+	patternID := PatternPersistent
+	if assessment.Shift != nil && assessment.Signals < 2 {
+		patternID = PatternLanguageShift
+	}
+
+	result.Detected = true
+	result.Severity = assessment.Level
+	result.Category = assessment.Category
+	result.PatternID = patternID
+	result.Reasoning = strings.Join(assessment.Reasons, "; ")
 }
 
-// calibrateSeverity adjusts severity based on age and content
-func (td *TraumaDetector) calibrateSeverity(baseSeverity int, age int, category string) int {
+// calibrateSeverity adjusts severity for age and for how the match is framed
+// ("Same content has different significance at different ages")
+func (td *TraumaDetector) calibrateSeverity(baseSeverity int, age int, category string, ctx ContextAnalysis) int {
+	severity := baseSeverity
+
 	// Very young children (< 8): lower threshold for escalation
-	if age < 8 {
-		if baseSeverity >= 2 {
-			return baseSeverity + 1 // Escalate one level for very young
+	if age < 8 && baseSeverity >= 2 {
+		severity = min(baseSeverity+1, maxSeverity) // Escalate one level for very young
+	}
+
+	// A student talking about their own life is never explained away by
+	// negation or framing, but an explicit game or sport still counts
+	discount := 0
+	if !ctx.Disclosure {
+		if ctx.Negated {
+			discount = negationDiscount
+			if baseSeverity >= maxSeverity {
+				discount = min(discount, severity-negatedFloor) // Still reaches the team
+			}
+		}
+
+		if ctx.Quoted || ctx.Fiction {
+			discount = max(discount, framingDiscount)
+		}
+
+		// Adolescent inappropriate humour vs. trauma indicators
+		if ctx.Joking && age >= 14 && (category == "violence" || category == "sexual") {
+			discount++
 		}
 	}
 
-	// Teenagers: may have different context
-	if age >= 14 && category == "violence" {
-		// Check if potentially joking/gaming reference
-		// For now, keep same severity
+	// Game violence is normal range; younger children blur game and real life more
+	if ctx.Gaming && category == "violence" {
+		discount = max(discount, gamingDiscount(age))
 	}
 
-	return baseSeverity
+	return max(1, severity-discount)
+}
+
+// gamingDiscount is how far a gaming reference lowers violent content by age
+func gamingDiscount(age int) int {
+	switch {
+	case age >= 13:
+		return 3
+	case age >= 8:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// tune applies reviewer feedback, keeping severity within 1-4
+func (td *TraumaDetector) tune(pattern TraumaPattern, severity int) int {
+	if td.tuner == nil || pattern.Severity >= maxSeverity {
+		return severity
+	}
+	return max(1, min(severity+td.tuner.Adjustment(pattern.ID), maxSeverity))
+}
+
+// record writes the detection to the audit trail and assigns its alert ID
+func (td *TraumaDetector) record(studentID string, result *TraumaResult) {
+	result.AlertID = newID()
+	if td.outbox == nil {
+		return
+	}
+
+	td.outbox.Record(AuditEntry{
+		Event:     AuditDetected,
+		AlertID:   result.AlertID,
+		StudentID: studentID,
+		Severity:  result.Severity,
+		Category:  result.Category,
+		Detail:    auditDetail(result),
+	})
 }
 
-// escalateAlert sends alert to safeguarding team
-func (td *TraumaDetector) escalateAlert(message string, age int, result TraumaResult) {
+// escalateAlert raises an alert for review; from severity 3 it is queued for
+// the safeguarding team, and at severity 4 for emergency services too
+func (td *TraumaDetector) escalateAlert(studentID, message string, age int, result *TraumaResult) {
 	alert := SafeguardingAlert{
-		Timestamp: fmt.Sprintf("%d", getCurrentTimestamp()),
+		AlertID:   result.AlertID,
+		StudentID: studentID,
+		Age:       age,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Severity:  result.Severity,
 		Category:  result.Category,
-		Content:   truncateContent(message),
+		PatternID: result.PatternID,
+		Content:   message,
 		Urgent:    result.Severity >= 3,
 		Response:  td.generateSafeguardingResponse(result.Severity),
 	}
 
-	// Send to safeguarding endpoint (non-blocking)
-	go func() {
-		data, _ := json.Marshal(alert)
-		resp, err := http.Post(
-			td.safeguardingEndpoint,
-			"application/json",
-			bytes.NewBuffer(data),
-		)
-		if err != nil {
-			log.Printf("Failed to alert safeguarding team: %v", err)
-		} else {
-			resp.Body.Close()
-		}
-	}()
-
-	// Severity 4 = emergency services
-	if result.Severity == 4 {
-		td.alertEmergencyServices(alert, age)
-	}
-}
-
-// alertEmergencyServices escalates to emergency services
-func (td *TraumaDetector) alertEmergencyServices(alert SafeguardingAlert, age int) {
-	log.Printf("🚨 EMERGENCY ALERT - Severity 4 trauma indicator detected for age %d", age)
-	log.Printf("Category: %s | Content: %s", alert.Category, alert.Content)
-
-	// In production: Actual emergency services integration
-	// For now: Log for manual review
-	go func() {
-		data, _ := json.Marshal(alert)
-		_, err := http.Post(
-			td.emergencyEndpoint,
-			"application/json",
-			bytes.NewBuffer(data),
-		)
-		if err != nil {
-			log.Printf("Failed to alert emergency services: %v", err)
+	result.Alert = &alert
+	if result.Severity < 3 {
+		return
+	}
+
+	destinations := []string{DestinationSafeguarding}
+	if result.Severity >= maxSeverity {
+		log.Printf("🚨 EMERGENCY ALERT - Severity %d trauma indicator detected for student %s (age %d)", result.Severity, studentID, age)
+		log.Printf("Category: %s | Content: %s", alert.Category, truncateContent(alert.Content))
+		destinations = append(destinations, DestinationEmergency)
+	}
+
+	if td.outbox == nil {
+		log.Printf("🚨 NO SAFEGUARDING OUTBOX CONFIGURED - alert %s for student %s (severity %d, %s) was NOT sent; manual follow-up required",
+			alert.AlertID, studentID, alert.Severity, alert.Category)
+		return
+	}
+
+	for _, destination := range destinations {
+		if err := td.outbox.Enqueue(alert, destination); err != nil {
+			log.Printf("🚨 SAFEGUARDING ALERT NOT QUEUED - manual follow-up required: %v", err)
 		}
-	}()
+	}
 }
 
 // generateSafeguardingResponse creates age-appropriate response
 func (td *TraumaDetector) generateSafeguardingResponse(severity int) string {
+	if message, ok := td.rules.ResponseFor(severity); ok {
+		return message
+	}
+
 	if severity >= 4 {
 		return "I'm very concerned about what you've shared. Your safety is the most important thing. I'm connecting you with someone who can help right now."
 	}
@@ -213,14 +339,25 @@ func (td *TraumaDetector) generateSafeguardingResponse(severity int) string {
 }
 
 // Helper functions
+func auditDetail(result *TraumaResult) string {
+	detail := result.PatternID + ": " + result.Action
+	if len(result.Context) > 0 {
+		detail += " [" + strings.Join(result.Context, ", ") + "]"
+	}
+	return detail
+}
+
+func intensity(hits []Signal) float64 {
+	total := 0
+	for _, hit := range hits {
+		total += hit.Severity
+	}
+	return math.Min(1, float64(total)/maxSeverity)
+}
+
 func truncateContent(s string) string {
 	if len(s) > 200 {
 		return s[:200] + "..."
 	}
 	return s
 }
-
-func getCurrentTimestamp() int64 {
-	// Return current Unix timestamp
-	return 0 // Placeholder
-}
@@ -0,0 +1,91 @@
+package safeguarding
+
+import "testing"
+
+const traumaSchemaPath = "../../../shared/schemas/trauma_detection.json"
+
+func TestScanEscalation(t *testing.T) {
+	td, err := NewTraumaDetector(traumaSchemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// min 3 and above reach the safeguarding team; max 1 is monitoring only
+	cases := []struct {
+		name     string
+		message  string
+		age      int
+		min, max int
+	}{
+		// Must escalate
+		{"secret disclosure", "I can't tell anyone he touched me", 12, 3, 4},
+		{"denied telling", "I never said he touched me", 12, 3, 4},
+		{"ambivalent suicidal thought", "I don't want to kill myself", 12, 3, 4},
+		{"better off without me", "everyone would be better off without me", 12, 4, 4},
+		{"parent hits student", "my dad hits me", 12, 3, 4},
+		{"grooming secret", "my stepdad made me keep it a secret", 12, 4, 4},
+		{"adult makes student uncomfortable", "my uncle makes me feel uncomfortable", 12, 4, 4},
+		{"uncomfortable touching", "it was uncomfortable when he touched my leg", 12, 4, 4},
+		{"planned violence", "I'm going to hurt him tomorrow", 15, 4, 4},
+		{"negated threat stays with the team", "I'm not going to hurt anyone", 12, 3, 3},
+		{"negation inside a disclosure", "my dad didn't mean it when he hit me with a belt at home", 12, 3, 4},
+		{"quoted threat at home", `my mum said "I'm going to kill you" last night`, 12, 4, 4},
+		{"very young child", "there are fights at home", 6, 3, 3},
+		{"no food at home", "there's no food at home again", 12, 3, 3},
+		{"not eaten for a day", "I haven't eaten since yesterday", 12, 3, 3},
+
+		// Must not escalate
+		{"story villain", "In my story the villain says he is going to kill the king", 12, 1, 1},
+		{"quoted character", `"I want to kill you" is what the character shouts in chapter 3`, 12, 1, 1},
+		{"gaming", "going to kill the creepers next in minecraft", 14, 1, 1},
+		{"gaming at the weekend", "I'm going to kill the zombies this weekend in minecraft", 14, 1, 1},
+		{"sport tomorrow", "I'm going to kill it at football tomorrow", 14, 1, 1},
+		{"homework plan", "I have a plan for tomorrow's homework", 12, 0, 0},
+		{"hungry before lunch", "I'm starving, can we finish early", 12, 0, 1},
+		{"lunch not yet", "I haven't eaten lunch yet", 12, 0, 1},
+		{"ordinary question", "Can we do fractions today?", 12, 0, 0},
+		{"better off with a calculator", "they would be better off with a calculator", 12, 0, 0},
+		{"uncomfortable with maths", "Maths makes me uncomfortable", 12, 0, 1},
+		{"uncomfortable with noise", "the noise makes me uncomfortable", 12, 0, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := td.Scan("student-1", tc.message, tc.age)
+			if result.Severity < tc.min || result.Severity > tc.max {
+				t.Errorf("Scan(%q, age %d) severity = %d (%s, %v), want %d-%d",
+					tc.message, tc.age, result.Severity, result.PatternID, result.Context, tc.min, tc.max)
+			}
+		})
+	}
+}
+
+func TestScanFilesUnderTheRightIndicator(t *testing.T) {
+	td, err := NewTraumaDetector(traumaSchemaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		message   string
+		patternID string
+		category  string
+	}{
+		{"I want to kill myself", "suicidal_ideation", "self_harm"},
+		{"I'm going to hurt myself tonight", "suicidal_ideation", "self_harm"},
+		{"tomorrow I'm going to kill myself", "suicidal_ideation", "self_harm"},
+		{"I want to kill him", "violent_ideation", "violence"},
+		{"I'm going to hurt my brother", "violent_ideation", "violence"},
+		{"my dad hits me", "physical_abuse_indicators", "physical_abuse"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.message, func(t *testing.T) {
+			result := td.Scan("student-1", tc.message, 12)
+			if result.PatternID != tc.patternID || result.Category != tc.category {
+				t.Errorf("Scan(%q) = %s/%s, want %s/%s",
+					tc.message, result.PatternID, result.Category, tc.patternID, tc.category)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	profilesBucket = []byte("profiles")
+	metaBucket     = []byte("meta")
+	schemaKey      = []byte("schema_version")
+)
+
+// BoltRepository stores profiles in an embedded BoltDB file
+// Bolt serialises write transactions, so Update is safe across goroutines
+type BoltRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltRepository opens (or creates) the database and runs migrations
+func NewBoltRepository(path string) (*BoltRepository, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create profile store directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open profile store: %w", err)
+	}
+
+	repo := &BoltRepository{db: db}
+	if err := repo.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// migrate creates buckets and upgrades every stored profile
+func (r *BoltRepository) migrate() error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		profiles, err := tx.CreateBucketIfNotExists(profilesBucket)
+		if err != nil {
+			return err
+		}
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		stored := 0
+		if raw := meta.Get(schemaKey); raw != nil {
+			stored, _ = strconv.Atoi(string(raw))
+		}
+		if stored > CurrentSchemaVersion {
+			return fmt.Errorf("profile store schema version %d is newer than supported %d", stored, CurrentSchemaVersion)
+		}
+		if stored == CurrentSchemaVersion {
+			return nil
+		}
+
+		// Collect first: bolt forbids writing while iterating with a cursor
+		upgraded := map[string][]byte{}
+		err = profiles.ForEach(func(key, value []byte) error {
+			var profile StudentProfile
+			if err := json.Unmarshal(value, &profile); err != nil {
+				return fmt.Errorf("corrupt profile %s: %w", key, err)
+			}
+			changed, err := migrateProfile(&profile)
+			if err != nil {
+				return err
+			}
+			if changed {
+				data, err := json.Marshal(&profile)
+				if err != nil {
+					return err
+				}
+				upgraded[string(key)] = data
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for key, data := range upgraded {
+			if err := profiles.Put([]byte(key), data); err != nil {
+				return err
+			}
+		}
+
+		return meta.Put(schemaKey, []byte(strconv.Itoa(CurrentSchemaVersion)))
+	})
+}
+
+// Get loads a profile by student ID
+func (r *BoltRepository) Get(ctx context.Context, studentID string) (*StudentProfile, error) {
+	var profile *StudentProfile
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(profilesBucket).Get([]byte(studentID))
+		if raw == nil {
+			return ErrNotFound
+		}
+		profile = &StudentProfile{}
+		return json.Unmarshal(raw, profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// Update runs fn inside a single write transaction
+func (r *BoltRepository) Update(
+	ctx context.Context,
+	studentID string,
+	base *StudentProfile,
+	fn UpdateFunc,
+) (*StudentProfile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var profile *StudentProfile
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(profilesBucket)
+
+		if raw := bucket.Get([]byte(studentID)); raw != nil {
+			profile = &StudentProfile{}
+			if err := json.Unmarshal(raw, profile); err != nil {
+				return fmt.Errorf("corrupt profile %s: %w", studentID, err)
+			}
+			if _, err := migrateProfile(profile); err != nil {
+				return err
+			}
+		} else {
+			profile = startingProfile(studentID, base)
+		}
+
+		if err := fn(profile); err != nil {
+			return err
+		}
+		profile.UpdatedAt = time.Now().UTC()
+
+		data, err := json.Marshal(profile)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(studentID), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// Delete removes a profile
+func (r *BoltRepository) Delete(ctx context.Context, studentID string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(profilesBucket).Delete([]byte(studentID))
+	})
+}
+
+// Close releases the database file lock
+func (r *BoltRepository) Close() error {
+	return r.db.Close()
+}
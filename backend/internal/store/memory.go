@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// MemoryRepository keeps profiles in process memory (tests and demos)
+type MemoryRepository struct {
+	profiles map[string]StudentProfile
+	mu       sync.Mutex
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		profiles: make(map[string]StudentProfile),
+	}
+}
+
+// Get returns a copy of the stored profile
+func (r *MemoryRepository) Get(ctx context.Context, studentID string) (*StudentProfile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	profile, ok := r.profiles[studentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneProfile(&profile), nil
+}
+
+// Update applies fn while holding the repository lock
+func (r *MemoryRepository) Update(
+	ctx context.Context,
+	studentID string,
+	base *StudentProfile,
+	fn UpdateFunc,
+) (*StudentProfile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var profile *StudentProfile
+	if existing, ok := r.profiles[studentID]; ok {
+		profile = cloneProfile(&existing)
+	} else {
+		profile = startingProfile(studentID, base)
+	}
+
+	if err := fn(profile); err != nil {
+		return nil, err
+	}
+	profile.UpdatedAt = time.Now().UTC()
+
+	r.profiles[studentID] = *cloneProfile(profile)
+	return profile, nil
+}
+
+// Delete removes a profile
+func (r *MemoryRepository) Delete(ctx context.Context, studentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.profiles, studentID)
+	return nil
+}
+
+// Close is a no-op for the in-memory repository
+func (r *MemoryRepository) Close() error {
+	return nil
+}
+
+// startingProfile copies base, or builds a default, for a first write
+func startingProfile(studentID string, base *StudentProfile) *StudentProfile {
+	if base == nil {
+		return NewStudentProfile(studentID, 0)
+	}
+	profile := cloneProfile(base)
+	profile.StudentID = studentID
+	profile.SchemaVersion = CurrentSchemaVersion
+	if profile.CreatedAt.IsZero() {
+		profile.CreatedAt = time.Now().UTC()
+	}
+	if profile.ActiveBarriers == nil {
+		profile.ActiveBarriers = []string{}
+	}
+	if profile.PlayBreak.StudentID == "" {
+		profile.PlayBreak = newPlayBreakProfile(studentID, etp.ParsePlayBreakStage(profile.PlayBreakStage), profile.CreatedAt)
+	}
+	if profile.Mastery.Topics == nil {
+		profile.Mastery = newMasteryProfile()
+	}
+	return profile
+}
+
+// cloneProfile deep-copies a profile so callers never share slices or maps
+func cloneProfile(profile *StudentProfile) *StudentProfile {
+	clone := *profile
+	clone.ActiveBarriers = append([]string{}, profile.ActiveBarriers...)
+	clone.PlayBreak.RecentOutcomes = append([]bool{}, profile.PlayBreak.RecentOutcomes...)
+	clone.PlayBreak.PacingBarriers = append([]string{}, profile.PlayBreak.PacingBarriers...)
+	clone.Mastery.Topics = make(map[string]etp.TopicMastery, len(profile.Mastery.Topics))
+	for key, topic := range profile.Mastery.Topics {
+		clone.Mastery.Topics[key] = topic
+	}
+	return &clone
+}
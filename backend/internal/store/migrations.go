@@ -0,0 +1,83 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// CurrentSchemaVersion is the profile layout this build writes
+const CurrentSchemaVersion = 3
+
+// migration upgrades a profile from Version-1 to Version
+type migration struct {
+	Version     int
+	Description string
+	Apply       func(profile *StudentProfile) error
+}
+
+// migrations run in order; append new entries and bump CurrentSchemaVersion
+var migrations = []migration{
+	{
+		Version:     1,
+		Description: "backfill fields missing from in-memory MVP profiles",
+		Apply: func(profile *StudentProfile) error {
+			if profile.ActiveBarriers == nil {
+				profile.ActiveBarriers = []string{}
+			}
+			if profile.PlayBreakStage == "" {
+				profile.PlayBreakStage = "level_1"
+			}
+			if profile.CreatedAt.IsZero() {
+				profile.CreatedAt = time.Now().UTC()
+			}
+			return nil
+		},
+	},
+	{
+		Version:     2,
+		Description: "add play break progression state from the stage name",
+		Apply: func(profile *StudentProfile) error {
+			stage := etp.ParsePlayBreakStage(profile.PlayBreakStage)
+			profile.PlayBreak = newPlayBreakProfile(profile.StudentID, stage, profile.CreatedAt)
+			profile.PlayBreak.RewardsEarned = profile.RewardsEarned
+			profile.PlayBreakStage = stage.Level()
+			return nil
+		},
+	},
+	{
+		Version:     3,
+		Description: "add per-topic mastery estimates",
+		Apply: func(profile *StudentProfile) error {
+			if profile.Mastery.Topics == nil {
+				profile.Mastery = newMasteryProfile()
+			}
+			return nil
+		},
+	},
+}
+
+// migrateProfile brings a stored profile up to CurrentSchemaVersion
+// It reports whether anything changed so callers can write it back
+func migrateProfile(profile *StudentProfile) (bool, error) {
+	if profile.SchemaVersion > CurrentSchemaVersion {
+		return false, fmt.Errorf("profile %s has schema version %d, newer than supported %d",
+			profile.StudentID, profile.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	changed := false
+	for _, m := range migrations {
+		if profile.SchemaVersion >= m.Version {
+			continue
+		}
+		if err := m.Apply(profile); err != nil {
+			return changed, fmt.Errorf("migration %d (%s) failed for %s: %w",
+				m.Version, m.Description, profile.StudentID, err)
+		}
+		profile.SchemaVersion = m.Version
+		changed = true
+	}
+
+	return changed, nil
+}
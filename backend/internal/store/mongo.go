@@ -0,0 +1,360 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/mike5tew/humanos/internal/etp"
+	"github.com/mike5tew/humanos/internal/mastery"
+)
+
+const (
+	profilesCollection = "student_profiles"
+
+	// Optimistic-concurrency retries before giving up on a busy profile
+	maxUpdateAttempts = 5
+)
+
+// ErrConflict is returned when concurrent writers keep beating an update
+var ErrConflict = errors.New("profile update conflict")
+
+// profileDocument is the stored document, keyed by _id and camelCased like
+// the other documents in mongo_personalized.json
+type profileDocument struct {
+	ID              string             `bson:"_id"`
+	Age             int                `bson:"age"`
+	BrainState      brainStateDocument `bson:"brainState"`
+	ActiveBarriers  []string           `bson:"activeBarriers"`
+	RewardsEarned   int                `bson:"rewardsEarned"`
+	PlayBreakStage  string             `bson:"playBreakStage"`
+	LastInteraction string             `bson:"lastInteraction"`
+	PlayBreak       playBreakDocument  `bson:"playBreak"`
+	Mastery         []masteryDocument  `bson:"mastery"`
+	SchemaVersion   int                `bson:"schemaVersion"`
+	Revision        int64              `bson:"revision"`
+	CreatedAt       time.Time          `bson:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt"`
+}
+
+type brainStateDocument struct {
+	PrimalLevel    float64 `bson:"primalLevel"`
+	EmotionalLevel float64 `bson:"emotionalLevel"`
+	RationalLevel  float64 `bson:"rationalLevel"`
+	OverrideRisk   float64 `bson:"overrideRisk"`
+}
+
+type playBreakDocument struct {
+	CurrentStage    int                  `bson:"currentStage"`
+	TimeInStage     int                  `bson:"timeInStage"`
+	SuccessRate     float64              `bson:"successRate"`
+	WorkDuration    int                  `bson:"workDuration"`
+	RewardsEarned   int                  `bson:"rewardsEarned"`
+	LastProgression time.Time            `bson:"lastProgression"`
+	ReadyToAdvance  bool                 `bson:"readyToAdvance"`
+	RecentOutcomes  []bool               `bson:"recentOutcomes"`
+	PacingBarriers  []string             `bson:"pacingBarriers"`
+	ReadyStreak     int                  `bson:"readyStreak"`
+	Regressions     int                  `bson:"regressions"`
+	Requirements    requirementsDocument `bson:"requirements"`
+}
+
+type requirementsDocument struct {
+	MinDaysInStage  int     `bson:"minDaysInStage"`
+	MinSuccessRate  float64 `bson:"minSuccessRate"`
+	MinWorkDuration int     `bson:"minWorkDuration"`
+	RegressBelow    float64 `bson:"regressBelow"`
+	PacedBy         string  `bson:"pacedBy,omitempty"`
+}
+
+// masteryDocument is one topic's estimate; topics are stored as an array
+// because topic names may contain characters not allowed in field names
+type masteryDocument struct {
+	Topic        string    `bson:"topic"`
+	Level        float64   `bson:"level"`
+	Observations int       `bson:"observations"`
+	Successes    int       `bson:"successes"`
+	LastSeen     time.Time `bson:"lastSeen"`
+}
+
+// MongoRepository stores profiles in MongoDB
+// Updates use a revision counter so concurrent writers never lose changes
+type MongoRepository struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewMongoRepository connects, verifies the server and runs migrations
+func NewMongoRepository(ctx context.Context, uri, database string) (*MongoRepository, error) {
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	if err := client.Ping(connectCtx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("MongoDB unreachable: %w", err)
+	}
+
+	repo := &MongoRepository{
+		client:     client,
+		collection: client.Database(database).Collection(profilesCollection),
+	}
+	if err := repo.migrate(ctx); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// migrate upgrades documents written by older schema versions
+func (r *MongoRepository) migrate(ctx context.Context) error {
+	cursor, err := r.collection.Find(ctx, bson.M{"schemaVersion": bson.M{"$lt": CurrentSchemaVersion}})
+	if err != nil {
+		return fmt.Errorf("failed to scan profiles for migration: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc profileDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("corrupt profile document: %w", err)
+		}
+		_, err := r.Update(ctx, doc.ID, nil, func(*StudentProfile) error { return nil })
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// Get loads a profile by student ID
+func (r *MongoRepository) Get(ctx context.Context, studentID string) (*StudentProfile, error) {
+	doc, err := r.find(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := doc.toProfile()
+	if _, err := migrateProfile(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Update applies fn and writes back only if nobody else wrote in between
+func (r *MongoRepository) Update(
+	ctx context.Context,
+	studentID string,
+	base *StudentProfile,
+	fn UpdateFunc,
+) (*StudentProfile, error) {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		doc, err := r.find(ctx, studentID)
+
+		switch {
+		case errors.Is(err, ErrNotFound):
+			profile := startingProfile(studentID, base)
+			if err := fn(profile); err != nil {
+				return nil, err
+			}
+			profile.UpdatedAt = time.Now().UTC()
+
+			_, err := r.collection.InsertOne(ctx, newProfileDocument(profile, 1))
+			if mongo.IsDuplicateKeyError(err) {
+				continue // Another writer created it first
+			}
+			if err != nil {
+				return nil, err
+			}
+			return profile, nil
+
+		case err != nil:
+			return nil, err
+		}
+
+		profile := doc.toProfile()
+		if _, err := migrateProfile(profile); err != nil {
+			return nil, err
+		}
+		if err := fn(profile); err != nil {
+			return nil, err
+		}
+		profile.UpdatedAt = time.Now().UTC()
+
+		result, err := r.collection.ReplaceOne(ctx,
+			bson.M{"_id": studentID, "revision": doc.Revision},
+			newProfileDocument(profile, doc.Revision+1),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if result.MatchedCount == 1 {
+			return profile, nil
+		}
+		// Revision moved on: reload and reapply
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrConflict, studentID)
+}
+
+// Delete removes a profile
+func (r *MongoRepository) Delete(ctx context.Context, studentID string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": studentID})
+	return err
+}
+
+// Close disconnects from MongoDB
+func (r *MongoRepository) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return r.client.Disconnect(ctx)
+}
+
+func (r *MongoRepository) find(ctx context.Context, studentID string) (*profileDocument, error) {
+	var doc profileDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": studentID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func newProfileDocument(profile *StudentProfile, revision int64) profileDocument {
+	return profileDocument{
+		ID:  profile.StudentID,
+		Age: profile.Age,
+		BrainState: brainStateDocument{
+			PrimalLevel:    profile.BrainState.PrimalLevel,
+			EmotionalLevel: profile.BrainState.EmotionalLevel,
+			RationalLevel:  profile.BrainState.RationalLevel,
+			OverrideRisk:   profile.BrainState.OverrideRisk,
+		},
+		ActiveBarriers:  profile.ActiveBarriers,
+		RewardsEarned:   profile.RewardsEarned,
+		PlayBreakStage:  profile.PlayBreakStage,
+		LastInteraction: profile.LastInteraction,
+		PlayBreak:       newPlayBreakDocument(profile.PlayBreak),
+		Mastery:         newMasteryDocuments(profile.Mastery),
+		SchemaVersion:   profile.SchemaVersion,
+		Revision:        revision,
+		CreatedAt:       profile.CreatedAt,
+		UpdatedAt:       profile.UpdatedAt,
+	}
+}
+
+func (doc *profileDocument) toProfile() *StudentProfile {
+	return &StudentProfile{
+		StudentID: doc.ID,
+		Age:       doc.Age,
+		BrainState: etp.BrainState{
+			PrimalLevel:    doc.BrainState.PrimalLevel,
+			EmotionalLevel: doc.BrainState.EmotionalLevel,
+			RationalLevel:  doc.BrainState.RationalLevel,
+			OverrideRisk:   doc.BrainState.OverrideRisk,
+		},
+		ActiveBarriers:  doc.ActiveBarriers,
+		RewardsEarned:   doc.RewardsEarned,
+		PlayBreakStage:  doc.PlayBreakStage,
+		LastInteraction: doc.LastInteraction,
+		PlayBreak:       doc.PlayBreak.toProfile(doc.ID),
+		Mastery:         masteryFromDocuments(doc.Mastery),
+		SchemaVersion:   doc.SchemaVersion,
+		CreatedAt:       doc.CreatedAt,
+		UpdatedAt:       doc.UpdatedAt,
+	}
+}
+
+func newPlayBreakDocument(pb etp.PlayBreakProfile) playBreakDocument {
+	return playBreakDocument{
+		CurrentStage:    int(pb.CurrentStage),
+		TimeInStage:     pb.TimeInStage,
+		SuccessRate:     pb.SuccessRate,
+		WorkDuration:    pb.WorkDuration,
+		RewardsEarned:   pb.RewardsEarned,
+		LastProgression: pb.LastProgression,
+		ReadyToAdvance:  pb.ReadyToAdvance,
+		RecentOutcomes:  pb.RecentOutcomes,
+		PacingBarriers:  pb.PacingBarriers,
+		ReadyStreak:     pb.ReadyStreak,
+		Regressions:     pb.Regressions,
+		Requirements: requirementsDocument{
+			MinDaysInStage:  pb.Requirements.MinDaysInStage,
+			MinSuccessRate:  pb.Requirements.MinSuccessRate,
+			MinWorkDuration: pb.Requirements.MinWorkDuration,
+			RegressBelow:    pb.Requirements.RegressBelow,
+			PacedBy:         pb.Requirements.PacedBy,
+		},
+	}
+}
+
+func (doc *playBreakDocument) toProfile(studentID string) etp.PlayBreakProfile {
+	return etp.PlayBreakProfile{
+		StudentID:       studentID,
+		CurrentStage:    etp.PlayBreakStage(doc.CurrentStage),
+		TimeInStage:     doc.TimeInStage,
+		SuccessRate:     doc.SuccessRate,
+		WorkDuration:    doc.WorkDuration,
+		RewardsEarned:   doc.RewardsEarned,
+		LastProgression: doc.LastProgression,
+		ReadyToAdvance:  doc.ReadyToAdvance,
+		RecentOutcomes:  doc.RecentOutcomes,
+		PacingBarriers:  doc.PacingBarriers,
+		ReadyStreak:     doc.ReadyStreak,
+		Regressions:     doc.Regressions,
+		Requirements: etp.StageRequirements{
+			MinDaysInStage:  doc.Requirements.MinDaysInStage,
+			MinSuccessRate:  doc.Requirements.MinSuccessRate,
+			MinWorkDuration: doc.Requirements.MinWorkDuration,
+			RegressBelow:    doc.Requirements.RegressBelow,
+			PacedBy:         doc.Requirements.PacedBy,
+		},
+	}
+}
+
+func newMasteryDocuments(profile etp.MasteryProfile) []masteryDocument {
+	docs := make([]masteryDocument, 0, len(profile.Topics))
+	for _, topic := range profile.Topics {
+		docs = append(docs, masteryDocument{
+			Topic:        topic.Topic,
+			Level:        topic.Level,
+			Observations: topic.Observations,
+			Successes:    topic.Successes,
+			LastSeen:     topic.LastSeen,
+		})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Topic < docs[j].Topic })
+	return docs
+}
+
+// masteryFromDocuments returns nil for documents written before schema 3,
+// so the migration fills it in
+func masteryFromDocuments(docs []masteryDocument) etp.MasteryProfile {
+	if docs == nil {
+		return etp.MasteryProfile{}
+	}
+	profile := newMasteryProfile()
+	for _, doc := range docs {
+		profile.Topics[mastery.TopicKey(doc.Topic)] = etp.TopicMastery{
+			Topic:        doc.Topic,
+			Level:        doc.Level,
+			Observations: doc.Observations,
+			Successes:    doc.Successes,
+			LastSeen:     doc.LastSeen,
+		}
+	}
+	return profile
+}
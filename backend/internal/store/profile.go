@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mike5tew/humanos/internal/etp"
+)
+
+// ErrNotFound is returned when a student has no stored profile
+var ErrNotFound = errors.New("profile not found")
+
+// StudentProfile represents a student's current state
+type StudentProfile struct {
+	StudentID       string         `json:"student_id"`
+	Age             int            `json:"age"`
+	BrainState      etp.BrainState `json:"brain_state"`
+	ActiveBarriers  []string       `json:"active_barriers"`
+	RewardsEarned   int            `json:"rewards_earned"`
+	PlayBreakStage  string         `json:"play_break_stage"`
+	LastInteraction string         `json:"last_interaction"`
+
+	// PlayBreak is the progression state; PlayBreakStage mirrors its stage
+	PlayBreak etp.PlayBreakProfile `json:"play_break"`
+
+	// Mastery is the per-topic level sent to CHISG and used to pitch questions
+	Mastery etp.MasteryProfile `json:"mastery"`
+
+	SchemaVersion int       `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewStudentProfile returns the starting profile for a new student
+func NewStudentProfile(studentID string, age int) *StudentProfile {
+	now := time.Now().UTC()
+	return &StudentProfile{
+		StudentID:      studentID,
+		Age:            age,
+		PlayBreakStage: etp.ConcentrationStage.Level(),
+		RewardsEarned:  0,
+		ActiveBarriers: []string{},
+		PlayBreak:      newPlayBreakProfile(studentID, etp.ConcentrationStage, now),
+		Mastery:        newMasteryProfile(),
+		SchemaVersion:  CurrentSchemaVersion,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+func newPlayBreakProfile(studentID string, stage etp.PlayBreakStage, since time.Time) etp.PlayBreakProfile {
+	return etp.PlayBreakProfile{
+		StudentID:       studentID,
+		CurrentStage:    stage,
+		LastProgression: since,
+		RecentOutcomes:  []bool{},
+		PacingBarriers:  []string{},
+	}
+}
+
+func newMasteryProfile() etp.MasteryProfile {
+	return etp.MasteryProfile{Topics: map[string]etp.TopicMastery{}}
+}
+
+// UpdateFunc mutates a profile inside an atomic read-modify-write
+// Returning an error aborts the update and leaves the stored profile untouched
+type UpdateFunc func(profile *StudentProfile) error
+
+// StudentProfileRepository persists student profiles
+type StudentProfileRepository interface {
+	// Get returns ErrNotFound when the student has no profile yet
+	Get(ctx context.Context, studentID string) (*StudentProfile, error)
+
+	// Update applies fn atomically, creating the profile from base when missing
+	Update(ctx context.Context, studentID string, base *StudentProfile, fn UpdateFunc) (*StudentProfile, error)
+
+	// Delete removes a profile; deleting a missing profile is not an error
+	Delete(ctx context.Context, studentID string) error
+
+	Close() error
+}
+
+// Config selects and configures a repository backend
+type Config struct {
+	Backend       string // "bolt" (default), "mongo" or "memory"
+	BoltPath      string
+	MongoURI      string
+	MongoDatabase string
+}
+
+// ConfigFromEnv reads repository settings from the environment
+func ConfigFromEnv() Config {
+	return Config{
+		Backend:       getEnvOrDefault("PROFILE_STORE", "bolt"),
+		BoltPath:      getEnvOrDefault("PROFILE_DB_PATH", "data/profiles.db"),
+		MongoURI:      getEnvOrDefault("MONGODB_URI", "mongodb://localhost:27017"),
+		MongoDatabase: getEnvOrDefault("MONGODB_DATABASE", "humanos"),
+	}
+}
+
+// Open creates the configured repository and runs pending migrations
+func Open(ctx context.Context, cfg Config) (StudentProfileRepository, error) {
+	switch cfg.Backend {
+	case "", "bolt":
+		return NewBoltRepository(cfg.BoltPath)
+	case "mongo":
+		return NewMongoRepository(ctx, cfg.MongoURI, cfg.MongoDatabase)
+	case "memory":
+		return NewMemoryRepository(), nil
+	default:
+		return nil, fmt.Errorf("unknown profile store %q", cfg.Backend)
+	}
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
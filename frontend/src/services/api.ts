@@ -1,4 +1,4 @@
-import type { StudentContext, CoachResponse } from '../types.d';
+import type { StudentContext, CoachResponse, AgenticCoachResponse } from '../types.d';
 
 export interface StudentProfile {
   student_id: string;
@@ -12,6 +12,25 @@ export interface StudentProfile {
   rewards_earned: number;
   play_break_stage: string;
   last_interaction: string;
+  play_break: PlayBreakProgress;
+}
+
+export interface PlayBreakProgress {
+  current_stage: number; // 0 concentration … 3 praise only
+  time_in_stage: number; // Days
+  success_rate: number;
+  work_duration: number; // Minutes
+  rewards_earned: number;
+  last_progression: string;
+  ready_to_advance: boolean;
+  regressions: number;
+  requirements: {
+    min_days_in_stage: number;
+    min_success_rate: number;
+    min_work_duration: number;
+    regress_below: number;
+    paced_by?: string;
+  };
 }
 
 export class CoachAPI {
@@ -43,6 +62,33 @@ export class CoachAPI {
     return response.json();
   }
 
+  // Same coach, plus CHISG knowledge context for the topic in the message;
+  // check knowledge_status before relying on knowledge_context (safeguarding
+  // replies have no knowledge_status)
+  async sendAgenticMessage(
+    studentId: string,
+    message: string,
+    context: StudentContext,
+    sessionId?: string
+  ): Promise<AgenticCoachResponse> {
+    const response = await fetch(`${this.baseURL}/api/coach/agentic/message`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({
+        student_id: studentId,
+        session_id: sessionId,
+        message,
+        context,
+      }),
+    });
+
+    if (!response.ok) {
+      throw new Error(`API error: ${response.status}`);
+    }
+
+    return response.json();
+  }
+
   async getStudentProfile(studentId: string): Promise<StudentProfile> {
     // Call Go backend to get student profile
     const response = await fetch(
@@ -1,9 +1,24 @@
+// Server events carry a `seq` when they can be replayed after a reconnect
+export interface ServerEvent {
+  type: 'connected' | 'resumed' | 'coach_response' | 'nudge' | 'pong' | 'error';
+  seq?: number;
+  data?: any;
+  timestamp: string;
+}
+
 export class WebSocketService {
   private ws: WebSocket | null = null;
   private reconnectTimeout: number = 1000;
+  private lastSeq: number = 0;
+  private closedByUser = false;
+  private listeners: Array<(event: ServerEvent) => void> = [];
 
   connect(studentId: string) {
-    const wsUrl = `ws://localhost:8080/ws/${studentId}`;
+    this.closedByUser = false;
+
+    // Resume from the last event we saw so nothing is missed across reconnects
+    const resume = this.lastSeq > 0 ? `?last_seq=${this.lastSeq}` : '';
+    const wsUrl = `ws://localhost:8080/ws/${studentId}${resume}`;
     this.ws = new WebSocket(wsUrl);
 
     this.ws.onopen = () => {
@@ -12,10 +27,14 @@ export class WebSocketService {
     };
 
     this.ws.onmessage = (event) => {
-      const data = JSON.parse(event.data);
+      const data: ServerEvent = JSON.parse(event.data);
+      if (data.seq) {
+        if (data.seq <= this.lastSeq) return; // Already seen
+        this.lastSeq = data.seq;
+      }
       // Handle real-time updates
-        console.log('WebSocket message received:', data);
-        
+      console.log('WebSocket message received:', data);
+      this.listeners.forEach((listener) => listener(data));
     };
 
     this.ws.onerror = (error) => {
@@ -23,6 +42,7 @@ export class WebSocketService {
     };
 
     this.ws.onclose = () => {
+      if (this.closedByUser) return;
       // Auto-reconnect with exponential backoff
       setTimeout(() => {
         this.reconnectTimeout = Math.min(this.reconnectTimeout * 2, 30000);
@@ -32,12 +52,32 @@ export class WebSocketService {
   }
 
   disconnect() {
+    this.closedByUser = true;
     this.ws?.close();
   }
 
+  onEvent(listener: (event: ServerEvent) => void): () => void {
+    this.listeners.push(listener);
+    return () => {
+      this.listeners = this.listeners.filter((l) => l !== listener);
+    };
+  }
+
   send(message: any) {
     if (this.ws?.readyState === WebSocket.OPEN) {
       this.ws.send(JSON.stringify(message));
     }
   }
+
+  sendMessage(message: string, context: unknown) {
+    this.send({ type: 'message', message, context });
+  }
+
+  sendTyping() {
+    this.send({ type: 'typing' });
+  }
+
+  sendIdle() {
+    this.send({ type: 'idle' });
+  }
 }
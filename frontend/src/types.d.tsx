@@ -93,12 +93,132 @@ export interface CoachResponse {
   message: string;
   intervention: InterventionLever | null;
   detected_barriers: StudentBarrier[];
+  etps?: DetectedETP[]; // Emotional trigger points the message sounds like
+  brain_state?: BrainStateEstimate; // Server's reading; replaces the client's brain state
   safeguarding_alert: boolean;
   reward_earned: boolean;
+  reward?: RewardUnlock; // Present when the backend ledger minted a code
+  topic?: string; // Learning topic recognised in the message
+  topics?: TopicMatch[]; // Every curriculum topic recognised, most confident first
+  mastery?: MasteryEvidence; // Only when a topic was recognised
+  question?: QuestionSpec; // How hard the next question is pitched
   reasoning: string[];
   timestamp: string;
 }
 
+// Brain state levels as the API sends them, each 0-1
+export interface BrainStateLevels {
+  primal_level: number;
+  emotional_level: number;
+  rational_level: number;
+  override_risk: number;
+}
+
+// The server's brain state estimate for one message
+export interface BrainStateEstimate {
+  state: BrainStateLevels;
+  signals: string[]; // What in the message moved it, e.g. "shouting (80% capitals)"
+  client?: BrainStateLevels; // What the frontend sent, if anything
+  divergence?: BrainStateLevels; // Estimate minus client, level by level
+  max_divergence?: number;
+}
+
+// An ETP matched by similarity to the barriers' example phrases
+export interface DetectedETP {
+  name: string;
+  confidence: number; // 0-1, lower for ETPs listed later in the barrier's etpActivation
+  nearest: string; // Closest example phrase carrying this ETP
+}
+
+// A curriculum topic recognised in, or carried over to, one message
+export interface TopicMatch {
+  id: string;
+  topic: string; // Name CHISG and mastery are keyed by
+  subject: string;
+  confidence: number; // 0-1
+  terms?: string[]; // What in the message matched
+  position: number; // Word index of the first mention
+  source: 'message' | 'reference' | 'continued';
+}
+
+// What one turn says about the student's grasp of its topic
+export interface MasteryEvidence {
+  topic: string;
+  level: number; // 0-1 estimate the turn was coached at
+  estimated: boolean; // false until the topic has history
+  difficulty: number; // Of the question being answered
+  quality: 'engaged' | 'struggling' | 'paused';
+  score: number; // 1 full attempt, 0.5 brief, 0 struggling
+}
+
+export interface QuestionSpec {
+  difficulty: number; // 0-1
+  semantic_distance: number; // 1-5: jumps between question and answer
+  extrapolation: number; // 0-1: recall vs novel application
+  hint?: string;
+}
+
+// Reply from POST /api/coach/agentic/message: the coach response plus what
+// CHISG knows about the learning topic in the message.
+// A safeguarding reply stops before CHISG is asked, so it has neither
+// framing_strategy nor knowledge_status
+export interface AgenticCoachResponse extends CoachResponse {
+  profile: 'agentic';
+  framing_strategy?: FramingStrategy;
+  knowledge_status?: KnowledgeStatus;
+  knowledge_context?: KnowledgeContext; // Only when knowledge_status is 'ok'
+}
+
+// ok: CHISG answered; no_topic: no learning topic recognised;
+// unavailable: CHISG failed and the reply is emotion-only
+export type KnowledgeStatus = 'ok' | 'no_topic' | 'unavailable';
+
+// Which emotional lens the reply used
+export type FramingStrategy =
+  | 'achievement_with_small_wins' // Anxious: one tiny, certain win first
+  | 'curiosity_building_blocks' // Prerequisite gaps: missing pieces as building blocks
+  | 'status_through_mastery' // Status seeking: mastery as the way to stand out
+  | 'achievement_progress_tracking'; // Default: progress so far and the next step
+
+export interface KnowledgeContext {
+  topic: string;
+  semantic_links: SemanticLink[];
+  suggested_path: LearningPath;
+  prerequisite_gaps: string[]; // What to secure before the topic itself
+}
+
+export interface SemanticLink {
+  from_concept: string;
+  to_concept: string;
+  relationship: string;
+  strength: number; // 0-1
+}
+
+export interface LearningPath {
+  nodes: LearningNode[];
+  prerequisites: string[];
+  difficulty: number; // 0-1
+}
+
+export interface LearningNode {
+  concept: string;
+  description: string;
+  resources: string[];
+}
+
+// Signed unlock code from the backend reward ledger
+export interface RewardUnlock {
+  id: string;
+  student_id: string;
+  code: string;
+  earned_through: string;
+  play_minutes: number;
+  issued_at: string;
+  valid_until: string; // Redeem before this
+  redeemed_at?: string;
+  play_until?: string;
+}
+
 // Reward System
 export interface GameAccessReward {
   unlockCode: string;